@@ -0,0 +1,150 @@
+package accessors
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// The types a device attribute can have
+const (
+	AttributeString = "string"
+	AttributeInt    = "int"
+	AttributeFloat  = "float"
+	AttributeBool   = "bool"
+)
+
+// NormalizeAttribute checks an attribute before it's saved and converts its value to the Go type
+// its type calls for (string, int, float64 or bool). An attribute without a type gets the one
+// its value looks like; a JSON number is an int if it's whole.
+func NormalizeAttribute(attribute structs.DeviceAttribute) (structs.DeviceAttribute, error) {
+	if len(attribute.Name) == 0 {
+		return structs.DeviceAttribute{}, Errorf(Validation, "an attribute needs a name")
+	}
+
+	if len(attribute.Type) == 0 {
+		switch value := attribute.Value.(type) {
+		case string:
+			attribute.Type = AttributeString
+		case bool:
+			attribute.Type = AttributeBool
+		case int:
+			attribute.Type = AttributeInt
+		case float64:
+			attribute.Type = AttributeFloat
+			if value == math.Trunc(value) {
+				attribute.Type = AttributeInt
+			}
+		default:
+			return structs.DeviceAttribute{}, Errorf(Validation, "%s: can't store a value of %v", attribute.Name, attribute.Value)
+		}
+	}
+
+	value, err := convertAttributeValue(attribute.Type, attribute.Value)
+	if err != nil {
+		return structs.DeviceAttribute{}, Errorf(Validation, "%s: %s", attribute.Name, err)
+	}
+	attribute.Value = value
+
+	return attribute, nil
+}
+
+// ParseAttribute makes an attribute out of a value that came in as text, e.g. in a URL. Without
+// a type, a value that reads as a bool, int or float is taken as one.
+func ParseAttribute(name string, attributeType string, text string) (structs.DeviceAttribute, error) {
+	if len(attributeType) == 0 {
+		attributeType = AttributeString
+
+		if text == "true" || text == "false" {
+			attributeType = AttributeBool
+		} else if _, err := strconv.Atoi(text); err == nil {
+			attributeType = AttributeInt
+		} else if _, err := strconv.ParseFloat(text, 64); err == nil {
+			attributeType = AttributeFloat
+		}
+	}
+
+	return NormalizeAttribute(structs.DeviceAttribute{Name: name, Type: attributeType, Value: text})
+}
+
+// EncodeAttributeValue is how an attribute's value is stored: as text, alongside its type
+func EncodeAttributeValue(value interface{}) string {
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+// DecodeAttributeValue reads a stored value back as its type
+func DecodeAttributeValue(attributeType string, text string) (interface{}, error) {
+	return convertAttributeValue(attributeType, text)
+}
+
+func convertAttributeValue(attributeType string, value interface{}) (interface{}, error) {
+	switch attributeType {
+	case AttributeString:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+
+	case AttributeInt:
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case float64:
+			// past 2^53 a float64 can't say which whole number it is
+			if v == math.Trunc(v) && math.Abs(v) <= 1<<53 {
+				return int(v), nil
+			}
+		case string:
+			if i, err := strconv.Atoi(v); err == nil {
+				return i, nil
+			}
+		}
+
+	case AttributeFloat:
+		switch v := value.(type) {
+		case int:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f, nil
+			}
+		}
+
+	case AttributeBool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if v == "true" || v == "false" {
+				return v == "true", nil
+			}
+		}
+
+	default:
+		return nil, unknownAttributeType(attributeType)
+	}
+
+	return nil, fmt.Errorf("%v isn't a valid %s", value, attributeType)
+}
+
+func unknownAttributeType(attributeType string) error {
+	return fmt.Errorf("%q isn't a type; valid types are string, int, float and bool", attributeType)
+}
+
+// AttributeMatches says whether a stored value equals one given as text, e.g. in a query
+// string. The text is read as the stored value's type, so 2.50 matches a float stored as 2.5.
+func AttributeMatches(attributeType string, stored string, text string) bool {
+	value, err := DecodeAttributeValue(attributeType, text)
+	if err != nil {
+		return false
+	}
+
+	return EncodeAttributeValue(value) == stored
+}
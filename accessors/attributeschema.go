@@ -0,0 +1,175 @@
+package accessors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// NormalizeAttributeSchema checks a device type's rule for an attribute, and converts its enum
+// values and default to the attribute's type
+func NormalizeAttributeSchema(rule structs.DeviceTypeAttribute) (structs.DeviceTypeAttribute, error) {
+	if len(rule.Name) == 0 {
+		return structs.DeviceTypeAttribute{}, Errorf(Validation, "an attribute needs a name")
+	}
+
+	switch rule.Type {
+	case AttributeString, AttributeBool:
+		if rule.Minimum != nil || rule.Maximum != nil {
+			return structs.DeviceTypeAttribute{}, Errorf(Validation, "%s: only int and float attributes can have a minimum or maximum", rule.Name)
+		}
+	case AttributeInt, AttributeFloat:
+		if rule.Minimum != nil && rule.Maximum != nil && *rule.Minimum > *rule.Maximum {
+			return structs.DeviceTypeAttribute{}, Errorf(Validation, "%s: the minimum is more than the maximum", rule.Name)
+		}
+	default:
+		return structs.DeviceTypeAttribute{}, Errorf(Validation, "%s: %s", rule.Name, unknownAttributeType(rule.Type))
+	}
+
+	var enum []interface{}
+	for _, value := range rule.Enum {
+		converted, err := convertAttributeValue(rule.Type, value)
+		if err != nil {
+			return structs.DeviceTypeAttribute{}, Errorf(Validation, "%s: enum: %s", rule.Name, err)
+		}
+
+		enum = append(enum, converted)
+	}
+	rule.Enum = enum
+
+	if rule.Default != nil {
+		value, err := convertAttributeValue(rule.Type, rule.Default)
+		if err == nil {
+			err = checkAttributeRule(rule, value)
+		}
+		if err != nil {
+			return structs.DeviceTypeAttribute{}, Errorf(Validation, "%s: default: %s", rule.Name, err)
+		}
+
+		rule.Default = value
+	}
+
+	return rule, nil
+}
+
+// NormalizeAttributeSchemas normalizes every rule in a whole schema, which can't name an
+// attribute twice
+func NormalizeAttributeSchemas(schema []structs.DeviceTypeAttribute) ([]structs.DeviceTypeAttribute, error) {
+	rules := []structs.DeviceTypeAttribute{}
+	seen := make(map[string]bool)
+
+	for _, rule := range schema {
+		normalized, err := NormalizeAttributeSchema(rule)
+		if err != nil {
+			return []structs.DeviceTypeAttribute{}, err
+		}
+
+		if seen[normalized.Name] {
+			return []structs.DeviceTypeAttribute{}, Errorf(Validation, "%s is in the schema more than once", normalized.Name)
+		}
+		seen[normalized.Name] = true
+
+		rules = append(rules, normalized)
+	}
+
+	return rules, nil
+}
+
+// ValidateAttribute checks an attribute against the schema of the device type it's being set on,
+// and normalizes it. A type without a schema takes any attribute; one with a schema only takes
+// the attributes it lists, as the type it gives them, within their ranges and enums. An
+// attribute without a type gets the one the schema gives it.
+func ValidateAttribute(typeName string, schema []structs.DeviceTypeAttribute, attribute structs.DeviceAttribute) (structs.DeviceAttribute, error) {
+	if len(schema) == 0 || len(attribute.Name) == 0 {
+		return NormalizeAttribute(attribute)
+	}
+
+	rule, ok := schemaRule(schema, attribute.Name)
+	if !ok {
+		names := []string{}
+		for _, rule := range schema {
+			names = append(names, rule.Name)
+		}
+
+		return structs.DeviceAttribute{}, Errorf(Validation, "%s devices can't have the attribute %s; their type allows %s", typeName, attribute.Name, strings.Join(names, ", "))
+	}
+
+	if len(attribute.Type) == 0 {
+		attribute.Type = rule.Type
+	} else if attribute.Type != rule.Type {
+		return structs.DeviceAttribute{}, Errorf(Validation, "%s: %s devices store it as type %s, not %s", attribute.Name, typeName, rule.Type, attribute.Type)
+	}
+
+	attribute, err := NormalizeAttribute(attribute)
+	if err != nil {
+		return structs.DeviceAttribute{}, err
+	}
+
+	err = checkAttributeRule(rule, attribute.Value)
+	if err != nil {
+		return structs.DeviceAttribute{}, Errorf(Validation, "%s: %s for %s devices", attribute.Name, err, typeName)
+	}
+
+	return attribute, nil
+}
+
+// AttributeDefaults returns the attributes a device of a type reads as having but hasn't set:
+// the defaults in its type's schema for the names not in set
+func AttributeDefaults(schema []structs.DeviceTypeAttribute, set map[string]bool) []structs.DeviceAttribute {
+	defaults := []structs.DeviceAttribute{}
+
+	for _, rule := range schema {
+		if rule.Default == nil || set[rule.Name] {
+			continue
+		}
+
+		defaults = append(defaults, structs.DeviceAttribute{Name: rule.Name, Type: rule.Type, Value: rule.Default, IsDefault: true})
+	}
+
+	return defaults
+}
+
+func schemaRule(schema []structs.DeviceTypeAttribute, name string) (structs.DeviceTypeAttribute, bool) {
+	for _, rule := range schema {
+		if rule.Name == name {
+			return rule, true
+		}
+	}
+
+	return structs.DeviceTypeAttribute{}, false
+}
+
+// checkAttributeRule checks a value, already of the rule's type, against its range and enum
+func checkAttributeRule(rule structs.DeviceTypeAttribute, value interface{}) error {
+	var number float64
+	switch v := value.(type) {
+	case int:
+		number = float64(v)
+	case float64:
+		number = v
+	}
+
+	if rule.Minimum != nil && number < *rule.Minimum {
+		return fmt.Errorf("%v is below the minimum of %s", value, EncodeAttributeValue(*rule.Minimum))
+	}
+
+	if rule.Maximum != nil && number > *rule.Maximum {
+		return fmt.Errorf("%v is above the maximum of %s", value, EncodeAttributeValue(*rule.Maximum))
+	}
+
+	if len(rule.Enum) == 0 {
+		return nil
+	}
+
+	allowed := []string{}
+	for _, option := range rule.Enum {
+		if EncodeAttributeValue(option) == EncodeAttributeValue(value) {
+			return nil
+		}
+
+		allowed = append(allowed, EncodeAttributeValue(option))
+	}
+
+	return fmt.Errorf("%v isn't one of %s", value, strings.Join(allowed, ", "))
+}
@@ -0,0 +1,138 @@
+package accessors
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+func float(f float64) *float64 {
+	return &f
+}
+
+func TestNormalizeAttributeSchema(t *testing.T) {
+	tests := []struct {
+		name string
+		rule structs.DeviceTypeAttribute
+		// want is the rule that comes back, or nil if it's refused
+		want *structs.DeviceTypeAttribute
+	}{
+		{
+			name: "a range",
+			rule: structs.DeviceTypeAttribute{Name: "volume", Type: AttributeInt, Minimum: float(0), Maximum: float(100), Default: 30.0},
+			want: &structs.DeviceTypeAttribute{Name: "volume", Type: AttributeInt, Minimum: float(0), Maximum: float(100), Default: 30},
+		},
+		{
+			name: "an enum, as JSON decodes it",
+			rule: structs.DeviceTypeAttribute{Name: "inputDelay", Type: AttributeInt, Enum: []interface{}{0.0, 50.0}, Default: 50.0},
+			want: &structs.DeviceTypeAttribute{Name: "inputDelay", Type: AttributeInt, Enum: []interface{}{0, 50}, Default: 50},
+		},
+		{
+			name: "a string enum",
+			rule: structs.DeviceTypeAttribute{Name: "aspect", Type: AttributeString, Enum: []interface{}{"16:9", "4:3"}},
+			want: &structs.DeviceTypeAttribute{Name: "aspect", Type: AttributeString, Enum: []interface{}{"16:9", "4:3"}},
+		},
+		{name: "without a name", rule: structs.DeviceTypeAttribute{Type: AttributeInt}},
+		{name: "an unknown type", rule: structs.DeviceTypeAttribute{Name: "volume", Type: "percent"}},
+		{name: "a range on a string", rule: structs.DeviceTypeAttribute{Name: "aspect", Type: AttributeString, Minimum: float(0)}},
+		{name: "a range on a bool", rule: structs.DeviceTypeAttribute{Name: "muted", Type: AttributeBool, Maximum: float(1)}},
+		{name: "a minimum over the maximum", rule: structs.DeviceTypeAttribute{Name: "volume", Type: AttributeInt, Minimum: float(100), Maximum: float(0)}},
+		{name: "an enum value of another type", rule: structs.DeviceTypeAttribute{Name: "inputDelay", Type: AttributeInt, Enum: []interface{}{0.0, "fast"}}},
+		{name: "a default below the minimum", rule: structs.DeviceTypeAttribute{Name: "volume", Type: AttributeInt, Minimum: float(0), Default: -1.0}},
+		{name: "a default above the maximum", rule: structs.DeviceTypeAttribute{Name: "volume", Type: AttributeInt, Maximum: float(100), Default: 101.0}},
+		{name: "a default outside the enum", rule: structs.DeviceTypeAttribute{Name: "aspect", Type: AttributeString, Enum: []interface{}{"16:9"}, Default: "4:3"}},
+		{name: "a default of another type", rule: structs.DeviceTypeAttribute{Name: "muted", Type: AttributeBool, Default: "no"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := NormalizeAttributeSchema(test.rule)
+			if test.want == nil {
+				if KindOf(err) != Validation {
+					t.Errorf("got %+v, %v, want a Validation error", rule, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(rule, *test.want) {
+				t.Errorf("got %+v, want %+v", rule, *test.want)
+			}
+		})
+	}
+}
+
+func TestValidateAttribute(t *testing.T) {
+	schema := []structs.DeviceTypeAttribute{
+		{Name: "volume", Type: AttributeInt, Minimum: float(0), Maximum: float(100)},
+		{Name: "gain", Type: AttributeFloat, Minimum: float(-1.5), Maximum: float(1.5)},
+		{Name: "aspect", Type: AttributeString, Enum: []interface{}{"16:9", "4:3"}},
+	}
+
+	tests := []struct {
+		name      string
+		schema    []structs.DeviceTypeAttribute
+		attribute structs.DeviceAttribute
+		// want is the attribute that comes back, or nil if it's refused
+		want *structs.DeviceAttribute
+	}{
+		{
+			name:      "any attribute without a schema",
+			attribute: structs.DeviceAttribute{Name: "anything", Value: "goes"},
+			want:      &structs.DeviceAttribute{Name: "anything", Type: AttributeString, Value: "goes"},
+		},
+		{
+			name:      "takes its type from the schema",
+			schema:    schema,
+			attribute: structs.DeviceAttribute{Name: "gain", Value: 1.0},
+			want:      &structs.DeviceAttribute{Name: "gain", Type: AttributeFloat, Value: 1.0},
+		},
+		{
+			name:      "at the minimum",
+			schema:    schema,
+			attribute: structs.DeviceAttribute{Name: "volume", Value: 0.0},
+			want:      &structs.DeviceAttribute{Name: "volume", Type: AttributeInt, Value: 0},
+		},
+		{
+			name:      "at the maximum",
+			schema:    schema,
+			attribute: structs.DeviceAttribute{Name: "volume", Type: AttributeInt, Value: "100"},
+			want:      &structs.DeviceAttribute{Name: "volume", Type: AttributeInt, Value: 100},
+		},
+		{
+			name:      "in the enum",
+			schema:    schema,
+			attribute: structs.DeviceAttribute{Name: "aspect", Value: "4:3"},
+			want:      &structs.DeviceAttribute{Name: "aspect", Type: AttributeString, Value: "4:3"},
+		},
+		{name: "below the minimum", schema: schema, attribute: structs.DeviceAttribute{Name: "volume", Value: -1.0}},
+		{name: "above the maximum", schema: schema, attribute: structs.DeviceAttribute{Name: "volume", Value: 101.0}},
+		{name: "a float below the minimum", schema: schema, attribute: structs.DeviceAttribute{Name: "gain", Value: -1.6}},
+		{name: "outside the enum", schema: schema, attribute: structs.DeviceAttribute{Name: "aspect", Value: "21:9"}},
+		{name: "not in the schema", schema: schema, attribute: structs.DeviceAttribute{Name: "inputDelay", Value: 0.0}},
+		{name: "as another type", schema: schema, attribute: structs.DeviceAttribute{Name: "volume", Type: AttributeFloat, Value: 1.5}},
+		{name: "a value that isn't the type", schema: schema, attribute: structs.DeviceAttribute{Name: "volume", Value: "loud"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			attribute, err := ValidateAttribute("SonyXBR", test.schema, test.attribute)
+			if test.want == nil {
+				if KindOf(err) != Validation {
+					t.Errorf("got %+v, %v, want a Validation error", attribute, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(attribute, *test.want) {
+				t.Errorf("got %+v, want %+v", attribute, *test.want)
+			}
+		})
+	}
+}
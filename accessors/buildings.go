@@ -1,12 +1,17 @@
 package accessors
 
-import "github.com/byuoitav/configuration-database-microservice/structs"
+import (
+	"context"
+	"fmt"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
 
 // GetAllBuildings returns a list of buildings from the database
-func (accessorGroup *AccessorGroup) GetAllBuildings() ([]structs.Building, error) {
+func (accessorGroup *AccessorGroup) GetAllBuildings(ctx context.Context) ([]structs.Building, error) {
 	allBuildings := []structs.Building{}
 
-	rows, err := accessorGroup.Database.Query("SELECT * FROM Buildings")
+	rows, err := accessorGroup.db().QueryContext(ctx, "SELECT * FROM Buildings")
 	if err != nil {
 		return []structs.Building{}, err
 	}
@@ -33,9 +38,9 @@ func (accessorGroup *AccessorGroup) GetAllBuildings() ([]structs.Building, error
 }
 
 // GetBuildingByID returns a building from the database by ID
-func (accessorGroup *AccessorGroup) GetBuildingByID(id int) (structs.Building, error) {
+func (accessorGroup *AccessorGroup) GetBuildingByID(ctx context.Context, id int) (structs.Building, error) {
 	building := &structs.Building{}
-	err := accessorGroup.Database.QueryRow("SELECT * FROM Buildings WHERE buildingID=?", id).Scan(&building.ID, &building.Name, &building.Shortname, &building.Description)
+	err := accessorGroup.db().QueryRowContext(ctx, "SELECT * FROM Buildings WHERE buildingID=?", id).Scan(&building.ID, &building.Name, &building.Shortname, &building.Description)
 	if err != nil {
 		return structs.Building{}, err
 	}
@@ -44,9 +49,9 @@ func (accessorGroup *AccessorGroup) GetBuildingByID(id int) (structs.Building, e
 }
 
 // GetBuildingByShortname returns a building from the database by shortname
-func (accessorGroup *AccessorGroup) GetBuildingByShortname(shortname string) (structs.Building, error) {
+func (accessorGroup *AccessorGroup) GetBuildingByShortname(ctx context.Context, shortname string) (structs.Building, error) {
 	building := &structs.Building{}
-	err := accessorGroup.Database.QueryRow("SELECT * FROM Buildings WHERE shortname=?", shortname).Scan(&building.ID, &building.Name, &building.Shortname, &building.Description)
+	err := accessorGroup.db().QueryRowContext(ctx, "SELECT * FROM Buildings WHERE shortname=?", shortname).Scan(&building.ID, &building.Name, &building.Shortname, &building.Description)
 	if err != nil {
 		return structs.Building{}, err
 	}
@@ -56,9 +61,9 @@ func (accessorGroup *AccessorGroup) GetBuildingByShortname(shortname string) (st
 
 //AddBuilding adds a building
 
-func (accessorGroup *AccessorGroup) AddBuilding(name string, shortname string, description string) (structs.Building, error) {
+func (accessorGroup *AccessorGroup) AddBuilding(ctx context.Context, name string, shortname string, description string) (structs.Building, error) {
 
-	result, err := accessorGroup.Database.Exec(`INSERT into Buildings (name, shortname, description) VALUES (?,?,?)`, name, shortname, description)
+	result, err := accessorGroup.db().ExecContext(ctx, `INSERT into Buildings (name, shortname, description) VALUES (?,?,?)`, name, shortname, description)
 	if err != nil {
 		return structs.Building{}, err
 	}
@@ -77,3 +82,126 @@ func (accessorGroup *AccessorGroup) AddBuilding(name string, shortname string, d
 
 	return building, err
 }
+
+// UpdateBuilding replaces the name, shortname and description of the building with the given
+// shortname. An empty shortname in building keeps the current one.
+func (accessorGroup *AccessorGroup) UpdateBuilding(ctx context.Context, shortname string, building structs.Building) (updated structs.Building, err error) {
+	if len(building.Name) == 0 {
+		return structs.Building{}, Errorf(Validation, "a building needs a name")
+	}
+
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		current, err := tx.GetBuildingByShortname(ctx, shortname)
+		if err != nil {
+			return err
+		}
+
+		if len(building.Shortname) == 0 {
+			building.Shortname = current.Shortname
+		}
+
+		if building.Shortname != current.Shortname {
+			_, err = tx.GetBuildingByShortname(ctx, building.Shortname)
+			if err == nil {
+				return Errorf(Conflict, "there is already a building with the shortname %s", building.Shortname)
+			} else if KindOf(err) != NotFound {
+				return err
+			}
+		}
+
+		_, err = tx.db().ExecContext(ctx, "UPDATE Buildings SET name = ?, shortName = ?, description = ? WHERE buildingID = ?", building.Name, building.Shortname, building.Description, current.ID)
+		if err != nil {
+			return err
+		}
+
+		updated, err = tx.GetBuildingByID(ctx, current.ID)
+		return err
+	})
+
+	return updated, err
+}
+
+// DeleteBuilding deletes the building with the given shortname. If rooms or devices are still
+// in it, it refuses with a Conflict listing them, unless cascade is set, in which case they're
+// deleted too.
+func (accessorGroup *AccessorGroup) DeleteBuilding(ctx context.Context, shortname string, cascade bool) error {
+	return accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		building, err := tx.GetBuildingByShortname(ctx, shortname)
+		if err != nil {
+			return err
+		}
+
+		if !cascade {
+			blockers, err := tx.buildingBlockers(ctx, building)
+			if err != nil {
+				return err
+			}
+
+			if len(blockers) > 0 {
+				return &Error{
+					Kind:    Conflict,
+					Err:     fmt.Errorf("building %s still has rooms or devices; delete them first or pass cascade=true", shortname),
+					Details: blockers,
+				}
+			}
+		}
+
+		// devices are found through the building's rooms, since their own buildingID can be stale
+		err = tx.deleteDevicesWhere(ctx, "roomID IN (SELECT roomID FROM Rooms WHERE buildingID = ?)", building.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.db().ExecContext(ctx, "DELETE FROM RoomPromotions WHERE roomID IN (SELECT roomID FROM Rooms WHERE buildingID = ?)", building.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.db().ExecContext(ctx, "DELETE FROM Rooms WHERE buildingID = ?", building.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.db().ExecContext(ctx, "DELETE FROM Buildings WHERE buildingID = ?", building.ID)
+		return err
+	})
+}
+
+// buildingBlockers lists the rooms and devices that keep a building from being deleted
+func (accessorGroup *AccessorGroup) buildingBlockers(ctx context.Context, building structs.Building) ([]string, error) {
+	rooms, err := accessorGroup.names(ctx, "SELECT name FROM Rooms WHERE buildingID = ? ORDER BY name", building.ID)
+	if err != nil {
+		return []string{}, err
+	}
+
+	blockers := []string{}
+	for _, room := range rooms {
+		blockers = append(blockers, fmt.Sprintf("room %s-%s", building.Shortname, room))
+	}
+
+	rows, err := accessorGroup.db().QueryContext(ctx, `SELECT Rooms.name, Devices.name FROM Devices
+		JOIN Rooms ON Rooms.roomID = Devices.roomID
+		WHERE Rooms.buildingID = ? ORDER BY Rooms.name, Devices.name`, building.ID)
+	if err != nil {
+		return []string{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var room, device string
+
+		err = rows.Scan(&room, &device)
+		if err != nil {
+			return []string{}, err
+		}
+
+		blockers = append(blockers, fmt.Sprintf("device %s-%s-%s", building.Shortname, room, device))
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return []string{}, err
+	}
+
+	return blockers, nil
+}
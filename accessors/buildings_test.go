@@ -0,0 +1,136 @@
+package accessors_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+func TestUpdateBuilding(t *testing.T) {
+	tests := []struct {
+		name      string
+		shortname string
+		building  structs.Building
+		kind      accessors.Kind
+		// want is the shortname the building ends up with
+		want string
+	}{
+		{name: "rename", shortname: "ITB", building: structs.Building{Name: "IT Building", Shortname: "ITB2"}, kind: succeeds, want: "ITB2"},
+		{name: "keep the shortname", shortname: "ITB", building: structs.Building{Name: "IT Building"}, kind: succeeds, want: "ITB"},
+		{name: "onto a taken shortname", shortname: "ITB", building: structs.Building{Name: "IT Building", Shortname: "TMCB"}, kind: accessors.Conflict},
+		{name: "without a name", shortname: "ITB", building: structs.Building{Shortname: "ITB"}, kind: accessors.Validation},
+		{name: "a missing building", shortname: "HBLL", building: structs.Building{Name: "Library"}, kind: accessors.NotFound},
+	}
+
+	for store, newStore := range testStores {
+		for _, test := range tests {
+			t.Run(store+"/"+test.name, func(t *testing.T) {
+				ctx := context.Background()
+				s, done := newStore(t)
+				defer done()
+
+				_, err := s.AddBuilding(ctx, "Talmage", "TMCB", "")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				updated, err := s.UpdateBuilding(ctx, test.shortname, test.building)
+				checkKind(t, "UpdateBuilding", err, test.kind)
+				if test.kind != succeeds {
+					return
+				}
+
+				if updated.Shortname != test.want || updated.Name != test.building.Name {
+					t.Errorf("updated to %+v, want %s named %s", updated, test.want, test.building.Name)
+				}
+
+				// the rooms stay with the building through a rename
+				rooms, err := s.GetRoomsByBuilding(ctx, test.want)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(rooms) != 2 {
+					t.Errorf("%s has %d rooms after the update, want 2", test.want, len(rooms))
+				}
+			})
+		}
+	}
+}
+
+func TestDeleteBuilding(t *testing.T) {
+	tests := []struct {
+		name      string
+		shortname string
+		cascade   bool
+		kind      accessors.Kind
+		// details are what a Conflict lists
+		details []string
+	}{
+		{
+			name:      "with rooms and devices",
+			shortname: "ITB",
+			kind:      accessors.Conflict,
+			details:   []string{"room ITB-1001D", "room ITB-1101", "device ITB-1001D-D1", "device ITB-1101-CP1", "device ITB-1101-D1", "device ITB-1101-PC1"},
+		},
+		{name: "cascade", shortname: "ITB", cascade: true, kind: succeeds},
+		{name: "an empty building", shortname: "TMCB", kind: succeeds},
+		{name: "a missing building", shortname: "HBLL", kind: accessors.NotFound},
+		{name: "cascade a missing building", shortname: "HBLL", cascade: true, kind: accessors.NotFound},
+	}
+
+	for store, newStore := range testStores {
+		for _, test := range tests {
+			t.Run(store+"/"+test.name, func(t *testing.T) {
+				ctx := context.Background()
+				s, done := newStore(t)
+				defer done()
+
+				_, err := s.AddBuilding(ctx, "Talmage", "TMCB", "")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = s.DeleteBuilding(ctx, test.shortname, test.cascade)
+				checkKind(t, "DeleteBuilding", err, test.kind)
+
+				if test.details != nil {
+					accessorError, ok := err.(*accessors.Error)
+					if !ok || !reflect.DeepEqual(accessorError.Details, test.details) {
+						t.Errorf("got %#v, want details %q", err, test.details)
+					}
+				}
+
+				_, err = s.GetBuildingByShortname(ctx, test.shortname)
+				if test.kind == succeeds {
+					checkKind(t, "GetBuildingByShortname after deleting", err, accessors.NotFound)
+				} else if test.kind != accessors.NotFound {
+					checkKind(t, "GetBuildingByShortname after failing to delete", err, succeeds)
+				}
+
+				devices, err := s.GetAllDevices(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				ports, err := s.GetAllPortConfiguration(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// only cascading ITB's delete takes its devices and their ports with it
+				wantDevices, wantPorts := 4, 1
+				if test.shortname == "ITB" && test.cascade {
+					wantDevices, wantPorts = 0, 0
+				}
+				if len(devices) != wantDevices {
+					t.Errorf("%d devices left, want %d", len(devices), wantDevices)
+				}
+				if len(ports) != wantPorts {
+					t.Errorf("%d port configurations left, want %d", len(ports), wantPorts)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package accessors
+
+import (
+	"context"
+	"fmt"
+)
+
+// deviceReferences are the columns, outside Devices itself, that point at a device
+var deviceReferences = []struct {
+	table  string
+	column string
+}{
+	{"DeviceRole", "deviceID"},
+	{"DevicePowerStates", "deviceID"},
+	{"DeviceCommands", "deviceID"},
+	{"AudioDevices", "deviceID"},
+	{"DeviceAttributes", "deviceID"},
+	{"Displays", "deviceID"},
+	{"PortConfiguration", "sourceDeviceID"},
+	{"PortConfiguration", "destinationDeviceID"},
+	{"PortConfiguration", "hostDeviceID"},
+}
+
+// deleteDevicesWhere deletes the devices matching condition (a WHERE clause on Devices, without
+// the WHERE) along with every row that points at them. Run it inside a transaction.
+func (accessorGroup *AccessorGroup) deleteDevicesWhere(ctx context.Context, condition string, args ...interface{}) error {
+	for _, reference := range deviceReferences {
+		query := fmt.Sprintf("DELETE FROM %s WHERE %s IN (SELECT deviceID FROM Devices WHERE %s)", reference.table, reference.column, condition)
+
+		_, err := accessorGroup.db().ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := accessorGroup.db().ExecContext(ctx, "DELETE FROM Devices WHERE "+condition, args...)
+	return err
+}
@@ -1,6 +1,7 @@
 package accessors
 
 import (
+	"context"
 	"database/sql"
 	"log"
 
@@ -8,9 +9,9 @@ import (
 )
 
 //GetAllCommands simply dumps the commands table
-func (accessorGroup *AccessorGroup) GetAllCommands() (commands []structs.RawCommand, err error) {
+func (accessorGroup *AccessorGroup) GetAllCommands(ctx context.Context) (commands []structs.RawCommand, err error) {
 	log.Printf("Getting all commands...")
-	rows, err := accessorGroup.Database.Query("Select * FROM Commands")
+	rows, err := accessorGroup.db().QueryContext(ctx, "Select * FROM Commands")
 	if err != nil {
 		log.Printf("Error: %s", err.Error())
 		return
@@ -22,8 +23,8 @@ func (accessorGroup *AccessorGroup) GetAllCommands() (commands []structs.RawComm
 	return
 }
 
-func (accessorGroup *AccessorGroup) GetRawCommandByName(name string) (structs.RawCommand, error) {
-	row := accessorGroup.Database.QueryRow("SELECT * FROM Commands WHERE name = ? ", name)
+func (accessorGroup *AccessorGroup) GetRawCommandByName(ctx context.Context, name string) (structs.RawCommand, error) {
+	row := accessorGroup.db().QueryRowContext(ctx, "SELECT * FROM Commands WHERE name = ? ", name)
 
 	rc, err := extractRawCommand(row)
 	if err != nil {
@@ -69,8 +70,8 @@ func ExtractRawCommands(rows *sql.Rows) (allCommands []structs.RawCommand, err e
 	return
 }
 
-func (accessorGroup *AccessorGroup) AddRawCommand(rc structs.RawCommand) (structs.RawCommand, error) {
-	result, err := accessorGroup.Database.Exec("Insert into Commands (commandID, name, description, priority) VALUES(?,?,?,?)", rc.ID, rc.Name, rc.Description, rc.Priority)
+func (accessorGroup *AccessorGroup) AddRawCommand(ctx context.Context, rc structs.RawCommand) (structs.RawCommand, error) {
+	result, err := accessorGroup.db().ExecContext(ctx, "Insert into Commands (commandID, name, description, priority) VALUES(?,?,?,?)", cleanID(rc.ID), rc.Name, rc.Description, rc.Priority)
 	if err != nil {
 		return structs.RawCommand{}, err
 	}
@@ -1,6 +1,7 @@
 package accessors
 
 import (
+	"context"
 	"database/sql"
 	"log"
 
@@ -8,27 +9,27 @@ import (
 )
 
 //GetConfigurationByRoomAndBuilding will get the configuration information tied to a given room.
-func (accessorGroup *AccessorGroup) GetConfigurationByRoomAndBuilding(building string, room string) (toReturn structs.RoomConfiguration, err error) {
+func (accessorGroup *AccessorGroup) GetConfigurationByRoomAndBuilding(ctx context.Context, building string, room string) (toReturn structs.RoomConfiguration, err error) {
 
-	rm, err := accessorGroup.GetRoomByBuildingAndName(building, room)
+	rm, err := accessorGroup.GetRoomByBuildingAndName(ctx, building, room)
 	if err != nil {
 		return
 	}
 
-	toReturn, err = accessorGroup.GetConfigurationByConfigurationID(rm.ConfigurationID)
+	toReturn, err = accessorGroup.GetConfigurationByConfigurationID(ctx, rm.ConfigurationID)
 	return
 }
 
 //GetConfigurationByConfigurationName gets a configuraiton by name.
-func (accessorGroup *AccessorGroup) GetConfigurationByConfigurationName(name string) (config structs.RoomConfiguration, err error) {
-	config, err = accessorGroup.GetConfigurationByQuery(`WHERE name = ?`, name)
+func (accessorGroup *AccessorGroup) GetConfigurationByConfigurationName(ctx context.Context, name string) (config structs.RoomConfiguration, err error) {
+	config, err = accessorGroup.GetConfigurationByQuery(ctx, `WHERE name = ?`, name)
 	return
 }
 
 //GetConfigurationByConfigurationID gets a room configuration by it's ID, and fills the commands
 //struct with the relevant ConfigurationEvaluators
-func (accessorGroup *AccessorGroup) GetConfigurationByConfigurationID(configurationID int) (config structs.RoomConfiguration, err error) {
-	config, err = accessorGroup.GetConfigurationByQuery(`WHERE roomConfigurationID = ?`, configurationID)
+func (accessorGroup *AccessorGroup) GetConfigurationByConfigurationID(ctx context.Context, configurationID int) (config structs.RoomConfiguration, err error) {
+	config, err = accessorGroup.GetConfigurationByQuery(ctx, `WHERE roomConfigurationID = ?`, configurationID)
 	return
 
 }
@@ -37,7 +38,7 @@ func (accessorGroup *AccessorGroup) GetConfigurationByConfigurationID(configurat
 //You provide a WHERE statement to append to the base query, essentially allowing you to
 //get any subset of information without duplicaiton of the necessary actions to extact and
 //fill the data. Note that this is meant to only access the TOP 1 of any objects returned.
-func (accessorGroup *AccessorGroup) GetConfigurationByQuery(queryAddition string, params ...interface{}) (config structs.RoomConfiguration, err error) {
+func (accessorGroup *AccessorGroup) GetConfigurationByQuery(ctx context.Context, queryAddition string, params ...interface{}) (config structs.RoomConfiguration, err error) {
 	baseQuery := `
 	Select roomConfigurationID, name, description, roomConfigurationKey, roomInitializationKey
 	FROM RoomConfiguration
@@ -46,7 +47,7 @@ func (accessorGroup *AccessorGroup) GetConfigurationByQuery(queryAddition string
 	LIMIT 1
 	`
 
-	rows, err := accessorGroup.Database.Query(baseQuery+" "+queryAddition+" "+limit, params...)
+	rows, err := accessorGroup.db().QueryContext(ctx, baseQuery+" "+queryAddition+" "+limit, params...)
 	if err != nil {
 		return
 	}
@@ -56,21 +57,23 @@ func (accessorGroup *AccessorGroup) GetConfigurationByQuery(queryAddition string
 	if err != nil {
 		return
 	}
+	rows.Close() // so the evaluators can be read on the same connection
 
-	config.Evaluators, err = accessorGroup.GetEvaluatorsForConfigurationByID(config.ID)
+	config.Evaluators, err = accessorGroup.GetEvaluatorsForConfigurationByID(ctx, config.ID)
 
 	return
 }
 
 //GetEvaluatorsForConfigurationByID gets the elements form the vConfiguraitonMapping table for a given configurationID
-func (accessorGroup *AccessorGroup) GetEvaluatorsForConfigurationByID(configurationID int) (allEvaluators []structs.ConfigurationEvaluator, err error) {
+func (accessorGroup *AccessorGroup) GetEvaluatorsForConfigurationByID(ctx context.Context, configurationID int) (allEvaluators []structs.ConfigurationEvaluator, err error) {
 	//Get configuration commands
 	query := `
 	Select EvaluatorKey, Priority
 	FROM vConfigurationMapping
-	WHERE ConfigurationID = ?`
+	WHERE ConfigurationID = ?
+	ORDER BY Priority, EvaluatorKey`
 
-	rows, err := accessorGroup.Database.Query(query, configurationID)
+	rows, err := accessorGroup.db().QueryContext(ctx, query, configurationID)
 	if err != nil {
 		return
 	}
@@ -81,8 +84,8 @@ func (accessorGroup *AccessorGroup) GetEvaluatorsForConfigurationByID(configurat
 	return
 }
 
-func (accessorGroup *AccessorGroup) GetConfigurations() ([]structs.RoomConfiguration, error) {
-	rows, err := accessorGroup.Database.Query("SELECT * FROM RoomConfiguration")
+func (accessorGroup *AccessorGroup) GetConfigurations(ctx context.Context) ([]structs.RoomConfiguration, error) {
+	rows, err := accessorGroup.db().QueryContext(ctx, "SELECT roomConfigurationID, name, roomConfigurationKey, description, roomInitializationKey FROM RoomConfiguration")
 	if err != nil {
 		return []structs.RoomConfiguration{}, err
 	}
@@ -132,7 +135,13 @@ func extractRoomConfigurations(rows *sql.Rows) ([]structs.RoomConfiguration, err
 
 //ExtractRoomConfiguration pulls the items from the row to fill the config item.
 func (accessorGroup *AccessorGroup) ExtractRoomConfiguration(rows *sql.Rows) (config structs.RoomConfiguration, err error) {
-	rows.Next()
+	if !rows.Next() {
+		err = rows.Err()
+		if err == nil {
+			err = sql.ErrNoRows
+		}
+		return
+	}
 
 	err = rows.Scan(&config.ID, &config.Name, &config.Description, &config.RoomKey, &config.RoomInitKey)
 
@@ -156,3 +165,208 @@ func (accessorGroup *AccessorGroup) ExtractConfigurationEvaluator(rows *sql.Rows
 
 	return
 }
+
+// AddConfiguration adds a room configuration, along with any evaluators it comes with. Names
+// have to be unique, since that's how rooms and the API find a configuration.
+func (accessorGroup *AccessorGroup) AddConfiguration(ctx context.Context, config structs.RoomConfiguration) (added structs.RoomConfiguration, err error) {
+	if len(config.Name) == 0 {
+		return structs.RoomConfiguration{}, Errorf(Validation, "a room configuration needs a name")
+	}
+
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		_, err := tx.configurationID(ctx, config.Name)
+		if err == nil {
+			return Errorf(Conflict, "there is already a room configuration called %s", config.Name)
+		} else if KindOf(err) != NotFound {
+			return err
+		}
+
+		result, err := tx.db().ExecContext(ctx, "INSERT INTO RoomConfiguration (name, description, roomConfigurationKey, roomInitializationKey) VALUES (?, ?, ?, ?)",
+			config.Name, config.Description, config.RoomKey, config.RoomInitKey)
+		if err != nil {
+			return err
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		for _, evaluator := range config.Evaluators {
+			err = tx.addConfigurationMapping(ctx, int(id), config.Name, evaluator)
+			if err != nil {
+				return err
+			}
+		}
+
+		added, err = tx.GetConfigurationByConfigurationID(ctx, int(id))
+		return err
+	})
+
+	return added, err
+}
+
+// UpdateConfiguration changes a room configuration's keys and description, or renames it. An
+// empty name, room key or room init key keeps the current value. Evaluators left out (null)
+// are left alone; a list replaces them all, so an empty one clears them.
+func (accessorGroup *AccessorGroup) UpdateConfiguration(ctx context.Context, name string, config structs.RoomConfiguration) (updated structs.RoomConfiguration, err error) {
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		id, err := tx.configurationID(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		current, err := tx.GetConfigurationByConfigurationID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if len(config.Name) == 0 {
+			config.Name = current.Name
+		}
+		if len(config.RoomKey) == 0 {
+			config.RoomKey = current.RoomKey
+		}
+		if len(config.RoomInitKey) == 0 {
+			config.RoomInitKey = current.RoomInitKey
+		}
+
+		if config.Name != current.Name {
+			_, err = tx.configurationID(ctx, config.Name)
+			if err == nil {
+				return Errorf(Conflict, "there is already a room configuration called %s", config.Name)
+			} else if KindOf(err) != NotFound {
+				return err
+			}
+		}
+
+		_, err = tx.db().ExecContext(ctx, "UPDATE RoomConfiguration SET name = ?, description = ?, roomConfigurationKey = ?, roomInitializationKey = ? WHERE roomConfigurationID = ?",
+			config.Name, config.Description, config.RoomKey, config.RoomInitKey, id)
+		if err != nil {
+			return err
+		}
+
+		if config.Evaluators != nil {
+			_, err = tx.db().ExecContext(ctx, "DELETE FROM RoomConfigurationMapping WHERE roomConfigurationID = ?", id)
+			if err != nil {
+				return err
+			}
+
+			for _, evaluator := range config.Evaluators {
+				err = tx.addConfigurationMapping(ctx, id, config.Name, evaluator)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		updated, err = tx.GetConfigurationByConfigurationID(ctx, id)
+		return err
+	})
+
+	return updated, err
+}
+
+// AddConfigurationEvaluator maps an evaluator to a room configuration at a priority. It takes
+// the place of the AddConfigurationMapping stored procedure.
+func (accessorGroup *AccessorGroup) AddConfigurationEvaluator(ctx context.Context, configurationName string, evaluator structs.ConfigurationEvaluator) (structs.ConfigurationEvaluator, error) {
+	err := accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		id, err := tx.configurationID(ctx, configurationName)
+		if err != nil {
+			return err
+		}
+
+		return tx.addConfigurationMapping(ctx, id, configurationName, evaluator)
+	})
+	if err != nil {
+		return structs.ConfigurationEvaluator{}, err
+	}
+
+	return evaluator, nil
+}
+
+// UpdateConfigurationEvaluator changes the priority of an evaluator in a room configuration,
+// which is how evaluators are reordered
+func (accessorGroup *AccessorGroup) UpdateConfigurationEvaluator(ctx context.Context, configurationName string, evaluatorKey string, evaluator structs.ConfigurationEvaluator) (structs.ConfigurationEvaluator, error) {
+	err := accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		id, err := tx.configurationID(ctx, configurationName)
+		if err != nil {
+			return err
+		}
+
+		// MySQL doesn't count rows an UPDATE leaves as they were, so check for the mapping first
+		var count int
+		err = tx.db().QueryRowContext(ctx, "SELECT COUNT(*) FROM RoomConfigurationMapping WHERE roomConfigurationID = ? AND evaluatorKey = ?", id, evaluatorKey).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return Errorf(NotFound, "%s has no evaluator %s", configurationName, evaluatorKey)
+		}
+
+		_, err = tx.db().ExecContext(ctx, "UPDATE RoomConfigurationMapping SET priority = ? WHERE roomConfigurationID = ? AND evaluatorKey = ?", evaluator.Priority, id, evaluatorKey)
+		return err
+	})
+	if err != nil {
+		return structs.ConfigurationEvaluator{}, err
+	}
+
+	evaluator.EvaluatorKey = evaluatorKey
+	return evaluator, nil
+}
+
+// DeleteConfigurationEvaluator removes an evaluator from a room configuration
+func (accessorGroup *AccessorGroup) DeleteConfigurationEvaluator(ctx context.Context, configurationName string, evaluatorKey string) error {
+	return accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		id, err := tx.configurationID(ctx, configurationName)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.db().ExecContext(ctx, "DELETE FROM RoomConfigurationMapping WHERE roomConfigurationID = ? AND evaluatorKey = ?", id, evaluatorKey)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return Errorf(NotFound, "%s has no evaluator %s", configurationName, evaluatorKey)
+		}
+
+		return nil
+	})
+}
+
+func (accessorGroup *AccessorGroup) configurationID(ctx context.Context, name string) (int, error) {
+	var id int
+	err := accessorGroup.db().QueryRowContext(ctx, "SELECT roomConfigurationID FROM RoomConfiguration WHERE name = ?", name).Scan(&id)
+	if KindOf(err) == NotFound {
+		return 0, Errorf(NotFound, "there is no room configuration called %s", name)
+	}
+
+	return id, err
+}
+
+// addConfigurationMapping adds a row to RoomConfigurationMapping. A configuration can only
+// have each evaluator once.
+func (accessorGroup *AccessorGroup) addConfigurationMapping(ctx context.Context, configurationID int, configurationName string, evaluator structs.ConfigurationEvaluator) error {
+	if len(evaluator.EvaluatorKey) == 0 {
+		return Errorf(Validation, "an evaluator needs an evaluatorKey")
+	}
+
+	var count int
+	err := accessorGroup.db().QueryRowContext(ctx, "SELECT COUNT(*) FROM RoomConfigurationMapping WHERE roomConfigurationID = ? AND evaluatorKey = ?", configurationID, evaluator.EvaluatorKey).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return Errorf(Conflict, "%s already has evaluator %s", configurationName, evaluator.EvaluatorKey)
+	}
+
+	_, err = accessorGroup.db().ExecContext(ctx, "INSERT INTO RoomConfigurationMapping (roomConfigurationID, evaluatorKey, priority) VALUES (?, ?, ?)",
+		configurationID, evaluator.EvaluatorKey, evaluator.Priority)
+	return err
+}
@@ -1,6 +1,7 @@
 package accessors
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -12,8 +13,9 @@ import (
 	"github.com/fatih/color"
 )
 
-//SetDeviceAttribute is used to set a field on the DEVICES TABLE.
-func (accessorGroup *AccessorGroup) SetDeviceAttribute(info structs.DeviceAttributeInfo) (structs.Device, error) {
+//SetDeviceAttribute is used to set a field on the DEVICES TABLE. Names that aren't one of its
+//columns are saved as device attributes, with info.AttributeType as their type.
+func (accessorGroup *AccessorGroup) SetDeviceAttribute(ctx context.Context, info structs.DeviceAttributeInfo) (structs.Device, error) {
 
 	acceptableColumnNames := make(map[string]string)
 
@@ -27,7 +29,44 @@ func (accessorGroup *AccessorGroup) SetDeviceAttribute(info structs.DeviceAttrib
 	acceptableColumnNames["typeID"] = "int"
 
 	if _, ok := acceptableColumnNames[info.AttributeName]; !ok {
-		return structs.Device{}, errors.New("invalid column name")
+		attribute, err := ParseAttribute(info.AttributeName, info.AttributeType, info.AttributeValue)
+		if err != nil {
+			return structs.Device{}, err
+		}
+
+		err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+			var count int
+			err := tx.db().QueryRowContext(ctx, "SELECT COUNT(*) FROM Devices WHERE deviceID = ?", info.DeviceID).Scan(&count)
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				return Errorf(NotFound, "No devices found for ID %d", info.DeviceID)
+			}
+
+			_, err = tx.putDeviceAttribute(ctx, info.DeviceID, attribute)
+			return err
+		})
+		if err != nil {
+			return structs.Device{}, err
+		}
+
+		return accessorGroup.GetDeviceById(ctx, info.DeviceID)
+	}
+
+	if info.AttributeName == "typeID" {
+		typeID, err := strconv.Atoi(info.AttributeValue)
+		if err != nil {
+			return structs.Device{}, &Error{Kind: Validation, Err: err}
+		}
+
+		// so the device's attributes get checked against the new type's schema
+		err = accessorGroup.SetDeviceTypeByID(ctx, typeID, info.DeviceID)
+		if err != nil {
+			return structs.Device{}, err
+		}
+
+		return accessorGroup.GetDeviceById(ctx, info.DeviceID)
 	}
 
 	query := fmt.Sprintf("UPDATE Devices SET %v = ? WHERE deviceID = ?", info.AttributeName)
@@ -41,7 +80,7 @@ func (accessorGroup *AccessorGroup) SetDeviceAttribute(info structs.DeviceAttrib
 
 	if val == "string" {
 		fmt.Sprintf("Setting a string value")
-		res, err = accessorGroup.Database.Exec(query, info.AttributeValue, info.DeviceID)
+		res, err = accessorGroup.db().ExecContext(ctx, query, info.AttributeValue, info.DeviceID)
 		if err != nil {
 			return structs.Device{}, err
 		}
@@ -50,9 +89,9 @@ func (accessorGroup *AccessorGroup) SetDeviceAttribute(info structs.DeviceAttrib
 		var value int
 		value, err = strconv.Atoi(info.AttributeValue)
 		if err != nil {
-			return structs.Device{}, err
+			return structs.Device{}, &Error{Kind: Validation, Err: err}
 		}
-		res, err = accessorGroup.Database.Exec(query, value, info.DeviceID)
+		res, err = accessorGroup.db().ExecContext(ctx, query, value, info.DeviceID)
 		if err != nil {
 			return structs.Device{}, err
 		}
@@ -64,9 +103,9 @@ func (accessorGroup *AccessorGroup) SetDeviceAttribute(info structs.DeviceAttrib
 		} else if info.AttributeValue == "true" {
 			value = true
 		} else {
-			return structs.Device{}, errors.New("Invalid value for a boolean column")
+			return structs.Device{}, Errorf(Validation, "Invalid value for a boolean column")
 		}
-		res, err = accessorGroup.Database.Exec(query, value, info.DeviceID)
+		res, err = accessorGroup.db().ExecContext(ctx, query, value, info.DeviceID)
 		if err != nil {
 			return structs.Device{}, err
 		}
@@ -84,7 +123,7 @@ func (accessorGroup *AccessorGroup) SetDeviceAttribute(info structs.DeviceAttrib
 	log.Printf("Done.")
 
 	log.Printf("Getting the device to return")
-	return accessorGroup.GetDeviceById(info.DeviceID)
+	return accessorGroup.GetDeviceById(ctx, info.DeviceID)
 }
 
 /*
@@ -101,8 +140,7 @@ If empty string is passed in no WHERE clause will be appended, and thus all devi
 will be returned.
 
 Flow	->	Find all devices based on the clause passed in
-			->	For each device found find the Ports
-			->	For each device found find the Commands
+			->	Find the Ports, Commands, PowerStates and Roles for all of them at once
 
 Examples of valid parameters.
 Example 1:
@@ -112,7 +150,7 @@ WHERE DeviceRoleDefinition.name LIKE 'AudioIn'`
 Example 2:
 `WHERE Devices.RoomID = 1`
 */
-func (accessorGroup *AccessorGroup) GetDevicesByQuery(query string, parameters ...interface{}) ([]structs.Device, error) {
+func (accessorGroup *AccessorGroup) GetDevicesByQuery(ctx context.Context, query string, parameters ...interface{}) ([]structs.Device, error) {
 	baseQuery := `SELECT DISTINCT Devices.deviceID,
   	Devices.Name as deviceName,
   	Devices.address as deviceAddress,
@@ -140,7 +178,7 @@ func (accessorGroup *AccessorGroup) GetDevicesByQuery(query string, parameters .
 	allDevices := []structs.Device{}
 
 	log.Printf("Making query for devices")
-	rows, err := accessorGroup.Database.Query(baseQuery+" "+query, parameters...)
+	rows, err := accessorGroup.db().QueryContext(ctx, baseQuery+" "+query, parameters...)
 	if err != nil {
 		log.Printf("Problem executing query: %v", err.Error())
 		return []structs.Device{}, err
@@ -149,6 +187,8 @@ func (accessorGroup *AccessorGroup) GetDevicesByQuery(query string, parameters .
 
 	defer rows.Close()
 
+	// read every row before running the queries below; a connection (and so a transaction)
+	// can't run a second query while it still has rows to hand back
 	for rows.Next() {
 
 		device := structs.Device{}
@@ -174,49 +214,40 @@ func (accessorGroup *AccessorGroup) GetDevicesByQuery(query string, parameters .
 			return []structs.Device{}, err
 		}
 
-		device.Commands, err = accessorGroup.GetDeviceCommandsByBuildingAndRoomAndName(device.Building.Shortname, device.Room.Name, device.Name)
-		if err != nil {
-			return []structs.Device{}, err
-		}
-
-		device.Ports, err = accessorGroup.GetDevicePortsByBuildingAndRoomAndName(device.Building.Shortname, device.Room.Name, device.Name)
-		if err != nil {
-			return []structs.Device{}, err
-		}
-
-		device.PowerStates, err = accessorGroup.GetPowerStatesByDeviceID(device.ID)
-		if err != nil {
-			return []structs.Device{}, err
-		}
+		allDevices = append(allDevices, device)
+	}
 
-		device.Roles, err = accessorGroup.GetRolesByDeviceID(device.ID)
-		if err != nil {
-			return []structs.Device{}, err
-		}
+	err = rows.Err()
+	if err != nil {
+		return []structs.Device{}, err
+	}
+	rows.Close()
 
-		allDevices = append(allDevices, device)
+	err = accessorGroup.fillDeviceRelations(ctx, allDevices)
+	if err != nil {
+		return []structs.Device{}, err
 	}
 
 	return allDevices, nil
 }
 
-func (AccessorGroup *AccessorGroup) GetDeviceById(deviceID int) (structs.Device, error) {
+func (AccessorGroup *AccessorGroup) GetDeviceById(ctx context.Context, deviceID int) (structs.Device, error) {
 	log.Printf("Getting device with deviceID %v", deviceID)
 
-	devices, err := AccessorGroup.GetDevicesByQuery(" WHERE Devices.DeviceID = ?", deviceID)
+	devices, err := AccessorGroup.GetDevicesByQuery(ctx, " WHERE Devices.DeviceID = ?", deviceID)
 	if err != nil {
 		return structs.Device{}, err
 	}
 	if len(devices) < 1 {
-		return structs.Device{}, errors.New(fmt.Sprintf("No devices found for ID %d", deviceID))
+		return structs.Device{}, Errorf(NotFound, "No devices found for ID %d", deviceID)
 	}
 
 	return devices[0], nil
 }
 
-func (AccessorGroup *AccessorGroup) GetDevicesByRoomIdAndRoleId(roomId, roleId int) ([]structs.Device, error) {
+func (AccessorGroup *AccessorGroup) GetDevicesByRoomIdAndRoleId(ctx context.Context, roomId, roleId int) ([]structs.Device, error) {
 
-	devices, err := AccessorGroup.GetDevicesByQuery("WHERE Rooms.roomID = ? AND DeviceRoleDefinition.deviceRoleDefinitionID = ?", roomId, roleId)
+	devices, err := AccessorGroup.GetDevicesByQuery(ctx, "WHERE Rooms.roomID = ? AND DeviceRoleDefinition.deviceRoleDefinitionID = ?", roomId, roleId)
 	if err != nil {
 		return []structs.Device{}, err
 	}
@@ -224,9 +255,9 @@ func (AccessorGroup *AccessorGroup) GetDevicesByRoomIdAndRoleId(roomId, roleId i
 	return devices, nil
 }
 
-func (AccessorGroup *AccessorGroup) GetDevicesByRoomId(roomId int) ([]structs.Device, error) {
+func (AccessorGroup *AccessorGroup) GetDevicesByRoomId(ctx context.Context, roomId int) ([]structs.Device, error) {
 
-	devices, err := AccessorGroup.GetDevicesByQuery("WHERE Rooms.roomID = ?", roomId)
+	devices, err := AccessorGroup.GetDevicesByQuery(ctx, "WHERE Rooms.roomID = ?", roomId)
 	if err != nil {
 		return []structs.Device{}, err
 	}
@@ -234,7 +265,7 @@ func (AccessorGroup *AccessorGroup) GetDevicesByRoomId(roomId int) ([]structs.De
 	return devices, nil
 }
 
-func (AccessorGroup *AccessorGroup) GetRolesByDeviceID(deviceID int) ([]string, error) {
+func (AccessorGroup *AccessorGroup) GetRolesByDeviceID(ctx context.Context, deviceID int) ([]string, error) {
 	log.Printf("Getting roles by device ID: %v", deviceID)
 	query := `Select DeviceRoleDefinition.name From DeviceRoleDefinition 
 	JOIN DeviceRole dr on dr.deviceRoleDefinitionID = DeviceRoleDefinition.deviceRoleDefinitionID 
@@ -242,7 +273,7 @@ func (AccessorGroup *AccessorGroup) GetRolesByDeviceID(deviceID int) ([]string,
 
 	toReturn := []string{}
 
-	rows, err := AccessorGroup.Database.Query(query, deviceID)
+	rows, err := AccessorGroup.db().QueryContext(ctx, query, deviceID)
 	if err != nil {
 		return []string{}, err
 	}
@@ -267,13 +298,13 @@ func (AccessorGroup *AccessorGroup) GetRolesByDeviceID(deviceID int) ([]string,
 
 //GetPowerStatesByDeviceID gets the powerstates allowed for a given devices based on the
 //DevicePowerStates table in the DB.
-func (AccessorGroup *AccessorGroup) GetPowerStatesByDeviceID(deviceID int) ([]string, error) {
+func (AccessorGroup *AccessorGroup) GetPowerStatesByDeviceID(ctx context.Context, deviceID int) ([]string, error) {
 	query := `SELECT PowerStates.name FROM PowerStates
 	JOIN DevicePowerStates on DevicePowerStates.powerStateID = PowerStates.powerStateID
 	Where DevicePowerStates.deviceID = ?`
 
 	toReturn := []string{}
-	rows, err := AccessorGroup.Database.Query(query, deviceID)
+	rows, err := AccessorGroup.db().QueryContext(ctx, query, deviceID)
 	if err != nil {
 		return []string{}, err
 	}
@@ -293,9 +324,9 @@ func (AccessorGroup *AccessorGroup) GetPowerStatesByDeviceID(deviceID int) ([]st
 
 //GetDevicesByBuildingAndRoomAndRole gets the devices in the room specified with the given role,
 //as specified in the DeviceRole table in the DB
-func (accessorGroup *AccessorGroup) GetDevicesByBuildingAndRoomAndRole(buildingShortname string, roomName string, roleName string) ([]structs.Device, error) {
+func (accessorGroup *AccessorGroup) GetDevicesByBuildingAndRoomAndRole(ctx context.Context, buildingShortname string, roomName string, roleName string) ([]structs.Device, error) {
 	log.Printf("Getting ")
-	devices, err := accessorGroup.GetDevicesByQuery(`WHERE Rooms.name LIKE ? AND Buildings.shortname LIKE ? AND DeviceRoleDefinition.name LIKE ?`,
+	devices, err := accessorGroup.GetDevicesByQuery(ctx, `WHERE Rooms.name LIKE ? AND Buildings.shortname LIKE ? AND DeviceRoleDefinition.name LIKE ?`,
 		roomName, buildingShortname, roleName)
 
 	if err != nil {
@@ -310,16 +341,16 @@ func (accessorGroup *AccessorGroup) GetDevicesByBuildingAndRoomAndRole(buildingS
 }
 
 //GetDevicesByRoleAndType Gets all teh devices that have a given role and type.
-func (accessorGroup *AccessorGroup) GetDevicesByRoleAndType(deviceRole string, deviceType string, production string) ([]structs.Device, error) {
+func (accessorGroup *AccessorGroup) GetDevicesByRoleAndType(ctx context.Context, deviceRole string, deviceType string, production string) ([]structs.Device, error) {
 	log.Printf("Making the query")
-	return accessorGroup.GetDevicesByQuery(`WHERE DeviceRoleDefinition.name LIKE ? AND DeviceClasses.name LIKE ? AND Rooms.roomDesignation = ?`, deviceRole, deviceType, production)
+	return accessorGroup.GetDevicesByQuery(ctx, `WHERE DeviceRoleDefinition.name LIKE ? AND DeviceClasses.name LIKE ? AND Rooms.roomDesignation = ?`, deviceRole, deviceType, production)
 }
 
 //GetDevicesByBuildingAndRoom get all the devices in the room specified.
-func (accessorGroup *AccessorGroup) GetDevicesByBuildingAndRoom(buildingShortname string, roomName string) ([]structs.Device, error) {
+func (accessorGroup *AccessorGroup) GetDevicesByBuildingAndRoom(ctx context.Context, buildingShortname string, roomName string) ([]structs.Device, error) {
 	log.Printf("Getting devices in room %s and building %s", roomName, buildingShortname)
 
-	devices, err := accessorGroup.GetDevicesByQuery(
+	devices, err := accessorGroup.GetDevicesByQuery(ctx,
 		`WHERE Rooms.name=? AND Buildings.shortName=?`, roomName, buildingShortname)
 
 	if err != nil {
@@ -329,13 +360,19 @@ func (accessorGroup *AccessorGroup) GetDevicesByBuildingAndRoom(buildingShortnam
 	return devices, nil
 }
 
+//GetAllDevices gets every device in every room, with the relations the group expands, in one
+//query per relation rather than one per room
+func (accessorGroup *AccessorGroup) GetAllDevices(ctx context.Context) ([]structs.Device, error) {
+	return accessorGroup.GetDevicesByQuery(ctx, "")
+}
+
 //GetDeviceCommandsByBuildingAndRoomAndName gets all the commands for the device
 //specified. Note that we assume that device names are unique within a room.
-func (accessorGroup *AccessorGroup) GetDeviceCommandsByBuildingAndRoomAndName(buildingShortname string, roomName string, deviceName string) ([]structs.Command, error) {
+func (accessorGroup *AccessorGroup) GetDeviceCommandsByBuildingAndRoomAndName(ctx context.Context, buildingShortname string, roomName string, deviceName string) ([]structs.Command, error) {
 
 	log.Printf("Getting all the commands for %v-%v-%v", buildingShortname, roomName, deviceName)
 	allCommands := []structs.Command{}
-	rows, err := accessorGroup.Database.Query(`SELECT Commands.name as commandName, Endpoints.name as endpointName, Endpoints.path as endpointPath, Microservices.address as microserviceAddress
+	rows, err := accessorGroup.db().QueryContext(ctx, `SELECT Commands.name as commandName, Endpoints.name as endpointName, Endpoints.path as endpointPath, Microservices.address as microserviceAddress
     FROM Devices
 	JOIN DeviceTypes on DeviceTypes.deviceTypeID = Devices.typeID
 	JOIN DeviceTypeCommandMapping TypeCommands on TypeCommands.deviceTypeID = DeviceTypes.deviceTypeID
@@ -364,10 +401,18 @@ func (accessorGroup *AccessorGroup) GetDeviceCommandsByBuildingAndRoomAndName(bu
 //specified. Note that we assume that device names are unique within a room.
 /*
  */
-func (accessorGroup *AccessorGroup) GetDevicePortsByBuildingAndRoomAndName(buildingShortname string, roomName string, deviceName string) ([]structs.Port, error) {
+func (accessorGroup *AccessorGroup) GetDevicePortsByBuildingAndRoomAndName(ctx context.Context, buildingShortname string, roomName string, deviceName string) ([]structs.Port, error) {
 	allPorts := []structs.Port{}
 
-	rows, err := accessorGroup.Database.Query(`SELECT srcDevice.Name as sourceName, Ports.name as portName, destDevice.Name as DestinationDevice, hostDevice.name as HostDevice FROM Ports
+	// a device with no ports configured and one that doesn't exist both match nothing below
+	_, err := accessorGroup.deviceID(ctx, buildingShortname, roomName, deviceName)
+	if KindOf(err) == NotFound {
+		return allPorts, Errorf(NotFound, "No devices found with that name.")
+	} else if err != nil {
+		return allPorts, err
+	}
+
+	rows, err := accessorGroup.db().QueryContext(ctx, `SELECT srcDevice.Name as sourceName, Ports.name as portName, destDevice.Name as DestinationDevice, hostDevice.name as HostDevice FROM Ports
     JOIN PortConfiguration ON Ports.PortID = PortConfiguration.PortID
     JOIN Devices as srcDevice on srcDevice.DeviceID = PortConfiguration.sourceDeviceID
     JOIN Devices as destDevice on destDevice.DeviceID = PortConfiguration.destinationDeviceID
@@ -398,18 +443,30 @@ func (accessorGroup *AccessorGroup) GetDevicePortsByBuildingAndRoomAndName(build
 
 //GetDeviceByBuildingAndRoomAndName gets the device
 //specified. Note that we assume that device names are unique within a room.
-func (accessorGroup *AccessorGroup) GetDeviceByBuildingAndRoomAndName(buildingShortname string, roomName string, deviceName string) (structs.Device, error) {
-	dev, err := accessorGroup.GetDevicesByQuery("WHERE Buildings.shortName = ? AND Rooms.name = ? AND Devices.name = ?", buildingShortname, roomName, deviceName)
-	if err != nil || len(dev) == 0 {
+func (accessorGroup *AccessorGroup) GetDeviceByBuildingAndRoomAndName(ctx context.Context, buildingShortname string, roomName string, deviceName string) (structs.Device, error) {
+	dev, err := accessorGroup.GetDevicesByQuery(ctx, "WHERE Buildings.shortName = ? AND Rooms.name = ? AND Devices.name = ?", buildingShortname, roomName, deviceName)
+	if err != nil {
 		return structs.Device{}, err
 	}
+	if len(dev) == 0 {
+		return structs.Device{}, Errorf(NotFound, "No devices found with that name.")
+	}
 
 	return dev[0], nil
 }
 
 //PutDeviceAttributeByDeviceAndRoomAndBuilding allows you to change attribute values for devices
-//Currently sets volume and muted.
-func (accessorGroup *AccessorGroup) PutDeviceAttributeByDeviceAndRoomAndBuilding(building string, room string, device string, attribute string, attributeValue string) (structs.Device, error) {
+//Volume and muted are set on the audio device; anything else is saved as a device attribute,
+//typed by what the value looks like.
+func (accessorGroup *AccessorGroup) PutDeviceAttributeByDeviceAndRoomAndBuilding(ctx context.Context, building string, room string, device string, attribute string, attributeValue string) (structs.Device, error) {
+	switch strings.ToLower(attribute) {
+	case "volume", "muted":
+		err := accessorGroup.checkDeclaredAttribute(ctx, building, room, device, strings.ToLower(attribute), attributeValue)
+		if err != nil {
+			return structs.Device{}, err
+		}
+	}
+
 	switch strings.ToLower(attribute) {
 	case "volume":
 		statement := `update AudioDevices SET volume = ? WHERE deviceID =
@@ -419,10 +476,10 @@ func (accessorGroup *AccessorGroup) PutDeviceAttributeByDeviceAndRoomAndBuilding
 				WHERE Devices.name LIKE ? AND Rooms.name LIKE ? AND Buildings.shortName LIKE ?)`
 		val, err := strconv.Atoi(attributeValue)
 		if err != nil {
-			return structs.Device{}, err
+			return structs.Device{}, &Error{Kind: Validation, Err: err}
 		}
 
-		_, err = accessorGroup.Database.Exec(statement, val, device, room, building)
+		_, err = accessorGroup.db().ExecContext(ctx, statement, val, device, room, building)
 		if err != nil {
 			return structs.Device{}, err
 		}
@@ -438,46 +495,252 @@ func (accessorGroup *AccessorGroup) PutDeviceAttributeByDeviceAndRoomAndBuilding
 			valToSet = false
 			break
 		default:
-			return structs.Device{}, errors.New("Invalid attribute value, must be a boolean.")
+			return structs.Device{}, Errorf(Validation, "Invalid attribute value, must be a boolean.")
 		}
 		statement := `update AudioDevices SET muted = ? WHERE deviceID =
 			(Select deviceID from Devices
 				JOIN Rooms on Rooms.roomID = Devices.roomID
 				JOIN Buildings on Buildings.buildingID = Rooms.buildingID
 				WHERE Devices.name LIKE ? AND Rooms.name LIKE ? AND Buildings.shortName LIKE ?)`
-		_, err := accessorGroup.Database.Exec(statement, valToSet, device, room, building)
+		_, err := accessorGroup.db().ExecContext(ctx, statement, valToSet, device, room, building)
 		if err != nil {
 			return structs.Device{}, err
 		}
 		break
+
+	default:
+		toSet, err := ParseAttribute(attribute, "", attributeValue)
+		if err != nil {
+			return structs.Device{}, err
+		}
+
+		_, err = accessorGroup.PutDeviceAttribute(ctx, building, room, device, toSet)
+		if err != nil {
+			return structs.Device{}, err
+		}
 	}
 
-	dev, err := accessorGroup.GetDeviceByBuildingAndRoomAndName(building, room, device)
+	dev, err := accessorGroup.GetDeviceByBuildingAndRoomAndName(ctx, building, room, device)
 	return dev, err
 }
 
-func (accessorGroup *AccessorGroup) AddDevice(d structs.Device) (structs.Device, error) {
+//DeleteDevice deletes a device along with its roles, power states, commands and port
+//configurations, all in one transaction.
+func (accessorGroup *AccessorGroup) DeleteDevice(ctx context.Context, buildingShortname string, roomName string, deviceName string) error {
+	return accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		id, err := tx.deviceID(ctx, buildingShortname, roomName, deviceName)
+		if err != nil {
+			return err
+		}
+
+		return tx.deleteDevicesWhere(ctx, "deviceID = ?", id)
+	})
+}
+
+//UpdateDevice replaces a device's name, address, input/output flags, type, class and display
+//name, and brings its roles and power states in line with d's, inserting and removing only the
+//ones that changed. A nil Roles or PowerStates (left out of the JSON) leaves them as they are;
+//an empty PowerStates clears them. Roles can't be emptied, since a device without any is
+//invisible to GetDevicesByQuery. The device stays in the room it's in.
+func (accessorGroup *AccessorGroup) UpdateDevice(ctx context.Context, buildingShortname string, roomName string, deviceName string, d structs.Device) (updated structs.Device, err error) {
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		id, err := tx.deviceID(ctx, buildingShortname, roomName, deviceName)
+		if err != nil {
+			return err
+		}
+
+		if len(d.Name) == 0 {
+			d.Name = deviceName
+		}
+
+		if d.Name != deviceName {
+			_, err = tx.deviceID(ctx, buildingShortname, roomName, d.Name)
+			if err == nil {
+				return Errorf(Conflict, "there is already a device called %s in %s-%s", d.Name, buildingShortname, roomName)
+			} else if KindOf(err) != NotFound {
+				return err
+			}
+		}
+
+		dt, err := tx.GetDeviceTypeByName(ctx, d.Type)
+		if KindOf(err) == NotFound {
+			return Errorf(Validation, "device type: %v does not exist", d.Type)
+		} else if err != nil {
+			return err
+		}
+
+		dc, err := tx.GetDeviceClassByName(ctx, d.Class)
+		if KindOf(err) == NotFound {
+			return Errorf(Validation, "device class: %v does not exist", d.Class)
+		} else if err != nil {
+			return err
+		}
+
+		if len(d.DisplayName) == 0 {
+			d.DisplayName = dc.DisplayName
+		}
+
+		_, err = tx.db().ExecContext(ctx, "UPDATE Devices SET name = ?, address = ?, input = ?, output = ?, classID = ?, typeID = ?, displayName = ? WHERE deviceID = ?",
+			d.Name, d.Address, d.Input, d.Output, dt.ID, dc.ID, d.DisplayName, id)
+		if err != nil {
+			return err
+		}
+
+		// a new class can come with a schema the device's attributes don't fit
+		err = tx.checkDeviceAttributes(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if d.Roles != nil {
+			if len(d.Roles) == 0 {
+				return Errorf(Validation, "a device needs at least one role; devices without roles aren't returned by the API")
+			}
+
+			roles := []int{}
+			for _, role := range d.Roles {
+				r, err := tx.GetDeviceRoleDefByName(ctx, role)
+				if KindOf(err) == NotFound {
+					return Errorf(Validation, "device role definition: %v does not exist", role)
+				} else if err != nil {
+					return err
+				}
+				roles = append(roles, r.ID)
+			}
+
+			err = tx.setDeviceLinks(ctx, id, "DeviceRole", "deviceRoleDefinitionID", roles)
+			if err != nil {
+				return err
+			}
+		}
+
+		if d.PowerStates != nil {
+			powerStates := []int{}
+			for _, ps := range d.PowerStates {
+				p, err := tx.GetPowerStateByName(ctx, ps)
+				if KindOf(err) == NotFound {
+					return Errorf(Validation, "powerstate: %v does not exist", ps)
+				} else if err != nil {
+					return err
+				}
+				powerStates = append(powerStates, p.ID)
+			}
+
+			err = tx.setDeviceLinks(ctx, id, "DevicePowerStates", "powerStateID", powerStates)
+			if err != nil {
+				return err
+			}
+		}
+
+		updated, err = tx.GetDeviceByBuildingAndRoomAndName(ctx, buildingShortname, roomName, d.Name)
+		return err
+	})
+
+	return updated, err
+}
+
+// setDeviceLinks makes the device's rows in table (DeviceRole or DevicePowerStates) point at
+// exactly the IDs in want, deleting and inserting only the rows that differ
+func (accessorGroup *AccessorGroup) setDeviceLinks(ctx context.Context, deviceID int, table string, column string, want []int) error {
+	rows, err := accessorGroup.db().QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s WHERE deviceID = ?", column, table), deviceID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	have := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		err = rows.Scan(&id)
+		if err != nil {
+			return err
+		}
+		have[id] = true
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	wanted := make(map[int]bool)
+	for _, id := range want {
+		if wanted[id] {
+			continue
+		}
+		wanted[id] = true
+
+		if !have[id] {
+			_, err = accessorGroup.db().ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (deviceID, %s) VALUES (?, ?)", table, column), deviceID, id)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for id := range have {
+		if !wanted[id] {
+			_, err = accessorGroup.db().ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE deviceID = ? AND %s = ?", table, column), deviceID, id)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// deviceID looks up a device's ID by building, room and name
+func (accessorGroup *AccessorGroup) deviceID(ctx context.Context, buildingShortname string, roomName string, deviceName string) (int, error) {
+	var id int
+	err := accessorGroup.db().QueryRowContext(ctx, `SELECT Devices.deviceID FROM Devices
+		JOIN Rooms ON Rooms.roomID = Devices.roomID
+		JOIN Buildings ON Buildings.buildingID = Rooms.buildingID
+		WHERE Buildings.shortName = ? AND Rooms.name = ? AND Devices.name = ?`, buildingShortname, roomName, deviceName).Scan(&id)
+
+	return id, err
+}
+
+//AddDevice adds a device along with its roles and power states. Either all of it is saved or none of it is.
+func (accessorGroup *AccessorGroup) AddDevice(ctx context.Context, d structs.Device) (added structs.Device, err error) {
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		added, err = tx.addDevice(ctx, d)
+		return err
+	})
+	if err != nil {
+		return structs.Device{}, err
+	}
+
+	return added, nil
+}
+
+func (accessorGroup *AccessorGroup) addDevice(ctx context.Context, d structs.Device) (structs.Device, error) {
 	log.Printf("Adding device %v to room %v in building %v", d.Name, d.Room.Name, d.Building.Shortname)
 
 	// get device type string, put it into d.Type
-	dt, err := accessorGroup.GetDeviceTypeByName(d.Type)
-	if err != nil {
+	dt, err := accessorGroup.GetDeviceTypeByName(ctx, d.Type)
+	if KindOf(err) == NotFound {
+		return structs.Device{}, Errorf(Validation, "device type: %v does not exist", d.Type)
+	} else if err != nil {
 		return structs.Device{}, err
 	}
 
-	dc, err := accessorGroup.GetDeviceClassByName(d.Class)
-	if err != nil {
+	dc, err := accessorGroup.GetDeviceClassByName(ctx, d.Class)
+	if KindOf(err) == NotFound {
+		return structs.Device{}, Errorf(Validation, "device class: %v does not exist", d.Class)
+	} else if err != nil {
 		return structs.Device{}, err
 	}
 
 	// if device already exists in database, stop
-	exists, err := accessorGroup.GetDeviceByBuildingAndRoomAndName(d.Building.Shortname, d.Room.Name, d.Name)
-	if err != nil || exists.ID != 0 {
-		return structs.Device{}, fmt.Errorf("device already exists in room, please choose a different name")
+	_, err = accessorGroup.GetDeviceByBuildingAndRoomAndName(ctx, d.Building.Shortname, d.Room.Name, d.Name)
+	if err == nil {
+		return structs.Device{}, Errorf(Conflict, "device already exists in room, please choose a different name")
+	} else if KindOf(err) != NotFound {
+		return structs.Device{}, err
 	}
 
 	// insert into devices
-	result, err := accessorGroup.Database.Exec("Insert into Devices (name, address, input, output, buildingID, roomID, classID, typeID, displayName) VALUES (?,?,?,?,?,?,?,?,?)", d.Name, d.Address, d.Input, d.Output, d.Building.ID, d.Room.ID, dt.ID, dc.ID, dc.DisplayName)
+	result, err := accessorGroup.db().ExecContext(ctx, "Insert into Devices (name, address, input, output, buildingID, roomID, classID, typeID, displayName) VALUES (?,?,?,?,?,?,?,?,?)", d.Name, d.Address, d.Input, d.Output, d.Building.ID, d.Room.ID, dt.ID, dc.ID, dc.DisplayName)
 	if err != nil {
 		return structs.Device{}, err
 	}
@@ -488,14 +751,16 @@ func (accessorGroup *AccessorGroup) AddDevice(d structs.Device) (structs.Device,
 	}
 
 	d.ID = int(id)
-	log.Printf(color.HiGreenString("NewID: %v", d.ID))
+	log.Printf("%s", color.HiGreenString("NewID: %v", d.ID))
 
 	// insert the roles into the DeviceRole table
 	var deviceroles []structs.DeviceRole
 	for _, role := range d.Roles {
-		r, err := accessorGroup.GetDeviceRoleDefByName(role)
-		if err != nil {
-			return structs.Device{}, fmt.Errorf("device role definition: %v does not exist", role)
+		r, err := accessorGroup.GetDeviceRoleDefByName(ctx, role)
+		if KindOf(err) == NotFound {
+			return structs.Device{}, Errorf(Validation, "device role definition: %v does not exist", role)
+		} else if err != nil {
+			return structs.Device{}, err
 		}
 		var dr structs.DeviceRole
 		dr.DeviceID = d.ID
@@ -507,9 +772,11 @@ func (accessorGroup *AccessorGroup) AddDevice(d structs.Device) (structs.Device,
 	// insert the powerstates into the DevicePowerStates table
 	var devicepowerstates []structs.DevicePowerState
 	for _, ps := range d.PowerStates {
-		p, err := accessorGroup.GetPowerStateByName(ps)
-		if err != nil {
-			return structs.Device{}, fmt.Errorf("powerstate: %v does not exist", ps)
+		p, err := accessorGroup.GetPowerStateByName(ctx, ps)
+		if KindOf(err) == NotFound {
+			return structs.Device{}, Errorf(Validation, "powerstate: %v does not exist", ps)
+		} else if err != nil {
+			return structs.Device{}, err
 		}
 		var dps structs.DevicePowerState
 		dps.DeviceID = d.ID
@@ -520,14 +787,14 @@ func (accessorGroup *AccessorGroup) AddDevice(d structs.Device) (structs.Device,
 
 	// insert everything else
 	for _, dr := range deviceroles {
-		_, err = accessorGroup.AddDeviceRole(dr)
+		_, err = accessorGroup.AddDeviceRole(ctx, dr)
 		if err != nil {
 			return structs.Device{}, err
 		}
 	}
 
 	for _, ps := range devicepowerstates {
-		_, err = accessorGroup.AddDevicePowerState(ps)
+		_, err = accessorGroup.AddDevicePowerState(ctx, ps)
 		if err != nil {
 			return structs.Device{}, err
 		}
@@ -0,0 +1,252 @@
+package accessors_test
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+func TestDeleteDevice(t *testing.T) {
+	tests := []struct {
+		name   string
+		room   string
+		device string
+		// id is the device's ID in the fixture, 0 for one that isn't there
+		id   int
+		kind accessors.Kind
+		// ports is how many port configurations are left after
+		ports int
+	}{
+		{name: "the host of a port", room: "1101", device: "D1", id: 1, kind: succeeds, ports: 0},
+		{name: "the source of a port", room: "1101", device: "PC1", id: 2, kind: succeeds, ports: 0},
+		{name: "without ports", room: "1101", device: "CP1", id: 3, kind: succeeds, ports: 1},
+		{name: "one with the same name in another room", room: "1001D", device: "D1", id: 4, kind: succeeds, ports: 1},
+		{name: "a missing device", room: "1101", device: "D9", kind: accessors.NotFound, ports: 1},
+		{name: "in a missing room", room: "9999", device: "D1", kind: accessors.NotFound, ports: 1},
+	}
+
+	for store, newStore := range testStores {
+		for _, test := range tests {
+			t.Run(store+"/"+test.name, func(t *testing.T) {
+				ctx := context.Background()
+				s, done := newStore(t)
+				defer done()
+
+				err := s.DeleteDevice(ctx, "ITB", test.room, test.device)
+				checkKind(t, "DeleteDevice", err, test.kind)
+
+				_, err = s.GetDeviceByBuildingAndRoomAndName(ctx, "ITB", test.room, test.device)
+				checkKind(t, "GetDeviceByBuildingAndRoomAndName after deleting", err, accessors.NotFound)
+
+				devices, err := s.GetAllDevices(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := 4
+				if test.kind == succeeds {
+					want = 3
+				}
+				if len(devices) != want {
+					t.Errorf("%d devices left, want %d", len(devices), want)
+				}
+
+				ports, err := s.GetAllPortConfiguration(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(ports) != test.ports {
+					t.Errorf("%d port configurations left, want %d", len(ports), test.ports)
+				}
+
+				if test.id == 0 {
+					return
+				}
+
+				// nothing that pointed at the device is left behind
+				roles, err := s.GetRolesByDeviceID(ctx, test.id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(roles) != 0 {
+					t.Errorf("the deleted device still has roles %v", roles)
+				}
+
+				powerStates, err := s.GetPowerStatesByDeviceID(ctx, test.id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(powerStates) != 0 {
+					t.Errorf("the deleted device still has power states %v", powerStates)
+				}
+
+				withAttribute, err := s.GetDevicesByAttribute(ctx, "inputDelay", "")
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, device := range withAttribute {
+					if device.ID == test.id {
+						t.Errorf("the deleted device still has attribute inputDelay")
+					}
+				}
+
+				// and the devices left keep theirs
+				for _, device := range devices {
+					roles, err := s.GetRolesByDeviceID(ctx, device.ID)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(roles) != 2 {
+						t.Errorf("%s (%d) has roles %v, want 2 of them", device.Name, device.ID, roles)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestUpdateDevice(t *testing.T) {
+	tests := []struct {
+		name   string
+		device string
+		// change is made to the device as it's read, and the result sent as the update
+		change func(d *structs.Device)
+		kind   accessors.Kind
+		// roles and powerStates are what the device has after, sorted
+		roles       []string
+		powerStates []string
+	}{
+		{
+			name:        "leave the roles and power states out",
+			device:      "D1",
+			change:      func(d *structs.Device) { d.Roles, d.PowerStates = nil, nil },
+			kind:        succeeds,
+			roles:       []string{"AudioOut", "VideoOut"},
+			powerStates: []string{"On", "Standby"},
+		},
+		{
+			name:        "replace a role",
+			device:      "D1",
+			change:      func(d *structs.Device) { d.Roles = []string{"AudioOut", "VideoIn"} },
+			kind:        succeeds,
+			roles:       []string{"AudioOut", "VideoIn"},
+			powerStates: []string{"On", "Standby"},
+		},
+		{
+			name:        "repeat a role",
+			device:      "D1",
+			change:      func(d *structs.Device) { d.Roles = []string{"VideoOut", "VideoOut"} },
+			kind:        succeeds,
+			roles:       []string{"VideoOut"},
+			powerStates: []string{"On", "Standby"},
+		},
+		{
+			name:        "add and remove power states",
+			device:      "D1",
+			change:      func(d *structs.Device) { d.PowerStates = []string{"Standby", "Off"} },
+			kind:        succeeds,
+			roles:       []string{"AudioOut", "VideoOut"},
+			powerStates: []string{"Off", "Standby"},
+		},
+		{
+			name:        "clear the power states",
+			device:      "D1",
+			change:      func(d *structs.Device) { d.PowerStates = []string{} },
+			kind:        succeeds,
+			roles:       []string{"AudioOut", "VideoOut"},
+			powerStates: []string{},
+		},
+		{
+			name:        "clear the roles",
+			device:      "D1",
+			change:      func(d *structs.Device) { d.Roles = []string{} },
+			kind:        accessors.Validation,
+			roles:       []string{"AudioOut", "VideoOut"},
+			powerStates: []string{"On", "Standby"},
+		},
+		{
+			name:        "a role that doesn't exist",
+			device:      "D1",
+			change:      func(d *structs.Device) { d.Roles = []string{"AudioOut", "Hologram"}; d.PowerStates = []string{} },
+			kind:        accessors.Validation,
+			roles:       []string{"AudioOut", "VideoOut"},
+			powerStates: []string{"On", "Standby"},
+		},
+		{
+			name:        "a power state that doesn't exist",
+			device:      "D1",
+			change:      func(d *structs.Device) { d.Roles = []string{"AudioOut"}; d.PowerStates = []string{"Asleep"} },
+			kind:        accessors.Validation,
+			roles:       []string{"AudioOut", "VideoOut"},
+			powerStates: []string{"On", "Standby"},
+		},
+		{
+			name:        "rename onto another device",
+			device:      "D1",
+			change:      func(d *structs.Device) { d.Name = "PC1" },
+			kind:        accessors.Conflict,
+			roles:       []string{"AudioOut", "VideoOut"},
+			powerStates: []string{"On", "Standby"},
+		},
+		{
+			name:   "a missing device",
+			device: "D9",
+			change: func(d *structs.Device) {},
+			kind:   accessors.NotFound,
+		},
+	}
+
+	for store, newStore := range testStores {
+		for _, test := range tests {
+			t.Run(store+"/"+test.name, func(t *testing.T) {
+				ctx := context.Background()
+				s, done := newStore(t)
+				defer done()
+
+				// D9 isn't there, so it starts from D1 too
+				d, err := s.GetDeviceByBuildingAndRoomAndName(ctx, "ITB", "1101", "D1")
+				if err != nil {
+					t.Fatal(err)
+				}
+				test.change(&d)
+
+				_, err = s.UpdateDevice(ctx, "ITB", "1101", test.device, d)
+				checkKind(t, "UpdateDevice", err, test.kind)
+				if test.kind == accessors.NotFound {
+					return
+				}
+
+				// failed updates leave the device as it was
+				roles, err := s.GetRolesByDeviceID(ctx, 1)
+				if err != nil {
+					t.Fatal(err)
+				}
+				sort.Strings(roles)
+				if !reflect.DeepEqual(roles, test.roles) {
+					t.Errorf("roles = %v, want %v", roles, test.roles)
+				}
+
+				powerStates, err := s.GetPowerStatesByDeviceID(ctx, 1)
+				if err != nil {
+					t.Fatal(err)
+				}
+				sort.Strings(powerStates)
+				if !reflect.DeepEqual(powerStates, test.powerStates) {
+					t.Errorf("power states = %v, want %v", powerStates, test.powerStates)
+				}
+
+				// the other devices keep theirs
+				roles, err = s.GetRolesByDeviceID(ctx, 4)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(roles) != 2 {
+					t.Errorf("ITB-1001D-D1's roles = %v, want 2 of them", roles)
+				}
+			})
+		}
+	}
+}
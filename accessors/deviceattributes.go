@@ -0,0 +1,189 @@
+package accessors
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// GetDeviceAttributes returns a device's attributes, sorted by name, including the defaults from
+// its type's schema for the ones it hasn't set
+func (accessorGroup *AccessorGroup) GetDeviceAttributes(ctx context.Context, buildingShortname string, roomName string, deviceName string) ([]structs.DeviceAttribute, error) {
+	id, err := accessorGroup.deviceID(ctx, buildingShortname, roomName, deviceName)
+	if err != nil {
+		return []structs.DeviceAttribute{}, err
+	}
+
+	rows, err := accessorGroup.db().QueryContext(ctx, "SELECT name, type, value FROM DeviceAttributes WHERE deviceID = ? ORDER BY name", id)
+	if err != nil {
+		return []structs.DeviceAttribute{}, err
+	}
+	defer rows.Close()
+
+	toReturn := []structs.DeviceAttribute{}
+	for rows.Next() {
+		attribute := structs.DeviceAttribute{}
+		var text string
+
+		err = rows.Scan(&attribute.Name, &attribute.Type, &text)
+		if err != nil {
+			return []structs.DeviceAttribute{}, err
+		}
+
+		attribute.Value, err = DecodeAttributeValue(attribute.Type, text)
+		if err != nil {
+			return []structs.DeviceAttribute{}, err
+		}
+
+		toReturn = append(toReturn, attribute)
+	}
+	if err = rows.Err(); err != nil {
+		return []structs.DeviceAttribute{}, err
+	}
+	rows.Close()
+
+	_, schema, err := accessorGroup.deviceSchema(ctx, id)
+	if err != nil {
+		return []structs.DeviceAttribute{}, err
+	}
+
+	set := make(map[string]bool)
+	for _, attribute := range toReturn {
+		set[attribute.Name] = true
+	}
+	toReturn = append(toReturn, AttributeDefaults(schema, set)...)
+
+	sort.Slice(toReturn, func(i, j int) bool {
+		return toReturn[i].Name < toReturn[j].Name
+	})
+
+	return toReturn, nil
+}
+
+// PutDeviceAttribute sets an attribute on a device, adding it if the device doesn't have it yet
+func (accessorGroup *AccessorGroup) PutDeviceAttribute(ctx context.Context, buildingShortname string, roomName string, deviceName string, attribute structs.DeviceAttribute) (saved structs.DeviceAttribute, err error) {
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		id, err := tx.deviceID(ctx, buildingShortname, roomName, deviceName)
+		if err != nil {
+			return err
+		}
+
+		saved, err = tx.putDeviceAttribute(ctx, id, attribute)
+		return err
+	})
+
+	return saved, err
+}
+
+// DeleteDeviceAttribute removes an attribute from a device
+func (accessorGroup *AccessorGroup) DeleteDeviceAttribute(ctx context.Context, buildingShortname string, roomName string, deviceName string, name string) error {
+	return accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		id, err := tx.deviceID(ctx, buildingShortname, roomName, deviceName)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.db().ExecContext(ctx, "DELETE FROM DeviceAttributes WHERE deviceID = ? AND name = ?", id, name)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return Errorf(NotFound, "%s-%s-%s has no attribute %s", buildingShortname, roomName, deviceName, name)
+		}
+
+		return nil
+	})
+}
+
+// GetDevicesByAttribute returns the devices that have an attribute, whether they set it or get it
+// from their type's default. If value isn't empty, only the devices whose attribute equals it
+// are returned.
+func (accessorGroup *AccessorGroup) GetDevicesByAttribute(ctx context.Context, name string, value string) ([]structs.Device, error) {
+	rows, err := accessorGroup.db().QueryContext(ctx, `SELECT deviceID, type, value FROM DeviceAttributes WHERE name = ?
+		UNION ALL
+		SELECT Devices.deviceID, DeviceTypeAttributes.type, DeviceTypeAttributes.defaultValue FROM Devices
+		JOIN DeviceTypeAttributes ON DeviceTypeAttributes.deviceTypeID = Devices.typeID
+		WHERE DeviceTypeAttributes.name = ? AND DeviceTypeAttributes.defaultValue IS NOT NULL
+		AND NOT EXISTS (SELECT 1 FROM DeviceAttributes WHERE DeviceAttributes.deviceID = Devices.deviceID AND DeviceAttributes.name = DeviceTypeAttributes.name)`, name, name)
+	if err != nil {
+		return []structs.Device{}, err
+	}
+	defer rows.Close()
+
+	ids := []interface{}{}
+	for rows.Next() {
+		var id int
+		var attributeType, stored string
+
+		err = rows.Scan(&id, &attributeType, &stored)
+		if err != nil {
+			return []structs.Device{}, err
+		}
+
+		if len(value) == 0 || AttributeMatches(attributeType, stored, value) {
+			ids = append(ids, id)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return []structs.Device{}, err
+	}
+	rows.Close()
+
+	allDevices := []structs.Device{}
+	for start := 0; start < len(ids); start += relationBatchSize {
+		end := start + relationBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		in := "(" + strings.TrimSuffix(strings.Repeat("?,", end-start), ",") + ")"
+		devices, err := accessorGroup.GetDevicesByQuery(ctx, "WHERE Devices.deviceID IN "+in, ids[start:end]...)
+		if err != nil {
+			return []structs.Device{}, err
+		}
+
+		allDevices = append(allDevices, devices...)
+	}
+
+	return allDevices, nil
+}
+
+// putDeviceAttribute inserts or updates an attribute on the device with the given ID, once it's
+// checked it against the schema of the device's type
+func (accessorGroup *AccessorGroup) putDeviceAttribute(ctx context.Context, deviceID int, attribute structs.DeviceAttribute) (structs.DeviceAttribute, error) {
+	typeName, schema, err := accessorGroup.deviceSchema(ctx, deviceID)
+	if err != nil {
+		return structs.DeviceAttribute{}, err
+	}
+
+	attribute, err = ValidateAttribute(typeName, schema, attribute)
+	if err != nil {
+		return structs.DeviceAttribute{}, err
+	}
+	attribute.IsDefault = false
+	text := EncodeAttributeValue(attribute.Value)
+
+	var count int
+	err = accessorGroup.db().QueryRowContext(ctx, "SELECT COUNT(*) FROM DeviceAttributes WHERE deviceID = ? AND name = ?", deviceID, attribute.Name).Scan(&count)
+	if err != nil {
+		return structs.DeviceAttribute{}, err
+	}
+
+	if count > 0 {
+		_, err = accessorGroup.db().ExecContext(ctx, "UPDATE DeviceAttributes SET type = ?, value = ? WHERE deviceID = ? AND name = ?", attribute.Type, text, deviceID, attribute.Name)
+	} else {
+		_, err = accessorGroup.db().ExecContext(ctx, "INSERT INTO DeviceAttributes (deviceID, name, type, value) VALUES (?, ?, ?, ?)", deviceID, attribute.Name, attribute.Type, text)
+	}
+	if err != nil {
+		return structs.DeviceAttribute{}, err
+	}
+
+	return attribute, nil
+}
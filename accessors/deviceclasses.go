@@ -1,6 +1,7 @@
 package accessors
 
 import (
+	"context"
 	"database/sql"
 	"log"
 
@@ -8,11 +9,11 @@ import (
 )
 
 //GetDeviceClasses returns a dump of the table in the database
-func (accessorGroup *AccessorGroup) GetDeviceClasses() ([]structs.DeviceType, error) {
+func (accessorGroup *AccessorGroup) GetDeviceClasses(ctx context.Context) ([]structs.DeviceType, error) {
 
 	var DeviceClasses []structs.DeviceType
 
-	rows, err := accessorGroup.Database.Query("SELECT * FROM DeviceClasses")
+	rows, err := accessorGroup.db().QueryContext(ctx, "SELECT * FROM DeviceClasses")
 	if err != nil {
 		return []structs.DeviceType{}, err
 	}
@@ -26,8 +27,8 @@ func (accessorGroup *AccessorGroup) GetDeviceClasses() ([]structs.DeviceType, er
 	return DeviceClasses, nil
 }
 
-func (accessorGroup *AccessorGroup) AddDeviceType(deviceType structs.DeviceType) (structs.DeviceType, error) {
-	result, err := accessorGroup.Database.Exec("Insert into DeviceClasses (deviceClassID, name, description) VALUES(?,?,?)", deviceType.ID, deviceType.Name, deviceType.Description)
+func (accessorGroup *AccessorGroup) AddDeviceType(ctx context.Context, deviceType structs.DeviceType) (structs.DeviceType, error) {
+	result, err := accessorGroup.db().ExecContext(ctx, "Insert into DeviceClasses (deviceClassID, name, description) VALUES(?,?,?)", cleanID(deviceType.ID), deviceType.Name, deviceType.Description)
 	if err != nil {
 		return structs.DeviceType{}, err
 	}
@@ -41,8 +42,8 @@ func (accessorGroup *AccessorGroup) AddDeviceType(deviceType structs.DeviceType)
 	return deviceType, nil
 }
 
-func (accessorGroup *AccessorGroup) GetDeviceTypeByID(id int) (structs.DeviceType, error) {
-	row := accessorGroup.Database.QueryRow("SELECT * FROM DeviceClasses WHERE deviceClassID = ?", id)
+func (accessorGroup *AccessorGroup) GetDeviceTypeByID(ctx context.Context, id int) (structs.DeviceType, error) {
+	row := accessorGroup.db().QueryRowContext(ctx, "SELECT * FROM DeviceClasses WHERE deviceClassID = ?", id)
 
 	dt, err := extractDeviceType(row)
 	if err != nil {
@@ -52,8 +53,8 @@ func (accessorGroup *AccessorGroup) GetDeviceTypeByID(id int) (structs.DeviceTyp
 	return dt, nil
 }
 
-func (accessorGroup *AccessorGroup) GetDeviceTypeByName(name string) (structs.DeviceType, error) {
-	row := accessorGroup.Database.QueryRow("SELECT * FROM DeviceClasses WHERE name = ?", name)
+func (accessorGroup *AccessorGroup) GetDeviceTypeByName(ctx context.Context, name string) (structs.DeviceType, error) {
+	row := accessorGroup.db().QueryRowContext(ctx, "SELECT * FROM DeviceClasses WHERE name = ?", name)
 
 	dt, err := extractDeviceType(row)
 	if err != nil {
@@ -1,13 +1,14 @@
 package accessors
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/byuoitav/configuration-database-microservice/structs"
 )
 
-func (accessorGroup *AccessorGroup) GetDevicePowerStates() ([]structs.DevicePowerState, error) {
-	rows, err := accessorGroup.Database.Query("SELECT * FROM DevicePowerStates")
+func (accessorGroup *AccessorGroup) GetDevicePowerStates(ctx context.Context) ([]structs.DevicePowerState, error) {
+	rows, err := accessorGroup.db().QueryContext(ctx, "SELECT * FROM DevicePowerStates")
 	if err != nil {
 		return []structs.DevicePowerState{}, err
 	}
@@ -21,8 +22,8 @@ func (accessorGroup *AccessorGroup) GetDevicePowerStates() ([]structs.DevicePowe
 	return devicepowerstates, nil
 }
 
-func (accessorGroup *AccessorGroup) AddDevicePowerState(dps structs.DevicePowerState) (structs.DevicePowerState, error) {
-	response, err := accessorGroup.Database.Exec("INSERT INTO DevicePowerStates (devicePowerStateID, deviceID, powerStateID) VALUES(?,?,?)", dps.ID, dps.DeviceID, dps.PowerStateID)
+func (accessorGroup *AccessorGroup) AddDevicePowerState(ctx context.Context, dps structs.DevicePowerState) (structs.DevicePowerState, error) {
+	response, err := accessorGroup.db().ExecContext(ctx, "INSERT INTO DevicePowerStates (devicePowerStateID, deviceID, powerStateID) VALUES(?,?,?)", cleanID(dps.ID), dps.DeviceID, dps.PowerStateID)
 	if err != nil {
 		return structs.DevicePowerState{}, err
 	}
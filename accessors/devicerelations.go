@@ -0,0 +1,279 @@
+package accessors
+
+import (
+	"context"
+	"strings"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// relationBatchSize caps how many device IDs go in one IN list; SQLite allows 999 parameters by default
+const relationBatchSize = 500
+
+// fillDeviceRelations loads the commands, ports, power states, roles and attributes of every
+// device with one query per relation (for each relationBatchSize devices), rather than five per
+// device.
+// Relations the group wasn't asked to expand aren't queried.
+func (accessorGroup *AccessorGroup) fillDeviceRelations(ctx context.Context, devices []structs.Device) error {
+	for start := 0; start < len(devices); start += relationBatchSize {
+		end := start + relationBatchSize
+		if end > len(devices) {
+			end = len(devices)
+		}
+
+		err := accessorGroup.fillDeviceRelationsBatch(ctx, devices[start:end])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (accessorGroup *AccessorGroup) fillDeviceRelationsBatch(ctx context.Context, devices []structs.Device) error {
+	ids := make([]interface{}, len(devices))
+	for i, device := range devices {
+		ids[i] = device.ID
+	}
+	in := "(" + strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",") + ")"
+
+	expand := accessorGroup.expansion()
+
+	var commands map[int][]structs.Command
+	var ports map[int][]structs.Port
+	var powerStates, roles map[int][]string
+	var attributes map[int]map[string]interface{}
+	var err error
+
+	if expand.Commands {
+		commands, err = accessorGroup.commandsByDevice(ctx, in, ids)
+		if err != nil {
+			return err
+		}
+	}
+
+	if expand.Ports {
+		ports, err = accessorGroup.portsByDevice(ctx, in, ids)
+		if err != nil {
+			return err
+		}
+	}
+
+	if expand.PowerStates {
+		powerStates, err = accessorGroup.namesByDevice(ctx, `SELECT DevicePowerStates.deviceID, PowerStates.name FROM PowerStates
+	JOIN DevicePowerStates on DevicePowerStates.powerStateID = PowerStates.powerStateID
+	WHERE DevicePowerStates.deviceID IN `+in, ids)
+		if err != nil {
+			return err
+		}
+	}
+
+	if expand.Roles {
+		roles, err = accessorGroup.namesByDevice(ctx, `SELECT dr.deviceID, DeviceRoleDefinition.name FROM DeviceRoleDefinition
+	JOIN DeviceRole dr on dr.deviceRoleDefinitionID = DeviceRoleDefinition.deviceRoleDefinitionID
+	WHERE dr.deviceID IN `+in, ids)
+		if err != nil {
+			return err
+		}
+	}
+
+	if expand.Attributes {
+		attributes, err = accessorGroup.attributesByDevice(ctx, in, ids)
+		if err != nil {
+			return err
+		}
+	}
+
+	// match what the one-device-at-a-time lookups returned: no commands is nil, the rest are
+	// empty lists. Relations that weren't expanded stay nil.
+	for i := range devices {
+		id := devices[i].ID
+
+		if expand.Commands {
+			devices[i].Commands = commands[id]
+		}
+
+		if expand.Ports {
+			devices[i].Ports = ports[id]
+			if devices[i].Ports == nil {
+				devices[i].Ports = []structs.Port{}
+			}
+		}
+
+		if expand.PowerStates {
+			devices[i].PowerStates = powerStates[id]
+			if devices[i].PowerStates == nil {
+				devices[i].PowerStates = []string{}
+			}
+		}
+
+		if expand.Roles {
+			devices[i].Roles = roles[id]
+			if devices[i].Roles == nil {
+				devices[i].Roles = []string{}
+			}
+		}
+
+		if expand.Attributes {
+			devices[i].Attributes = attributes[id]
+			if devices[i].Attributes == nil {
+				devices[i].Attributes = map[string]interface{}{}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (accessorGroup *AccessorGroup) commandsByDevice(ctx context.Context, in string, ids []interface{}) (map[int][]structs.Command, error) {
+	rows, err := accessorGroup.db().QueryContext(ctx, `SELECT Devices.deviceID, Commands.name as commandName, Endpoints.name as endpointName, Endpoints.path as endpointPath, Microservices.address as microserviceAddress
+	FROM Devices
+	JOIN DeviceTypeCommandMapping TypeCommands on TypeCommands.deviceTypeID = Devices.typeID
+	JOIN Commands on TypeCommands.commandID = Commands.commandID
+	JOIN Endpoints on TypeCommands.endpointID = Endpoints.endpointID
+	JOIN Microservices ON TypeCommands.microserviceID = Microservices.microserviceID
+	WHERE Devices.deviceID IN `+in, ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	toReturn := make(map[int][]structs.Command)
+	for rows.Next() {
+		var deviceID int
+		command := structs.Command{}
+
+		err = rows.Scan(&deviceID, &command.Name, &command.Endpoint.Name, &command.Endpoint.Path, &command.Microservice)
+		if err != nil {
+			return nil, err
+		}
+
+		toReturn[deviceID] = append(toReturn[deviceID], command)
+	}
+
+	return toReturn, rows.Err()
+}
+
+// portsByDevice returns the ports configured on each host device
+func (accessorGroup *AccessorGroup) portsByDevice(ctx context.Context, in string, ids []interface{}) (map[int][]structs.Port, error) {
+	rows, err := accessorGroup.db().QueryContext(ctx, `SELECT hostDevice.deviceID, srcDevice.Name as sourceName, Ports.name as portName, destDevice.Name as DestinationDevice, hostDevice.name as HostDevice FROM Ports
+	JOIN PortConfiguration ON Ports.PortID = PortConfiguration.PortID
+	JOIN Devices as srcDevice on srcDevice.DeviceID = PortConfiguration.sourceDeviceID
+	JOIN Devices as destDevice on destDevice.DeviceID = PortConfiguration.destinationDeviceID
+	JOIN Devices as hostDevice on hostDevice.DeviceID = PortConfiguration.hostDeviceID
+	WHERE hostDevice.deviceID IN `+in, ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	toReturn := make(map[int][]structs.Port)
+	for rows.Next() {
+		var deviceID int
+		port := structs.Port{}
+
+		err = rows.Scan(&deviceID, &port.Source, &port.Name, &port.Destination, &port.Host)
+		if err != nil {
+			return nil, err
+		}
+
+		toReturn[deviceID] = append(toReturn[deviceID], port)
+	}
+
+	return toReturn, rows.Err()
+}
+
+// attributesByDevice returns each device's attributes, keyed by name, along with the defaults its
+// type gives it
+func (accessorGroup *AccessorGroup) attributesByDevice(ctx context.Context, in string, ids []interface{}) (map[int]map[string]interface{}, error) {
+	rows, err := accessorGroup.db().QueryContext(ctx, "SELECT deviceID, name, type, value FROM DeviceAttributes WHERE deviceID IN "+in, ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	toReturn := make(map[int]map[string]interface{})
+	for rows.Next() {
+		var deviceID int
+		var name, attributeType, text string
+
+		err = rows.Scan(&deviceID, &name, &attributeType, &text)
+		if err != nil {
+			return nil, err
+		}
+
+		// a row that doesn't decode was edited by hand; leave it out rather than fail every lookup
+		value, err := DecodeAttributeValue(attributeType, text)
+		if err != nil {
+			continue
+		}
+
+		if toReturn[deviceID] == nil {
+			toReturn[deviceID] = make(map[string]interface{})
+		}
+		toReturn[deviceID][name] = value
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	// then the defaults from each device's type, for the attributes it hasn't set
+	rows, err = accessorGroup.db().QueryContext(ctx, `SELECT Devices.deviceID, DeviceTypeAttributes.name, DeviceTypeAttributes.type, DeviceTypeAttributes.defaultValue FROM Devices
+		JOIN DeviceTypeAttributes ON DeviceTypeAttributes.deviceTypeID = Devices.typeID
+		WHERE DeviceTypeAttributes.defaultValue IS NOT NULL AND Devices.deviceID IN `+in, ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var deviceID int
+		var name, attributeType, text string
+
+		err = rows.Scan(&deviceID, &name, &attributeType, &text)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := toReturn[deviceID][name]; ok {
+			continue
+		}
+
+		value, err := DecodeAttributeValue(attributeType, text)
+		if err != nil {
+			continue
+		}
+
+		if toReturn[deviceID] == nil {
+			toReturn[deviceID] = make(map[string]interface{})
+		}
+		toReturn[deviceID][name] = value
+	}
+
+	return toReturn, rows.Err()
+}
+
+// namesByDevice runs a query returning (deviceID, name) pairs and groups the names by device
+func (accessorGroup *AccessorGroup) namesByDevice(ctx context.Context, query string, ids []interface{}) (map[int][]string, error) {
+	rows, err := accessorGroup.db().QueryContext(ctx, query, ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	toReturn := make(map[int][]string)
+	for rows.Next() {
+		var deviceID int
+		var name string
+
+		err = rows.Scan(&deviceID, &name)
+		if err != nil {
+			return nil, err
+		}
+
+		toReturn[deviceID] = append(toReturn[deviceID], name)
+	}
+
+	return toReturn, rows.Err()
+}
@@ -0,0 +1,212 @@
+//go:build cgo
+// +build cgo
+
+package accessors
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/byuoitav/configuration-database-microservice/migrations"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// newRelationsFixture migrates a new SQLite database and fills one room with n devices, each
+// with relations that depend on its index so a batch mixing them up shows: even devices are
+// SonyXBR VideoOuts that are On, odd ones are Pulse AudioOuts in Standby with a ChangeInput
+// command too. Device i hosts port hdmi1 from itself to device i+1, and has an int attribute
+// "index" of i. It returns the group, the devices without their relations, and a func that
+// deletes the database.
+func newRelationsFixture(tb testing.TB, n int) (*AccessorGroup, []structs.Device, func()) {
+	dir, err := ioutil.TempDir("", "devicerelations")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	done := func() { os.RemoveAll(dir) }
+
+	accessorGroup := new(AccessorGroup)
+	accessorGroup.OpenSQLite(filepath.Join(dir, "fixture.db"))
+
+	fail := func(err error) {
+		accessorGroup.Database.Close()
+		done()
+		tb.Fatal(err)
+	}
+
+	err = migrations.Up(accessorGroup.Database, migrations.SQLite)
+	if err != nil {
+		fail(err)
+	}
+
+	tx, err := accessorGroup.Database.Begin()
+	if err != nil {
+		fail(err)
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		"INSERT INTO Buildings (buildingID, name, shortName, description) VALUES (1, 'Info Tech Building', 'ITB', '')",
+		"INSERT INTO Rooms (roomID, name, buildingID, description, roomDesignation) VALUES (1, '1101', 1, '', 'stage')",
+		"INSERT INTO DeviceClasses (deviceClassID, name, description) VALUES (1, 'display', '')",
+		"INSERT INTO DeviceTypes (deviceTypeID, typeName) VALUES (1, 'SonyXBR'), (2, 'Pulse')",
+		"INSERT INTO Commands (commandID, name, description, priority) VALUES (1, 'PowerOn', '', 1), (2, 'ChangeInput', '', 2)",
+		"INSERT INTO Endpoints (endpointID, name, path, description) VALUES (1, 'PowerOn', '/:address/power/on', '')",
+		"INSERT INTO Microservices (microserviceID, name, address, description) VALUES (1, 'sony', 'http://localhost:8007', '')",
+		`INSERT INTO DeviceTypeCommandMapping (deviceTypeID, commandID, microserviceID, endpointID) VALUES
+			(1, 1, 1, 1), (2, 1, 1, 1), (2, 2, 1, 1)`,
+		"INSERT INTO Ports (portID, name, description) VALUES (1, 'hdmi1', '')",
+	}
+	for _, statement := range statements {
+		_, err = tx.Exec(statement)
+		if err != nil {
+			fail(err)
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		role, powerState := "VideoOut", "On"
+		if i%2 == 1 {
+			role, powerState = "AudioOut", "Standby"
+		}
+
+		deviceStatements := []struct {
+			query string
+			args  []interface{}
+		}{
+			{"INSERT INTO Devices (deviceID, name, address, input, output, buildingID, roomID, classID, typeID, displayName) VALUES (?, ?, '', 0, 1, 1, 1, 1, ?, '')",
+				[]interface{}{i + 1, fmt.Sprintf("D%d", i), i%2 + 1}},
+			{"INSERT INTO DeviceRole (deviceID, deviceRoleDefinitionID) SELECT ?, deviceRoleDefinitionID FROM DeviceRoleDefinition WHERE name = ?",
+				[]interface{}{i + 1, role}},
+			{"INSERT INTO DevicePowerStates (deviceID, powerStateID) SELECT ?, powerStateID FROM PowerStates WHERE name = ?",
+				[]interface{}{i + 1, powerState}},
+			{"INSERT INTO DeviceAttributes (deviceID, name, type, value) VALUES (?, 'index', 'int', ?)",
+				[]interface{}{i + 1, fmt.Sprint(i)}},
+		}
+		for _, statement := range deviceStatements {
+			_, err = tx.Exec(statement.query, statement.args...)
+			if err != nil {
+				fail(err)
+			}
+		}
+	}
+
+	// the port configurations point at the device after them, so they go in once they all exist
+	for i := 0; i < n; i++ {
+		_, err = tx.Exec("INSERT INTO PortConfiguration (destinationDeviceID, portID, sourceDeviceID, hostDeviceID) VALUES (?, 1, ?, ?)",
+			(i+1)%n+1, i+1, i+1)
+		if err != nil {
+			fail(err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		fail(err)
+	}
+
+	devices, err := accessorGroup.WithExpansion(Expand{}).(*AccessorGroup).GetDevicesByQuery(context.Background(), "ORDER BY Devices.deviceID")
+	if err != nil {
+		fail(err)
+	}
+	if len(devices) != n {
+		fail(fmt.Errorf("loaded %d devices, want %d", len(devices), n))
+	}
+
+	return accessorGroup, devices, func() {
+		accessorGroup.Database.Close()
+		done()
+	}
+}
+
+// TestFillDeviceRelationsBatches loads the relations of more devices than fit in one batch, and
+// checks each device gets its own, the same as loading it on its own would give it
+func TestFillDeviceRelationsBatches(t *testing.T) {
+	n := 2*relationBatchSize + 1
+	accessorGroup, devices, done := newRelationsFixture(t, n)
+	defer done()
+
+	ctx := context.Background()
+
+	batched := append([]structs.Device{}, devices...)
+	err := accessorGroup.fillDeviceRelations(ctx, batched)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, device := range batched {
+		want := struct {
+			roles, powerStates []string
+			commands           int
+			destination        string
+		}{[]string{"VideoOut"}, []string{"On"}, 1, fmt.Sprintf("D%d", (i+1)%n)}
+		if i%2 == 1 {
+			want.roles, want.powerStates, want.commands = []string{"AudioOut"}, []string{"Standby"}, 2
+		}
+
+		if !reflect.DeepEqual(device.Roles, want.roles) {
+			t.Errorf("%s: roles = %v, want %v", device.Name, device.Roles, want.roles)
+		}
+		if !reflect.DeepEqual(device.PowerStates, want.powerStates) {
+			t.Errorf("%s: power states = %v, want %v", device.Name, device.PowerStates, want.powerStates)
+		}
+		if len(device.Commands) != want.commands {
+			t.Errorf("%s: %d commands, want %d", device.Name, len(device.Commands), want.commands)
+		}
+		if len(device.Ports) != 1 || device.Ports[0].Destination != want.destination {
+			t.Errorf("%s: ports = %+v, want one to %s", device.Name, device.Ports, want.destination)
+		}
+		if device.Attributes["index"] != i {
+			t.Errorf("%s: index = %v, want %d", device.Name, device.Attributes["index"], i)
+		}
+	}
+
+	// one at a time, which is what fillDeviceRelations replaced
+	for i := range devices {
+		alone := []structs.Device{devices[i]}
+		err = accessorGroup.fillDeviceRelations(ctx, alone)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(alone[0], batched[i]) {
+			t.Errorf("%s: batched %+v, alone %+v", devices[i].Name, batched[i], alone[0])
+		}
+	}
+}
+
+// BenchmarkFillDeviceRelations compares loading every device's relations a device at a time
+// (a query or two per relation each) with loading them in batches
+func BenchmarkFillDeviceRelations(b *testing.B) {
+	ctx := context.Background()
+
+	for _, n := range []int{10, 100, 1000} {
+		accessorGroup, devices, done := newRelationsFixture(b, n)
+
+		b.Run(fmt.Sprintf("per-device/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for j := range devices {
+					err := accessorGroup.fillDeviceRelations(ctx, devices[j:j+1])
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("batched/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				err := accessorGroup.fillDeviceRelations(ctx, devices)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		done()
+	}
+}
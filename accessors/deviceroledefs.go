@@ -1,14 +1,15 @@
 package accessors
 
 import (
+	"context"
 	"database/sql"
 	"log"
 
 	"github.com/byuoitav/configuration-database-microservice/structs"
 )
 
-func (accessorGroup *AccessorGroup) GetDeviceRoleDefs() ([]structs.DeviceRoleDef, error) {
-	rows, err := accessorGroup.Database.Query("SELECT * FROM DeviceRoleDefinition")
+func (accessorGroup *AccessorGroup) GetDeviceRoleDefs(ctx context.Context) ([]structs.DeviceRoleDef, error) {
+	rows, err := accessorGroup.db().QueryContext(ctx, "SELECT * FROM DeviceRoleDefinition")
 	if err != nil {
 		return []structs.DeviceRoleDef{}, err
 	}
@@ -22,8 +23,8 @@ func (accessorGroup *AccessorGroup) GetDeviceRoleDefs() ([]structs.DeviceRoleDef
 	return deviceroledefs, nil
 }
 
-func (accessorGroup *AccessorGroup) AddDeviceRoleDef(deviceroledef structs.DeviceRoleDef) (structs.DeviceRoleDef, error) {
-	result, err := accessorGroup.Database.Exec("Insert into DeviceRoleDefinition (deviceRoleDefinitionID, name, description) VALUES(?,?,?)", deviceroledef.ID, deviceroledef.Name, deviceroledef.Description)
+func (accessorGroup *AccessorGroup) AddDeviceRoleDef(ctx context.Context, deviceroledef structs.DeviceRoleDef) (structs.DeviceRoleDef, error) {
+	result, err := accessorGroup.db().ExecContext(ctx, "Insert into DeviceRoleDefinition (deviceRoleDefinitionID, name, description) VALUES(?,?,?)", cleanID(deviceroledef.ID), deviceroledef.Name, deviceroledef.Description)
 	if err != nil {
 		return structs.DeviceRoleDef{}, err
 	}
@@ -37,8 +38,8 @@ func (accessorGroup *AccessorGroup) AddDeviceRoleDef(deviceroledef structs.Devic
 	return deviceroledef, nil
 }
 
-func (accessorGroup *AccessorGroup) GetDeviceRoleDefByID(id int) (structs.DeviceRoleDef, error) {
-	row := accessorGroup.Database.QueryRow("SELECT * FROM DeviceRoleDefinition WHERE deviceRoleDefinitionID = ? ", id)
+func (accessorGroup *AccessorGroup) GetDeviceRoleDefByID(ctx context.Context, id int) (structs.DeviceRoleDef, error) {
+	row := accessorGroup.db().QueryRowContext(ctx, "SELECT * FROM DeviceRoleDefinition WHERE deviceRoleDefinitionID = ? ", id)
 
 	drd, err := extractDeviceRoleDef(row)
 	if err != nil {
@@ -48,8 +49,8 @@ func (accessorGroup *AccessorGroup) GetDeviceRoleDefByID(id int) (structs.Device
 	return drd, nil
 }
 
-func (accessorGroup *AccessorGroup) GetDeviceRoleDefByName(name string) (structs.DeviceRoleDef, error) {
-	row := accessorGroup.Database.QueryRow("SELECT * FROM DeviceRoleDefinition WHERE name = ? ", name)
+func (accessorGroup *AccessorGroup) GetDeviceRoleDefByName(ctx context.Context, name string) (structs.DeviceRoleDef, error) {
+	row := accessorGroup.db().QueryRowContext(ctx, "SELECT * FROM DeviceRoleDefinition WHERE name = ? ", name)
 
 	drd, err := extractDeviceRoleDef(row)
 	if err != nil {
@@ -1,13 +1,14 @@
 package accessors
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/byuoitav/configuration-database-microservice/structs"
 )
 
-func (accessorGroup *AccessorGroup) GetDeviceRoles() ([]structs.DeviceRole, error) {
-	rows, err := accessorGroup.Database.Query("SELECT * FROM DeviceRole")
+func (accessorGroup *AccessorGroup) GetDeviceRoles(ctx context.Context) ([]structs.DeviceRole, error) {
+	rows, err := accessorGroup.db().QueryContext(ctx, "SELECT * FROM DeviceRole")
 	if err != nil {
 		return []structs.DeviceRole{}, err
 	}
@@ -21,8 +22,8 @@ func (accessorGroup *AccessorGroup) GetDeviceRoles() ([]structs.DeviceRole, erro
 	return deviceroles, nil
 }
 
-func (accessorGroup *AccessorGroup) AddDeviceRole(dr structs.DeviceRole) (structs.DeviceRole, error) {
-	response, err := accessorGroup.Database.Exec("INSERT INTO DeviceRole (deviceRoleID, deviceID, deviceRoleDefinitionID) VALUES(?,?,?)", dr.ID, dr.DeviceID, dr.DeviceRoleDefinitionID)
+func (accessorGroup *AccessorGroup) AddDeviceRole(ctx context.Context, dr structs.DeviceRole) (structs.DeviceRole, error) {
+	response, err := accessorGroup.db().ExecContext(ctx, "INSERT INTO DeviceRole (deviceRoleID, deviceID, deviceRoleDefinitionID) VALUES(?,?,?)", cleanID(dr.ID), dr.DeviceID, dr.DeviceRoleDefinitionID)
 	if err != nil {
 		return structs.DeviceRole{}, err
 	}
@@ -0,0 +1,390 @@
+package accessors
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// GetDeviceTypeAttributes returns the attribute schema of an entry in DeviceTypes, sorted by name
+func (accessorGroup *AccessorGroup) GetDeviceTypeAttributes(ctx context.Context, typeName string) ([]structs.DeviceTypeAttribute, error) {
+	typeID, err := accessorGroup.deviceTypeID(ctx, typeName)
+	if err != nil {
+		return []structs.DeviceTypeAttribute{}, err
+	}
+
+	return accessorGroup.attributeSchema(ctx, typeID)
+}
+
+// SetDeviceTypeAttributes replaces a device type's whole schema in one go, so a type whose devices
+// already have several attributes can declare them all at once. It's refused if any device of the
+// type has attributes the new schema wouldn't allow; an empty schema lets them have any attribute.
+func (accessorGroup *AccessorGroup) SetDeviceTypeAttributes(ctx context.Context, typeName string, schema []structs.DeviceTypeAttribute) (set []structs.DeviceTypeAttribute, err error) {
+	rules, err := NormalizeAttributeSchemas(schema)
+	if err != nil {
+		return []structs.DeviceTypeAttribute{}, err
+	}
+
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		typeID, err := tx.deviceTypeID(ctx, typeName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.db().ExecContext(ctx, "DELETE FROM DeviceTypeAttributes WHERE deviceTypeID = ?", typeID)
+		if err != nil {
+			return err
+		}
+
+		for _, rule := range rules {
+			enum, defaultValue := encodeAttributeRule(rule)
+			_, err = tx.db().ExecContext(ctx, "INSERT INTO DeviceTypeAttributes (deviceTypeID, name, type, description, minimum, maximum, enumValues, defaultValue) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+				typeID, rule.Name, rule.Type, rule.Description, rule.Minimum, rule.Maximum, enum, defaultValue)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = tx.checkTypeAttributes(ctx, typeID, typeName)
+		if err != nil {
+			return err
+		}
+
+		set, err = tx.attributeSchema(ctx, typeID)
+		return err
+	})
+	if err != nil {
+		return []structs.DeviceTypeAttribute{}, err
+	}
+
+	return set, nil
+}
+
+// AddDeviceTypeAttribute adds an attribute to a device type's schema. It's refused if any device
+// of the type has attributes the new schema wouldn't allow.
+func (accessorGroup *AccessorGroup) AddDeviceTypeAttribute(ctx context.Context, typeName string, rule structs.DeviceTypeAttribute) (added structs.DeviceTypeAttribute, err error) {
+	added, err = NormalizeAttributeSchema(rule)
+	if err != nil {
+		return structs.DeviceTypeAttribute{}, err
+	}
+
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		typeID, err := tx.deviceTypeID(ctx, typeName)
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = tx.db().QueryRowContext(ctx, "SELECT COUNT(*) FROM DeviceTypeAttributes WHERE deviceTypeID = ? AND name = ?", typeID, added.Name).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return Errorf(Conflict, "%s already has an attribute called %s", typeName, added.Name)
+		}
+
+		enum, defaultValue := encodeAttributeRule(added)
+		_, err = tx.db().ExecContext(ctx, "INSERT INTO DeviceTypeAttributes (deviceTypeID, name, type, description, minimum, maximum, enumValues, defaultValue) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			typeID, added.Name, added.Type, added.Description, added.Minimum, added.Maximum, enum, defaultValue)
+		if err != nil {
+			return err
+		}
+
+		return tx.checkTypeAttributes(ctx, typeID, typeName)
+	})
+	if err != nil {
+		return structs.DeviceTypeAttribute{}, err
+	}
+
+	return added, nil
+}
+
+// UpdateDeviceTypeAttribute replaces the rules for an attribute in a device type's schema. It's
+// refused if any device of the type has a value the new rules wouldn't allow.
+func (accessorGroup *AccessorGroup) UpdateDeviceTypeAttribute(ctx context.Context, typeName string, name string, rule structs.DeviceTypeAttribute) (updated structs.DeviceTypeAttribute, err error) {
+	rule.Name = name
+	updated, err = NormalizeAttributeSchema(rule)
+	if err != nil {
+		return structs.DeviceTypeAttribute{}, err
+	}
+
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		typeID, err := tx.deviceTypeID(ctx, typeName)
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = tx.db().QueryRowContext(ctx, "SELECT COUNT(*) FROM DeviceTypeAttributes WHERE deviceTypeID = ? AND name = ?", typeID, name).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return Errorf(NotFound, "%s has no attribute called %s", typeName, name)
+		}
+
+		enum, defaultValue := encodeAttributeRule(updated)
+		_, err = tx.db().ExecContext(ctx, "UPDATE DeviceTypeAttributes SET type = ?, description = ?, minimum = ?, maximum = ?, enumValues = ?, defaultValue = ? WHERE deviceTypeID = ? AND name = ?",
+			updated.Type, updated.Description, updated.Minimum, updated.Maximum, enum, defaultValue, typeID, name)
+		if err != nil {
+			return err
+		}
+
+		return tx.checkTypeAttributes(ctx, typeID, typeName)
+	})
+	if err != nil {
+		return structs.DeviceTypeAttribute{}, err
+	}
+
+	return updated, nil
+}
+
+// DeleteDeviceTypeAttribute removes an attribute from a device type's schema. While the schema
+// has other attributes, devices of the type can't keep one it no longer lists, so it's refused
+// if any of them have it. Removing the last one lets the type's devices have any attribute.
+func (accessorGroup *AccessorGroup) DeleteDeviceTypeAttribute(ctx context.Context, typeName string, name string) error {
+	return accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		typeID, err := tx.deviceTypeID(ctx, typeName)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.db().ExecContext(ctx, "DELETE FROM DeviceTypeAttributes WHERE deviceTypeID = ? AND name = ?", typeID, name)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return Errorf(NotFound, "%s has no attribute called %s", typeName, name)
+		}
+
+		return tx.checkTypeAttributes(ctx, typeID, typeName)
+	})
+}
+
+// attributeSchema reads a device type's schema, sorted by name
+func (accessorGroup *AccessorGroup) attributeSchema(ctx context.Context, typeID int) ([]structs.DeviceTypeAttribute, error) {
+	rows, err := accessorGroup.db().QueryContext(ctx, `SELECT name, type, description, minimum, maximum, enumValues, defaultValue FROM DeviceTypeAttributes
+		WHERE deviceTypeID = ? ORDER BY name`, typeID)
+	if err != nil {
+		return []structs.DeviceTypeAttribute{}, err
+	}
+	defer rows.Close()
+
+	schema := []structs.DeviceTypeAttribute{}
+	for rows.Next() {
+		rule := structs.DeviceTypeAttribute{}
+		var description, enum, defaultValue sql.NullString
+		var minimum, maximum sql.NullFloat64
+
+		err = rows.Scan(&rule.Name, &rule.Type, &description, &minimum, &maximum, &enum, &defaultValue)
+		if err != nil {
+			return []structs.DeviceTypeAttribute{}, err
+		}
+
+		rule.Description = description.String
+		if minimum.Valid {
+			rule.Minimum = &minimum.Float64
+		}
+		if maximum.Valid {
+			rule.Maximum = &maximum.Float64
+		}
+		if len(enum.String) > 0 {
+			err = json.Unmarshal([]byte(enum.String), &rule.Enum)
+			if err != nil {
+				return []structs.DeviceTypeAttribute{}, fmt.Errorf("the enum of %s is corrupt: %s", rule.Name, err)
+			}
+		}
+		if defaultValue.Valid {
+			rule.Default = defaultValue.String
+		}
+
+		// puts the enum and default back in the attribute's type
+		rule, err = NormalizeAttributeSchema(rule)
+		if err != nil {
+			return []structs.DeviceTypeAttribute{}, err
+		}
+
+		schema = append(schema, rule)
+	}
+
+	return schema, rows.Err()
+}
+
+// deviceSchema returns the name and schema of a device's type
+func (accessorGroup *AccessorGroup) deviceSchema(ctx context.Context, deviceID int) (string, []structs.DeviceTypeAttribute, error) {
+	var typeID int
+	var typeName string
+	err := accessorGroup.db().QueryRowContext(ctx, `SELECT DeviceTypes.deviceTypeID, DeviceTypes.typeName FROM Devices
+		JOIN DeviceTypes ON DeviceTypes.deviceTypeID = Devices.typeID
+		WHERE Devices.deviceID = ?`, deviceID).Scan(&typeID, &typeName)
+	if KindOf(err) == NotFound {
+		// a device without a type has no schema to follow
+		return "", []structs.DeviceTypeAttribute{}, nil
+	} else if err != nil {
+		return "", []structs.DeviceTypeAttribute{}, err
+	}
+
+	schema, err := accessorGroup.attributeSchema(ctx, typeID)
+	return typeName, schema, err
+}
+
+// checkDeclaredAttribute checks a value for one of the audio device columns (volume or muted)
+// against the device type's schema, if it lists that name; the columns themselves aren't
+// device attributes, so a type that doesn't list them doesn't limit them
+func (accessorGroup *AccessorGroup) checkDeclaredAttribute(ctx context.Context, building string, room string, device string, name string, text string) error {
+	var deviceID int
+	err := accessorGroup.db().QueryRowContext(ctx, `SELECT Devices.deviceID FROM Devices
+		JOIN Rooms ON Rooms.roomID = Devices.roomID
+		JOIN Buildings ON Buildings.buildingID = Rooms.buildingID
+		WHERE Buildings.shortName = ? AND Rooms.name = ? AND Devices.name = ?`, building, room, device).Scan(&deviceID)
+	if KindOf(err) == NotFound {
+		// the update below matches nothing, same as before there were schemas
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	typeName, schema, err := accessorGroup.deviceSchema(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+
+	rule, ok := schemaRule(schema, name)
+	if !ok {
+		return nil
+	}
+
+	attribute, err := ParseAttribute(name, rule.Type, text)
+	if err != nil {
+		return err
+	}
+
+	_, err = ValidateAttribute(typeName, schema, attribute)
+	return err
+}
+
+// checkTypeAttributes makes sure every device of a type still fits its schema, returning a
+// Conflict listing the attributes that don't
+func (accessorGroup *AccessorGroup) checkTypeAttributes(ctx context.Context, typeID int, typeName string) error {
+	problems, err := accessorGroup.attributeProblems(ctx, "WHERE Devices.typeID = ?", typeID)
+	if err != nil {
+		return err
+	}
+
+	if len(problems) > 0 {
+		return &Error{
+			Kind:    Conflict,
+			Err:     fmt.Errorf("some %s devices have attributes the schema wouldn't allow; change them first", typeName),
+			Details: problems,
+		}
+	}
+
+	return nil
+}
+
+// checkDeviceAttributes makes sure a device's attributes fit the schema of its type, e.g. after
+// its type changes, returning a Validation error listing the ones that don't
+func (accessorGroup *AccessorGroup) checkDeviceAttributes(ctx context.Context, deviceID int) error {
+	problems, err := accessorGroup.attributeProblems(ctx, "WHERE Devices.deviceID = ?", deviceID)
+	if err != nil {
+		return err
+	}
+
+	if len(problems) > 0 {
+		return &Error{
+			Kind:    Validation,
+			Err:     fmt.Errorf("the device has attributes its type doesn't allow; change them first"),
+			Details: problems,
+		}
+	}
+
+	return nil
+}
+
+// attributeProblems checks the attributes of the devices matching condition against the
+// schemas of their types, returning a line for each one that doesn't fit
+func (accessorGroup *AccessorGroup) attributeProblems(ctx context.Context, condition string, args ...interface{}) ([]string, error) {
+	type stored struct {
+		device    structs.Device
+		typeID    int
+		attribute structs.DeviceAttribute
+		text      string
+	}
+
+	rows, err := accessorGroup.db().QueryContext(ctx, `SELECT Buildings.shortName, Rooms.name, Devices.name, Devices.typeID, DeviceTypes.typeName,
+		DeviceAttributes.name, DeviceAttributes.type, DeviceAttributes.value FROM DeviceAttributes
+		JOIN Devices ON Devices.deviceID = DeviceAttributes.deviceID
+		JOIN DeviceTypes ON DeviceTypes.deviceTypeID = Devices.typeID
+		JOIN Rooms ON Rooms.roomID = Devices.roomID
+		JOIN Buildings ON Buildings.buildingID = Rooms.buildingID
+		`+condition+`
+		ORDER BY Buildings.shortName, Rooms.name, Devices.name, DeviceAttributes.name`, args...)
+	if err != nil {
+		return []string{}, err
+	}
+	defer rows.Close()
+
+	all := []stored{}
+	for rows.Next() {
+		row := stored{}
+
+		err = rows.Scan(&row.device.Building.Shortname, &row.device.Room.Name, &row.device.Name, &row.typeID, &row.device.Class,
+			&row.attribute.Name, &row.attribute.Type, &row.text)
+		if err != nil {
+			return []string{}, err
+		}
+
+		all = append(all, row)
+	}
+	if err = rows.Err(); err != nil {
+		return []string{}, err
+	}
+	rows.Close()
+
+	problems := []string{}
+	schemas := make(map[int][]structs.DeviceTypeAttribute)
+	for _, row := range all {
+		schema, ok := schemas[row.typeID]
+		if !ok {
+			schema, err = accessorGroup.attributeSchema(ctx, row.typeID)
+			if err != nil {
+				return []string{}, err
+			}
+			schemas[row.typeID] = schema
+		}
+
+		row.attribute.Value, err = DecodeAttributeValue(row.attribute.Type, row.text)
+		if err == nil {
+			_, err = ValidateAttribute(row.device.Class, schema, row.attribute)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", row.device.GetFullName(), err))
+		}
+	}
+
+	return problems, nil
+}
+
+// encodeAttributeRule returns a rule's enum and default as they're stored; nil for ones it doesn't have
+func encodeAttributeRule(rule structs.DeviceTypeAttribute) (interface{}, interface{}) {
+	var enum, defaultValue interface{}
+
+	if len(rule.Enum) > 0 {
+		b, _ := json.Marshal(rule.Enum)
+		enum = string(b)
+	}
+
+	if rule.Default != nil {
+		defaultValue = EncodeAttributeValue(rule.Default)
+	}
+
+	return enum, defaultValue
+}
@@ -0,0 +1,139 @@
+package accessors_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+func TestSetDeviceTypeAttributes(t *testing.T) {
+	volume := structs.DeviceTypeAttribute{Name: "volume", Type: accessors.AttributeInt, Default: 30.0}
+	inputDelay := structs.DeviceTypeAttribute{Name: "inputDelay", Type: accessors.AttributeInt}
+
+	tests := []struct {
+		name     string
+		typeName string
+		schema   []structs.DeviceTypeAttribute
+		kind     accessors.Kind
+		// want is the names in the schema after, in order; a refused schema leaves the one
+		// the test starts with, volume and inputDelay
+		want []string
+	}{
+		{
+			name:     "replace",
+			typeName: "SonyXBR",
+			schema:   []structs.DeviceTypeAttribute{inputDelay, {Name: "aspect", Type: accessors.AttributeString}},
+			kind:     succeeds,
+			want:     []string{"aspect", "inputDelay"},
+		},
+		{
+			name:     "the same again",
+			typeName: "SonyXBR",
+			schema:   []structs.DeviceTypeAttribute{volume, inputDelay},
+			kind:     succeeds,
+			want:     []string{"inputDelay", "volume"},
+		},
+		{
+			name:     "clear it",
+			typeName: "SonyXBR",
+			schema:   []structs.DeviceTypeAttribute{},
+			kind:     succeeds,
+			want:     []string{},
+		},
+		{
+			name:     "name an attribute twice",
+			typeName: "SonyXBR",
+			schema:   []structs.DeviceTypeAttribute{inputDelay, volume, inputDelay},
+			kind:     accessors.Validation,
+			want:     []string{"inputDelay", "volume"},
+		},
+		{
+			name:     "with a bad rule",
+			typeName: "SonyXBR",
+			schema:   []structs.DeviceTypeAttribute{inputDelay, {Name: "volume", Type: accessors.AttributeInt, Minimum: new(float64), Default: -1.0}},
+			kind:     accessors.Validation,
+			want:     []string{"inputDelay", "volume"},
+		},
+		{
+			name:     "leave out an attribute a device has",
+			typeName: "SonyXBR",
+			schema:   []structs.DeviceTypeAttribute{volume},
+			kind:     accessors.Conflict,
+			want:     []string{"inputDelay", "volume"},
+		},
+		{
+			name:     "an enum a device's value isn't in",
+			typeName: "SonyXBR",
+			schema:   []structs.DeviceTypeAttribute{volume, {Name: "inputDelay", Type: accessors.AttributeInt, Enum: []interface{}{50.0, 100.0}}},
+			kind:     accessors.Conflict,
+			want:     []string{"inputDelay", "volume"},
+		},
+		{
+			name:     "a missing type",
+			typeName: "SonyBravia",
+			schema:   []structs.DeviceTypeAttribute{volume},
+			kind:     accessors.NotFound,
+			want:     []string{"inputDelay", "volume"},
+		},
+	}
+
+	for store, newStore := range testStores {
+		for _, test := range tests {
+			t.Run(store+"/"+test.name, func(t *testing.T) {
+				ctx := context.Background()
+				s, done := newStore(t)
+				defer done()
+
+				_, err := s.SetDeviceTypeAttributes(ctx, "SonyXBR", []structs.DeviceTypeAttribute{volume, inputDelay})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				_, err = s.SetDeviceTypeAttributes(ctx, test.typeName, test.schema)
+				checkKind(t, "SetDeviceTypeAttributes", err, test.kind)
+
+				schema, err := s.GetDeviceTypeAttributes(ctx, "SonyXBR")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				names := []string{}
+				for _, rule := range schema {
+					names = append(names, rule.Name)
+				}
+				if !reflect.DeepEqual(names, test.want) {
+					t.Fatalf("the schema has %v, want %v", names, test.want)
+				}
+
+				// D1 reads as having the default volume for as long as the schema gives one
+				attributes, err := s.GetDeviceAttributes(ctx, "ITB", "1101", "D1")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				hasDefault := false
+				for _, attribute := range attributes {
+					if attribute.Name == "volume" && attribute.IsDefault {
+						hasDefault = true
+					}
+				}
+				if wantDefault := contains(test.want, "volume"); hasDefault != wantDefault {
+					t.Errorf("D1 has the default volume: %v, want %v", hasDefault, wantDefault)
+				}
+			})
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
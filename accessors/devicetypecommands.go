@@ -0,0 +1,168 @@
+package accessors
+
+import (
+	"context"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// GetDeviceTypeCommands returns the commands mapped to an entry in DeviceTypes (what a device's
+// class shows), along with the devices of that type
+func (accessorGroup *AccessorGroup) GetDeviceTypeCommands(ctx context.Context, typeName string) (structs.DeviceTypeCommands, error) {
+	toReturn := structs.DeviceTypeCommands{Type: typeName, Commands: []structs.DeviceTypeCommand{}}
+
+	typeID, err := accessorGroup.deviceTypeID(ctx, typeName)
+	if err != nil {
+		return structs.DeviceTypeCommands{}, err
+	}
+
+	rows, err := accessorGroup.db().QueryContext(ctx, `SELECT Commands.name, Endpoints.name, Microservices.name FROM DeviceTypeCommandMapping
+		JOIN Commands ON Commands.commandID = DeviceTypeCommandMapping.commandID
+		JOIN Endpoints ON Endpoints.endpointID = DeviceTypeCommandMapping.endpointID
+		JOIN Microservices ON Microservices.microserviceID = DeviceTypeCommandMapping.microserviceID
+		WHERE DeviceTypeCommandMapping.deviceTypeID = ?
+		ORDER BY Commands.name`, typeID)
+	if err != nil {
+		return structs.DeviceTypeCommands{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		command := structs.DeviceTypeCommand{}
+
+		err = rows.Scan(&command.Command, &command.Endpoint, &command.Microservice)
+		if err != nil {
+			return structs.DeviceTypeCommands{}, err
+		}
+
+		toReturn.Commands = append(toReturn.Commands, command)
+	}
+	if err = rows.Err(); err != nil {
+		return structs.DeviceTypeCommands{}, err
+	}
+	rows.Close()
+
+	toReturn.Devices, err = accessorGroup.fullNames(ctx, "WHERE Devices.typeID = ?", typeID)
+	if err != nil {
+		return structs.DeviceTypeCommands{}, err
+	}
+
+	return toReturn, nil
+}
+
+// AddDeviceTypeCommand maps a command to an endpoint and microservice for every device of a type.
+// A type can only have one mapping per command.
+func (accessorGroup *AccessorGroup) AddDeviceTypeCommand(ctx context.Context, typeName string, command structs.DeviceTypeCommand) (structs.DeviceTypeCommand, error) {
+	err := accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		typeID, err := tx.deviceTypeID(ctx, typeName)
+		if err != nil {
+			return err
+		}
+
+		commandID, err := tx.idByName(ctx, "SELECT commandID FROM Commands WHERE name = ?", "command", command.Command)
+		if err != nil {
+			return err
+		}
+
+		endpointID, err := tx.idByName(ctx, "SELECT endpointID FROM Endpoints WHERE name = ?", "endpoint", command.Endpoint)
+		if err != nil {
+			return err
+		}
+
+		microserviceID, err := tx.idByName(ctx, "SELECT microserviceID FROM Microservices WHERE name = ?", "microservice", command.Microservice)
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = tx.db().QueryRowContext(ctx, "SELECT COUNT(*) FROM DeviceTypeCommandMapping WHERE deviceTypeID = ? AND commandID = ?", typeID, commandID).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return Errorf(Conflict, "%s already has a mapping for %s; delete it first", typeName, command.Command)
+		}
+
+		_, err = tx.db().ExecContext(ctx, "INSERT INTO DeviceTypeCommandMapping (deviceTypeID, commandID, microserviceID, endpointID) VALUES (?, ?, ?, ?)",
+			typeID, commandID, microserviceID, endpointID)
+		return err
+	})
+	if err != nil {
+		return structs.DeviceTypeCommand{}, err
+	}
+
+	return command, nil
+}
+
+// DeleteDeviceTypeCommand removes a type's mapping for a command
+func (accessorGroup *AccessorGroup) DeleteDeviceTypeCommand(ctx context.Context, typeName string, commandName string) error {
+	return accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		typeID, err := tx.deviceTypeID(ctx, typeName)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.db().ExecContext(ctx, "DELETE FROM DeviceTypeCommandMapping WHERE deviceTypeID = ? AND commandID IN (SELECT commandID FROM Commands WHERE name = ?)", typeID, commandName)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return Errorf(NotFound, "%s has no mapping for %s", typeName, commandName)
+		}
+
+		return nil
+	})
+}
+
+func (accessorGroup *AccessorGroup) deviceTypeID(ctx context.Context, typeName string) (int, error) {
+	var id int
+	err := accessorGroup.db().QueryRowContext(ctx, "SELECT deviceTypeID FROM DeviceTypes WHERE typeName = ?", typeName).Scan(&id)
+	if KindOf(err) == NotFound {
+		return 0, Errorf(NotFound, "there is no device type called %s", typeName)
+	}
+
+	return id, err
+}
+
+// fullNames returns the building-room-name of each device matching condition
+func (accessorGroup *AccessorGroup) fullNames(ctx context.Context, condition string, args ...interface{}) ([]string, error) {
+	rows, err := accessorGroup.db().QueryContext(ctx, `SELECT Buildings.shortName, Rooms.name, Devices.name FROM Devices
+		JOIN Rooms ON Rooms.roomID = Devices.roomID
+		JOIN Buildings ON Buildings.buildingID = Rooms.buildingID
+		`+condition+`
+		ORDER BY Buildings.shortName, Rooms.name, Devices.name`, args...)
+	if err != nil {
+		return []string{}, err
+	}
+	defer rows.Close()
+
+	toReturn := []string{}
+	for rows.Next() {
+		device := structs.Device{}
+
+		err = rows.Scan(&device.Building.Shortname, &device.Room.Name, &device.Name)
+		if err != nil {
+			return []string{}, err
+		}
+
+		toReturn = append(toReturn, device.GetFullName())
+	}
+
+	return toReturn, rows.Err()
+}
+
+// idByName runs query for the ID of the thing called name, calling a miss a validation error
+func (accessorGroup *AccessorGroup) idByName(ctx context.Context, query string, what string, name string) (int, error) {
+	var id int
+	err := accessorGroup.db().QueryRowContext(ctx, query, name).Scan(&id)
+	if KindOf(err) == NotFound {
+		return 0, Errorf(Validation, "%s: %v does not exist", what, name)
+	}
+
+	return id, err
+}
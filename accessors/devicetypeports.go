@@ -0,0 +1,129 @@
+package accessors
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// AddDeviceTypePort defines a port on an entry in DeviceTypes. The port, dtp.Port.Name, has to be
+// in the Ports table, and a type can only define each port once.
+func (accessorGroup *AccessorGroup) AddDeviceTypePort(ctx context.Context, typeName string, dtp structs.DeviceTypePort) (added structs.DeviceTypePort, err error) {
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		typeID, err := tx.deviceTypeID(ctx, typeName)
+		if err != nil {
+			return err
+		}
+
+		port, err := tx.GetPortTypeByName(ctx, dtp.Port.Name)
+		if KindOf(err) == NotFound {
+			return Errorf(Validation, "port: %v does not exist", dtp.Port.Name)
+		} else if err != nil {
+			return err
+		}
+
+		dtp.DeviceTypeID = typeID
+		dtp.DeviceTypeName = typeName
+		dtp.Port = port
+
+		var count int
+		err = tx.db().QueryRowContext(ctx, "SELECT COUNT(*) FROM DeviceTypePorts WHERE deviceTypeID = ? AND portID = ?", dtp.DeviceTypeID, dtp.Port.ID).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return Errorf(Conflict, "%s already has a port called %s", typeName, dtp.Port.Name)
+		}
+
+		result, err := tx.db().ExecContext(ctx, "INSERT INTO DeviceTypePorts (deviceTypeID, portID, description, friendlyName, hostDestinationMirror) VALUES (?, ?, ?, ?, ?)",
+			dtp.DeviceTypeID, dtp.Port.ID, dtp.Description, dtp.FriendlyName, dtp.HostDestintionMirror)
+		if err != nil {
+			return err
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		dtp.DeviceTypePortID = int(id)
+
+		added = dtp
+		return nil
+	})
+
+	return added, err
+}
+
+// UpdateDeviceTypePort replaces the description, friendly name and host/destination mirroring of
+// one of a type's ports
+func (accessorGroup *AccessorGroup) UpdateDeviceTypePort(ctx context.Context, typeName string, portName string, dtp structs.DeviceTypePort) (updated structs.DeviceTypePort, err error) {
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		current, err := tx.deviceTypePort(ctx, typeName, portName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.db().ExecContext(ctx, "UPDATE DeviceTypePorts SET description = ?, friendlyName = ?, hostDestinationMirror = ? WHERE deviceTypePortID = ?",
+			dtp.Description, dtp.FriendlyName, dtp.HostDestintionMirror, current.DeviceTypePortID)
+		if err != nil {
+			return err
+		}
+
+		current.Description = dtp.Description
+		current.FriendlyName = dtp.FriendlyName
+		current.HostDestintionMirror = dtp.HostDestintionMirror
+
+		updated = current
+		return nil
+	})
+
+	return updated, err
+}
+
+// DeleteDeviceTypePort removes one of a type's ports. It refuses, listing the devices, while any
+// device of the type has that port configured.
+func (accessorGroup *AccessorGroup) DeleteDeviceTypePort(ctx context.Context, typeName string, portName string) error {
+	return accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		current, err := tx.deviceTypePort(ctx, typeName, portName)
+		if err != nil {
+			return err
+		}
+
+		devices, err := tx.fullNames(ctx, "WHERE Devices.typeID = ? AND Devices.deviceID IN (SELECT hostDeviceID FROM PortConfiguration WHERE portID = ?)", current.DeviceTypeID, current.Port.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(devices) > 0 {
+			return &Error{
+				Kind:    Conflict,
+				Err:     fmt.Errorf("%s devices still have %s configured; remove those port configurations first", typeName, portName),
+				Details: devices,
+			}
+		}
+
+		_, err = tx.db().ExecContext(ctx, "DELETE FROM DeviceTypePorts WHERE deviceTypePortID = ?", current.DeviceTypePortID)
+		return err
+	})
+}
+
+// deviceTypePort returns one of a type's ports, or a NotFound error if the type doesn't have it
+func (accessorGroup *AccessorGroup) deviceTypePort(ctx context.Context, typeName string, portName string) (structs.DeviceTypePort, error) {
+	if _, err := accessorGroup.deviceTypeID(ctx, typeName); err != nil {
+		return structs.DeviceTypePort{}, err
+	}
+
+	ports, err := accessorGroup.GetPortsByDeviceTypeName(ctx, typeName)
+	if err != nil {
+		return structs.DeviceTypePort{}, err
+	}
+
+	for _, port := range ports {
+		if port.Port.Name == portName {
+			return port, nil
+		}
+	}
+
+	return structs.DeviceTypePort{}, Errorf(NotFound, "%s has no port called %s", typeName, portName)
+}
@@ -1,6 +1,7 @@
 package accessors
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -10,10 +11,10 @@ import (
 )
 
 //GetDeviceClasses returns a dump of the table in the database
-func (accessorGroup *AccessorGroup) GetDeviceTypes() ([]structs.DeviceClass, error) {
+func (accessorGroup *AccessorGroup) GetDeviceTypes(ctx context.Context) ([]structs.DeviceClass, error) {
 
 	var toReturn []structs.DeviceClass
-	rows, err := accessorGroup.Database.Query("Select deviceTypeID, typeName, typeDescription, typeDisplayName From DeviceTypes")
+	rows, err := accessorGroup.db().QueryContext(ctx, "Select deviceTypeID, typeName, typeDescription, typeDisplayName From DeviceTypes")
 	if err != nil {
 		return toReturn, err
 	}
@@ -23,22 +24,30 @@ func (accessorGroup *AccessorGroup) GetDeviceTypes() ([]structs.DeviceClass, err
 	return toReturn, err
 }
 
-func (accessorGroup *AccessorGroup) SetDeviceTypeByID(id int, deviceID int) error {
+func (accessorGroup *AccessorGroup) SetDeviceTypeByID(ctx context.Context, id int, deviceID int) error {
 	log.Printf("Updating type id of device %v to %v", deviceID, id)
 
 	query := "UPDATE devices SET typeID = ? WHERE deviceID = ?"
 
-	res, err := accessorGroup.Database.Exec(query, id, deviceID)
-	if err != nil {
-		return err
-	}
-
-	if num, err := res.RowsAffected(); num != 1 || err != nil {
+	err := accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		res, err := tx.db().ExecContext(ctx, query, id, deviceID)
 		if err != nil {
 			return err
 		}
 
-		err = errors.New(fmt.Sprintf("There was a problem updating the device type: incorrect number of rows affected: %v. ", res.RowsAffected))
+		if num, err := res.RowsAffected(); num != 1 || err != nil {
+			if err != nil {
+				return err
+			}
+
+			err = errors.New(fmt.Sprintf("There was a problem updating the device type: incorrect number of rows affected: %v. ", num))
+			return err
+		}
+
+		// the new type's schema has to take the attributes the device already has
+		return tx.checkDeviceAttributes(ctx, deviceID)
+	})
+	if err != nil {
 		return err
 	}
 
@@ -85,8 +94,8 @@ func extractDeviceClassData(rows *sql.Rows) ([]structs.DeviceClass, error) {
 	return toReturn, nil
 }
 
-func (accessorGroup *AccessorGroup) GetDeviceClassByName(name string) (structs.DeviceClass, error) {
-	row, err := accessorGroup.Database.Query("Select deviceTypeID, typeName, typeDescription, typeDisplayName From DeviceTypes WHERE typeName = ?", name)
+func (accessorGroup *AccessorGroup) GetDeviceClassByName(ctx context.Context, name string) (structs.DeviceClass, error) {
+	row, err := accessorGroup.db().QueryContext(ctx, "Select deviceTypeID, typeName, typeDescription, typeDisplayName From DeviceTypes WHERE typeName = ?", name)
 	if err != nil {
 		return structs.DeviceClass{}, err
 	}
@@ -95,7 +104,7 @@ func (accessorGroup *AccessorGroup) GetDeviceClassByName(name string) (structs.D
 	dt, err := extractDeviceClassData(row)
 	if err != nil || len(dt) < 1 {
 		if len(dt) < 1 {
-			return structs.DeviceClass{}, errors.New("No device types found")
+			return structs.DeviceClass{}, Errorf(NotFound, "No device types found")
 		}
 		return structs.DeviceClass{}, err
 	}
@@ -1,15 +1,16 @@
 package accessors
 
 import (
+	"context"
 	"database/sql"
 	"log"
 
 	"github.com/byuoitav/configuration-database-microservice/structs"
 )
 
-func (accessorGroup *AccessorGroup) GetAllEndpoints() ([]structs.Endpoint, error) {
+func (accessorGroup *AccessorGroup) GetAllEndpoints(ctx context.Context) ([]structs.Endpoint, error) {
 
-	rows, err := accessorGroup.Database.Query("SELECT * FROM Endpoints")
+	rows, err := accessorGroup.db().QueryContext(ctx, "SELECT * FROM Endpoints")
 	if err != nil {
 		return []structs.Endpoint{}, err
 	}
@@ -23,9 +24,9 @@ func (accessorGroup *AccessorGroup) GetAllEndpoints() ([]structs.Endpoint, error
 	return endpoints, nil
 }
 
-func (accessorGroup *AccessorGroup) AddEndpoint(toAdd structs.Endpoint) (structs.Endpoint, error) {
+func (accessorGroup *AccessorGroup) AddEndpoint(ctx context.Context, toAdd structs.Endpoint) (structs.Endpoint, error) {
 
-	response, err := accessorGroup.Database.Exec("INSERT INTO Endpoints (name, path, description) VALUES(?,?,?)", toAdd.Name, toAdd.Path, toAdd.Description)
+	response, err := accessorGroup.db().ExecContext(ctx, "INSERT INTO Endpoints (name, path, description) VALUES(?,?,?)", toAdd.Name, toAdd.Path, toAdd.Description)
 	if err != nil {
 		return structs.Endpoint{}, err
 	}
@@ -36,9 +37,9 @@ func (accessorGroup *AccessorGroup) AddEndpoint(toAdd structs.Endpoint) (structs
 	return toAdd, nil
 }
 
-func (accessorGroup *AccessorGroup) RemoveEndpointByName(name string) error {
+func (accessorGroup *AccessorGroup) RemoveEndpointByName(ctx context.Context, name string) error {
 
-	_, err := accessorGroup.Database.Exec("DELETE FROM Endpoints WHERE name=?", name)
+	_, err := accessorGroup.db().ExecContext(ctx, "DELETE FROM Endpoints WHERE name=?", name)
 	if err != nil {
 		return err
 	}
@@ -46,8 +47,8 @@ func (accessorGroup *AccessorGroup) RemoveEndpointByName(name string) error {
 	return nil
 }
 
-func (accessorGroup *AccessorGroup) GetEndpointByName(name string) (structs.Endpoint, error) {
-	row := accessorGroup.Database.QueryRow("SELECT * FROM Endpoints WHERE name = ? ", name)
+func (accessorGroup *AccessorGroup) GetEndpointByName(ctx context.Context, name string) (structs.Endpoint, error) {
+	row := accessorGroup.db().QueryRowContext(ctx, "SELECT * FROM Endpoints WHERE name = ? ", name)
 
 	e, err := extractEndpoint(row)
 	if err != nil {
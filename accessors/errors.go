@@ -0,0 +1,101 @@
+package accessors
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Kind sorts accessor errors by what the caller can do about them
+type Kind int
+
+const (
+	// Internal is anything that isn't one of the kinds below
+	Internal Kind = iota
+
+	// NotFound means the thing asked for doesn't exist
+	NotFound
+
+	// Conflict means a write clashes with what's already stored, e.g. a duplicate name
+	Conflict
+
+	// Validation means the request itself is wrong: a bad value, or a reference to something that doesn't exist
+	Validation
+
+	// Unavailable means the database couldn't be reached
+	Unavailable
+)
+
+var kindNames = map[Kind]string{
+	Internal:    "internal",
+	NotFound:    "not_found",
+	Conflict:    "conflict",
+	Validation:  "validation",
+	Unavailable: "unavailable",
+}
+
+func (kind Kind) String() string {
+	return kindNames[kind]
+}
+
+// Error is an error with a Kind attached. Details lists specifics that don't fit in the
+// message, e.g. what's stopping a delete.
+type Error struct {
+	Kind    Kind
+	Err     error
+	Details []string
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Errorf formats an error of the given kind
+func Errorf(kind Kind, format string, args ...interface{}) error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+// KindOf returns the kind of an error from a Store. Errors the accessors didn't type themselves
+// are sorted by what the database driver said: no rows is NotFound, a duplicate key is Conflict,
+// a broken foreign key is Validation, and a lost connection or a query that ran out of time is
+// Unavailable.
+func KindOf(err error) Kind {
+	switch e := err.(type) {
+	case *Error:
+		return e.Kind
+
+	case *mysql.MySQLError:
+		switch e.Number {
+		case 1062: // ER_DUP_ENTRY
+			return Conflict
+		case 1451: // ER_ROW_IS_REFERENCED_2: something still points at the row
+			return Conflict
+		case 1452: // ER_NO_REFERENCED_ROW_2: the row points at something that isn't there
+			return Validation
+		case 1040, 1045, 1049: // too many connections, access denied, no such database
+			return Unavailable
+		}
+
+	case net.Error:
+		return Unavailable
+	}
+
+	if kind, ok := sqliteKind(err); ok {
+		return kind
+	}
+
+	switch err {
+	case sql.ErrNoRows:
+		return NotFound
+	case driver.ErrBadConn, mysql.ErrInvalidConn:
+		return Unavailable
+	case context.DeadlineExceeded, context.Canceled:
+		return Unavailable
+	}
+
+	return Internal
+}
@@ -0,0 +1,10 @@
+//go:build !cgo
+// +build !cgo
+
+package accessors
+
+// sqliteKind never matches without cgo: the SQLite driver isn't built, so none of its errors
+// can turn up
+func sqliteKind(err error) (Kind, bool) {
+	return Internal, false
+}
@@ -0,0 +1,29 @@
+//go:build cgo
+// +build cgo
+
+package accessors
+
+import "github.com/mattn/go-sqlite3"
+
+// sqliteKind sorts the errors the SQLite driver returns. The driver needs cgo, so builds without
+// it get the stub in errors_nocgo.go instead.
+func sqliteKind(err error) (Kind, bool) {
+	e, ok := err.(sqlite3.Error)
+	if !ok {
+		return Internal, false
+	}
+
+	switch e.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		return Conflict, true
+	case sqlite3.ErrConstraintForeignKey, sqlite3.ErrConstraintNotNull:
+		return Validation, true
+	}
+
+	switch e.Code {
+	case sqlite3.ErrBusy, sqlite3.ErrLocked, sqlite3.ErrCantOpen:
+		return Unavailable, true
+	}
+
+	return Internal, false
+}
@@ -0,0 +1,87 @@
+package accessors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// Expand says which of a device's relations to load. The ones left out aren't queried, and
+// come back nil so they drop out of the JSON.
+type Expand struct {
+	Commands    bool
+	Ports       bool
+	PowerStates bool
+	Roles       bool
+	Attributes  bool
+}
+
+// ExpandAll loads every relation; it's what a Store does until it's told otherwise
+var ExpandAll = Expand{Commands: true, Ports: true, PowerStates: true, Roles: true, Attributes: true}
+
+// ParseExpand reads a comma separated list of relations, e.g. "commands,ports". An empty
+// list expands nothing.
+func ParseExpand(list string) (Expand, error) {
+	expand := Expand{}
+
+	for _, name := range strings.Split(list, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "":
+		case "commands":
+			expand.Commands = true
+		case "ports":
+			expand.Ports = true
+		case "powerstates":
+			expand.PowerStates = true
+		case "roles":
+			expand.Roles = true
+		case "attributes":
+			expand.Attributes = true
+		default:
+			return Expand{}, fmt.Errorf("can't expand %q; valid values are commands, ports, powerstates, roles and attributes", strings.TrimSpace(name))
+		}
+	}
+
+	return expand, nil
+}
+
+// Trim clears the relations e doesn't ask for. It's for backends that get every relation for
+// free and can't skip loading them.
+func (e Expand) Trim(devices []structs.Device) {
+	for i := range devices {
+		if !e.Commands {
+			devices[i].Commands = nil
+		}
+		if !e.Ports {
+			devices[i].Ports = nil
+		}
+		if !e.PowerStates {
+			devices[i].PowerStates = nil
+		}
+		if !e.Roles {
+			devices[i].Roles = nil
+		}
+		if !e.Attributes {
+			devices[i].Attributes = nil
+		}
+	}
+}
+
+// WithExpansion returns a copy of the group that only loads the device relations in expand.
+// The copy shares the group's database (and transaction, if it's in one).
+func (accessorGroup *AccessorGroup) WithExpansion(expand Expand) Store {
+	expanded := *accessorGroup
+	expanded.expand = &expand
+
+	return &expanded
+}
+
+// expansion returns the relations the group loads; a group nobody has narrowed loads them all
+func (accessorGroup *AccessorGroup) expansion() Expand {
+	if accessorGroup.expand == nil {
+		return ExpandAll
+	}
+
+	return *accessorGroup.expand
+}
@@ -3,6 +3,7 @@ package accessors
 import (
 	_ "github.com/go-sql-driver/mysql" // Blank import due to its use as a driver
 
+	"context"
 	"database/sql"
 	"log"
 )
@@ -10,6 +11,17 @@ import (
 // AccessorGroup holds all configuration for the accessors.
 type AccessorGroup struct {
 	Database *sql.DB
+	Driver   string // the database/sql driver Database was opened with
+
+	tx     *sql.Tx // set on the copy handed to a Transaction callback
+	expand *Expand // set by WithExpansion; nil loads every device relation
+}
+
+// executor is what *sql.DB and *sql.Tx have in common
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
 // Open creates a database connection and sets it in the struct
@@ -20,4 +32,85 @@ func (accessorGroup *AccessorGroup) Open(dataSourceName string) {
 	}
 
 	accessorGroup.Database = database
+	accessorGroup.Driver = "mysql"
+}
+
+// db returns the transaction the group is in, or the database if it isn't in one
+func (accessorGroup *AccessorGroup) db() executor {
+	if accessorGroup.tx != nil {
+		return accessorGroup.tx
+	}
+
+	return accessorGroup.Database
+}
+
+// Transaction runs fn with a Store whose queries all go through one transaction, then commits
+// it if fn returns nil and rolls it back otherwise. Use the Store fn is given, not the group
+// Transaction was called on. Calling Transaction inside fn joins the transaction already open.
+func (accessorGroup *AccessorGroup) Transaction(ctx context.Context, fn func(Store) error) error {
+	return accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		return fn(tx)
+	})
+}
+
+func (accessorGroup *AccessorGroup) transaction(ctx context.Context, fn func(*AccessorGroup) error) (err error) {
+	if accessorGroup.tx != nil {
+		return fn(accessorGroup)
+	}
+
+	tx, err := accessorGroup.Database.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// roll back if fn panics, and let the panic carry on
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	txGroup := *accessorGroup
+	txGroup.tx = tx
+
+	err = fn(&txGroup)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// cleanID turns an unset ID into NULL so the database assigns one. MySQL treats an explicit
+// 0 the same way, but SQLite would store it as the row's ID.
+func cleanID(id int) interface{} {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+// names runs a query returning one string column and collects the results
+func (accessorGroup *AccessorGroup) names(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	rows, err := accessorGroup.db().QueryContext(ctx, query, args...)
+	if err != nil {
+		return []string{}, err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+
+		err = rows.Scan(&name)
+		if err != nil {
+			return []string{}, err
+		}
+
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
 }
@@ -0,0 +1,202 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// GetAllBuildings returns a list of buildings
+func (s *Store) GetAllBuildings(ctx context.Context) ([]structs.Building, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	allBuildings := []structs.Building{}
+	allBuildings = append(allBuildings, s.tables.Buildings...)
+
+	return allBuildings, nil
+}
+
+// GetBuildingByID returns a building by ID
+func (s *Store) GetBuildingByID(ctx context.Context, id int) (structs.Building, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	building, ok := s.tables.building(id)
+	if !ok {
+		return structs.Building{}, sql.ErrNoRows
+	}
+
+	return building, nil
+}
+
+// GetBuildingByShortname returns a building by shortname
+func (s *Store) GetBuildingByShortname(ctx context.Context, shortname string) (structs.Building, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	building, ok := s.tables.buildingByShortname(shortname)
+	if !ok {
+		return structs.Building{}, sql.ErrNoRows
+	}
+
+	return building, nil
+}
+
+// AddBuilding adds a building
+func (s *Store) AddBuilding(ctx context.Context, name string, shortname string, description string) (structs.Building, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	building := structs.Building{
+		ID:          s.nextID("Buildings", 0),
+		Name:        name,
+		Shortname:   shortname,
+		Description: description,
+	}
+	s.tables.Buildings = append(s.tables.Buildings, building)
+
+	return building, nil
+}
+
+// UpdateBuilding replaces the name, shortname and description of a building. An empty
+// shortname keeps the current one.
+func (s *Store) UpdateBuilding(ctx context.Context, shortname string, building structs.Building) (structs.Building, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(building.Name) == 0 {
+		return structs.Building{}, accessors.Errorf(accessors.Validation, "a building needs a name")
+	}
+
+	current, ok := s.tables.buildingByShortname(shortname)
+	if !ok {
+		return structs.Building{}, sql.ErrNoRows
+	}
+
+	if len(building.Shortname) == 0 {
+		building.Shortname = current.Shortname
+	}
+
+	if building.Shortname != current.Shortname {
+		if _, ok := s.tables.buildingByShortname(building.Shortname); ok {
+			return structs.Building{}, accessors.Errorf(accessors.Conflict, "there is already a building with the shortname %s", building.Shortname)
+		}
+	}
+
+	for i := range s.tables.Buildings {
+		if s.tables.Buildings[i].ID == current.ID {
+			s.tables.Buildings[i].Name = building.Name
+			s.tables.Buildings[i].Shortname = building.Shortname
+			s.tables.Buildings[i].Description = building.Description
+			return s.tables.Buildings[i], nil
+		}
+	}
+
+	return structs.Building{}, sql.ErrNoRows
+}
+
+// DeleteBuilding deletes a building, refusing while rooms or devices are in it unless cascade is set
+func (s *Store) DeleteBuilding(ctx context.Context, shortname string, cascade bool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	building, ok := s.tables.buildingByShortname(shortname)
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	if !cascade {
+		blockers := s.tables.buildingBlockers(building)
+		if len(blockers) > 0 {
+			return &accessors.Error{
+				Kind:    accessors.Conflict,
+				Err:     fmt.Errorf("building %s still has rooms or devices; delete them first or pass cascade=true", shortname),
+				Details: blockers,
+			}
+		}
+	}
+
+	s.tables.deleteDevices(func(d Device) bool {
+		room, ok := s.tables.roomRow(d.RoomID)
+		return ok && room.BuildingID == building.ID
+	})
+
+	s.tables.deletePromotions(func(roomID int) bool {
+		room, ok := s.tables.roomRow(roomID)
+		return ok && room.BuildingID == building.ID
+	})
+
+	rooms := s.tables.Rooms[:0]
+	for _, row := range s.tables.Rooms {
+		if row.BuildingID != building.ID {
+			rooms = append(rooms, row)
+		}
+	}
+	s.tables.Rooms = rooms
+
+	buildings := s.tables.Buildings[:0]
+	for _, row := range s.tables.Buildings {
+		if row.ID != building.ID {
+			buildings = append(buildings, row)
+		}
+	}
+	s.tables.Buildings = buildings
+
+	return nil
+}
+
+func (t *Tables) buildingBlockers(building structs.Building) []string {
+	rooms := []string{}
+	devices := []string{}
+
+	for _, row := range t.Rooms {
+		if row.BuildingID == building.ID {
+			rooms = append(rooms, row.Name)
+		}
+	}
+
+	for _, row := range t.Devices {
+		room, ok := t.roomRow(row.RoomID)
+		if ok && room.BuildingID == building.ID {
+			devices = append(devices, room.Name+"-"+row.Name)
+		}
+	}
+
+	sort.Strings(rooms)
+	sort.Strings(devices)
+
+	blockers := []string{}
+	for _, room := range rooms {
+		blockers = append(blockers, fmt.Sprintf("room %s-%s", building.Shortname, room))
+	}
+	for _, device := range devices {
+		blockers = append(blockers, fmt.Sprintf("device %s-%s", building.Shortname, device))
+	}
+
+	return blockers
+}
+
+func (t *Tables) building(id int) (structs.Building, bool) {
+	for _, building := range t.Buildings {
+		if building.ID == id {
+			return building, true
+		}
+	}
+
+	return structs.Building{}, false
+}
+
+func (t *Tables) buildingByShortname(shortname string) (structs.Building, bool) {
+	for _, building := range t.Buildings {
+		if building.Shortname == shortname {
+			return building, true
+		}
+	}
+
+	return structs.Building{}, false
+}
@@ -0,0 +1,68 @@
+package memory
+
+// deleteDevices removes the devices that match, along with every row that points at them
+func (t *Tables) deleteDevices(match func(Device) bool) {
+	deleted := make(map[int]bool)
+
+	devices := t.Devices[:0]
+	for _, row := range t.Devices {
+		if match(row) {
+			deleted[row.ID] = true
+			continue
+		}
+		devices = append(devices, row)
+	}
+	t.Devices = devices
+
+	if len(deleted) == 0 {
+		return
+	}
+
+	roles := t.DeviceRoles[:0]
+	for _, row := range t.DeviceRoles {
+		if !deleted[row.DeviceID] {
+			roles = append(roles, row)
+		}
+	}
+	t.DeviceRoles = roles
+
+	powerStates := t.DevicePowerStates[:0]
+	for _, row := range t.DevicePowerStates {
+		if !deleted[row.DeviceID] {
+			powerStates = append(powerStates, row)
+		}
+	}
+	t.DevicePowerStates = powerStates
+
+	commands := t.DeviceCommands[:0]
+	for _, row := range t.DeviceCommands {
+		if !deleted[row.DeviceID] {
+			commands = append(commands, row)
+		}
+	}
+	t.DeviceCommands = commands
+
+	audioDevices := t.AudioDevices[:0]
+	for _, row := range t.AudioDevices {
+		if !deleted[row.DeviceID] {
+			audioDevices = append(audioDevices, row)
+		}
+	}
+	t.AudioDevices = audioDevices
+
+	attributes := t.DeviceAttributes[:0]
+	for _, row := range t.DeviceAttributes {
+		if !deleted[row.DeviceID] {
+			attributes = append(attributes, row)
+		}
+	}
+	t.DeviceAttributes = attributes
+
+	portConfigurations := t.PortConfigurations[:0]
+	for _, row := range t.PortConfigurations {
+		if !deleted[row.SourceDeviceID] && !deleted[row.DestinationDeviceID] && !deleted[row.HostDeviceID] {
+			portConfigurations = append(portConfigurations, row)
+		}
+	}
+	t.PortConfigurations = portConfigurations
+}
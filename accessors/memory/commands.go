@@ -0,0 +1,173 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// GetAllCommands dumps the Commands table
+func (s *Store) GetAllCommands(ctx context.Context) ([]structs.RawCommand, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	commands := []structs.RawCommand{}
+	commands = append(commands, s.tables.Commands...)
+
+	return commands, nil
+}
+
+// GetRawCommandByName returns an entry in Commands by name
+func (s *Store) GetRawCommandByName(ctx context.Context, name string) (structs.RawCommand, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, rc := range s.tables.Commands {
+		if rc.Name == name {
+			return rc, nil
+		}
+	}
+
+	return structs.RawCommand{}, sql.ErrNoRows
+}
+
+// AddRawCommand adds an entry to the Commands table
+func (s *Store) AddRawCommand(ctx context.Context, rc structs.RawCommand) (structs.RawCommand, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rc.ID = s.nextID("Commands", rc.ID)
+	s.tables.Commands = append(s.tables.Commands, rc)
+
+	return rc, nil
+}
+
+// AddDeviceCommand adds an entry to the DeviceCommands table
+func (s *Store) AddDeviceCommand(ctx context.Context, dc structs.DeviceCommand) (structs.DeviceCommand, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	dc.ID = s.nextID("DeviceCommands", dc.ID)
+	s.tables.DeviceCommands = append(s.tables.DeviceCommands, dc)
+
+	return dc, nil
+}
+
+// GetAllEndpoints dumps the Endpoints table
+func (s *Store) GetAllEndpoints(ctx context.Context) ([]structs.Endpoint, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	endpoints := []structs.Endpoint{}
+	endpoints = append(endpoints, s.tables.Endpoints...)
+
+	return endpoints, nil
+}
+
+// GetEndpointByName returns an endpoint by name
+func (s *Store) GetEndpointByName(ctx context.Context, name string) (structs.Endpoint, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, e := range s.tables.Endpoints {
+		if e.Name == name {
+			return e, nil
+		}
+	}
+
+	return structs.Endpoint{}, sql.ErrNoRows
+}
+
+// AddEndpoint adds an endpoint
+func (s *Store) AddEndpoint(ctx context.Context, toAdd structs.Endpoint) (structs.Endpoint, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	toAdd.ID = s.nextID("Endpoints", 0)
+	s.tables.Endpoints = append(s.tables.Endpoints, toAdd)
+
+	return toAdd, nil
+}
+
+// RemoveEndpointByName removes every endpoint with the given name
+func (s *Store) RemoveEndpointByName(ctx context.Context, name string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	endpoints := s.tables.Endpoints[:0]
+	for _, e := range s.tables.Endpoints {
+		if e.Name != name {
+			endpoints = append(endpoints, e)
+		}
+	}
+	s.tables.Endpoints = endpoints
+
+	return nil
+}
+
+// GetMicroservices dumps the Microservices table
+func (s *Store) GetMicroservices(ctx context.Context) ([]structs.Microservice, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	microservices := []structs.Microservice{}
+	microservices = append(microservices, s.tables.Microservices...)
+
+	return microservices, nil
+}
+
+// GetMicroserviceByAddress returns a microservice by address
+func (s *Store) GetMicroserviceByAddress(ctx context.Context, address string) (structs.Microservice, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, m := range s.tables.Microservices {
+		if m.Address == address {
+			return m, nil
+		}
+	}
+
+	return structs.Microservice{}, sql.ErrNoRows
+}
+
+// AddMicroservice adds a microservice
+func (s *Store) AddMicroservice(ctx context.Context, microservice structs.Microservice) (structs.Microservice, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	microservice.ID = s.nextID("Microservices", microservice.ID)
+	s.tables.Microservices = append(s.tables.Microservices, microservice)
+
+	return microservice, nil
+}
+
+func (t *Tables) command(id int) (structs.RawCommand, bool) {
+	for _, rc := range t.Commands {
+		if rc.ID == id {
+			return rc, true
+		}
+	}
+
+	return structs.RawCommand{}, false
+}
+
+func (t *Tables) endpoint(id int) (structs.Endpoint, bool) {
+	for _, e := range t.Endpoints {
+		if e.ID == id {
+			return e, true
+		}
+	}
+
+	return structs.Endpoint{}, false
+}
+
+func (t *Tables) microservice(id int) (structs.Microservice, bool) {
+	for _, m := range t.Microservices {
+		if m.ID == id {
+			return m, true
+		}
+	}
+
+	return structs.Microservice{}, false
+}
@@ -0,0 +1,312 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// GetConfigurations dumps the RoomConfiguration table, without evaluators
+func (s *Store) GetConfigurations(ctx context.Context) ([]structs.RoomConfiguration, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	rcs := []structs.RoomConfiguration{}
+	for _, rc := range s.tables.Configurations {
+		rc.Evaluators = nil
+		rcs = append(rcs, rc)
+	}
+
+	return rcs, nil
+}
+
+// GetConfigurationByConfigurationID returns a configuration, with its evaluators, by ID
+func (s *Store) GetConfigurationByConfigurationID(ctx context.Context, configurationID int) (structs.RoomConfiguration, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	config, ok := s.tables.configuration(configurationID)
+	if !ok {
+		return structs.RoomConfiguration{}, sql.ErrNoRows
+	}
+
+	return config, nil
+}
+
+// GetConfigurationByConfigurationName returns a configuration, with its evaluators, by name
+func (s *Store) GetConfigurationByConfigurationName(ctx context.Context, name string) (structs.RoomConfiguration, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, rc := range s.tables.Configurations {
+		if rc.Name == name {
+			config, _ := s.tables.configuration(rc.ID)
+			return config, nil
+		}
+	}
+
+	return structs.RoomConfiguration{}, sql.ErrNoRows
+}
+
+// GetConfigurationByRoomAndBuilding returns the configuration a room uses
+func (s *Store) GetConfigurationByRoomAndBuilding(ctx context.Context, building string, room string) (structs.RoomConfiguration, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	b, ok := s.tables.buildingByShortname(building)
+	if !ok {
+		return structs.RoomConfiguration{}, sql.ErrNoRows
+	}
+
+	rm, ok := s.tables.roomByBuildingAndName(b.ID, room)
+	if !ok {
+		return structs.RoomConfiguration{}, accessors.Errorf(accessors.NotFound, "No rooms found with that name.")
+	}
+
+	config, ok := s.tables.configuration(rm.ConfigurationID)
+	if !ok {
+		return structs.RoomConfiguration{}, sql.ErrNoRows
+	}
+
+	return config, nil
+}
+
+// GetEvaluatorsForConfigurationByID returns the evaluators mapped to a configuration
+func (s *Store) GetEvaluatorsForConfigurationByID(ctx context.Context, configurationID int) ([]structs.ConfigurationEvaluator, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.tables.evaluators(configurationID), nil
+}
+
+func (t *Tables) configuration(id int) (structs.RoomConfiguration, bool) {
+	for _, rc := range t.Configurations {
+		if rc.ID == id {
+			rc.Evaluators = t.evaluators(id)
+			return rc, true
+		}
+	}
+
+	return structs.RoomConfiguration{}, false
+}
+
+func (t *Tables) evaluators(configurationID int) []structs.ConfigurationEvaluator {
+	var allEvaluators []structs.ConfigurationEvaluator
+
+	for _, mapping := range t.ConfigurationMappings {
+		if mapping.ConfigurationID == configurationID {
+			allEvaluators = append(allEvaluators, structs.ConfigurationEvaluator{
+				EvaluatorKey: mapping.EvaluatorKey,
+				Priority:     mapping.Priority,
+			})
+		}
+	}
+
+	sort.SliceStable(allEvaluators, func(i, j int) bool {
+		if allEvaluators[i].Priority != allEvaluators[j].Priority {
+			return allEvaluators[i].Priority < allEvaluators[j].Priority
+		}
+		return allEvaluators[i].EvaluatorKey < allEvaluators[j].EvaluatorKey
+	})
+
+	return allEvaluators
+}
+
+// AddConfiguration adds a room configuration, along with any evaluators it comes with
+func (s *Store) AddConfiguration(ctx context.Context, config structs.RoomConfiguration) (structs.RoomConfiguration, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(config.Name) == 0 {
+		return structs.RoomConfiguration{}, accessors.Errorf(accessors.Validation, "a room configuration needs a name")
+	}
+
+	if _, ok := s.tables.configurationIndex(config.Name); ok {
+		return structs.RoomConfiguration{}, accessors.Errorf(accessors.Conflict, "there is already a room configuration called %s", config.Name)
+	}
+
+	err := checkEvaluators(config.Name, config.Evaluators)
+	if err != nil {
+		return structs.RoomConfiguration{}, err
+	}
+
+	evaluators := config.Evaluators
+	config.ID = s.nextID("RoomConfiguration", 0)
+	config.Evaluators = nil
+	s.tables.Configurations = append(s.tables.Configurations, config)
+
+	for _, evaluator := range evaluators {
+		s.addConfigurationMapping(config.ID, evaluator)
+	}
+
+	added, _ := s.tables.configuration(config.ID)
+	return added, nil
+}
+
+// UpdateConfiguration changes a room configuration's keys and description, or renames it. An
+// empty name, room key or room init key keeps the current value; a non-nil list of evaluators
+// replaces the current ones.
+func (s *Store) UpdateConfiguration(ctx context.Context, name string, config structs.RoomConfiguration) (structs.RoomConfiguration, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	i, ok := s.tables.configurationIndex(name)
+	if !ok {
+		return structs.RoomConfiguration{}, accessors.Errorf(accessors.NotFound, "there is no room configuration called %s", name)
+	}
+	current := s.tables.Configurations[i]
+
+	if len(config.Name) == 0 {
+		config.Name = current.Name
+	}
+	if len(config.RoomKey) == 0 {
+		config.RoomKey = current.RoomKey
+	}
+	if len(config.RoomInitKey) == 0 {
+		config.RoomInitKey = current.RoomInitKey
+	}
+
+	if config.Name != current.Name {
+		if _, ok := s.tables.configurationIndex(config.Name); ok {
+			return structs.RoomConfiguration{}, accessors.Errorf(accessors.Conflict, "there is already a room configuration called %s", config.Name)
+		}
+	}
+
+	if config.Evaluators != nil {
+		err := checkEvaluators(config.Name, config.Evaluators)
+		if err != nil {
+			return structs.RoomConfiguration{}, err
+		}
+
+		mappings := s.tables.ConfigurationMappings[:0]
+		for _, mapping := range s.tables.ConfigurationMappings {
+			if mapping.ConfigurationID != current.ID {
+				mappings = append(mappings, mapping)
+			}
+		}
+		s.tables.ConfigurationMappings = mappings
+
+		for _, evaluator := range config.Evaluators {
+			s.addConfigurationMapping(current.ID, evaluator)
+		}
+	}
+
+	config.ID = current.ID
+	config.Evaluators = nil
+	s.tables.Configurations[i] = config
+
+	updated, _ := s.tables.configuration(config.ID)
+	return updated, nil
+}
+
+// AddConfigurationEvaluator maps an evaluator to a room configuration at a priority
+func (s *Store) AddConfigurationEvaluator(ctx context.Context, configurationName string, evaluator structs.ConfigurationEvaluator) (structs.ConfigurationEvaluator, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	i, ok := s.tables.configurationIndex(configurationName)
+	if !ok {
+		return structs.ConfigurationEvaluator{}, accessors.Errorf(accessors.NotFound, "there is no room configuration called %s", configurationName)
+	}
+	id := s.tables.Configurations[i].ID
+
+	err := checkEvaluators(configurationName, append(s.tables.evaluators(id), evaluator))
+	if err != nil {
+		return structs.ConfigurationEvaluator{}, err
+	}
+
+	s.addConfigurationMapping(id, evaluator)
+
+	return evaluator, nil
+}
+
+// UpdateConfigurationEvaluator changes the priority of an evaluator in a room configuration
+func (s *Store) UpdateConfigurationEvaluator(ctx context.Context, configurationName string, evaluatorKey string, evaluator structs.ConfigurationEvaluator) (structs.ConfigurationEvaluator, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	i, ok := s.tables.configurationIndex(configurationName)
+	if !ok {
+		return structs.ConfigurationEvaluator{}, accessors.Errorf(accessors.NotFound, "there is no room configuration called %s", configurationName)
+	}
+	id := s.tables.Configurations[i].ID
+
+	for j, mapping := range s.tables.ConfigurationMappings {
+		if mapping.ConfigurationID == id && mapping.EvaluatorKey == evaluatorKey {
+			s.tables.ConfigurationMappings[j].Priority = evaluator.Priority
+
+			evaluator.EvaluatorKey = evaluatorKey
+			return evaluator, nil
+		}
+	}
+
+	return structs.ConfigurationEvaluator{}, accessors.Errorf(accessors.NotFound, "%s has no evaluator %s", configurationName, evaluatorKey)
+}
+
+// DeleteConfigurationEvaluator removes an evaluator from a room configuration
+func (s *Store) DeleteConfigurationEvaluator(ctx context.Context, configurationName string, evaluatorKey string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	i, ok := s.tables.configurationIndex(configurationName)
+	if !ok {
+		return accessors.Errorf(accessors.NotFound, "there is no room configuration called %s", configurationName)
+	}
+	id := s.tables.Configurations[i].ID
+
+	deleted := false
+	mappings := s.tables.ConfigurationMappings[:0]
+	for _, mapping := range s.tables.ConfigurationMappings {
+		if mapping.ConfigurationID == id && mapping.EvaluatorKey == evaluatorKey {
+			deleted = true
+			continue
+		}
+		mappings = append(mappings, mapping)
+	}
+	s.tables.ConfigurationMappings = mappings
+
+	if !deleted {
+		return accessors.Errorf(accessors.NotFound, "%s has no evaluator %s", configurationName, evaluatorKey)
+	}
+
+	return nil
+}
+
+func (s *Store) addConfigurationMapping(configurationID int, evaluator structs.ConfigurationEvaluator) {
+	s.tables.ConfigurationMappings = append(s.tables.ConfigurationMappings, ConfigurationMapping{
+		ID:              s.nextID("RoomConfigurationMapping", 0),
+		ConfigurationID: configurationID,
+		EvaluatorKey:    evaluator.EvaluatorKey,
+		Priority:        evaluator.Priority,
+	})
+}
+
+func (t *Tables) configurationIndex(name string) (int, bool) {
+	for i, rc := range t.Configurations {
+		if rc.Name == name {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// checkEvaluators makes sure every evaluator has a key, and no key is used twice. The SQL
+// backend finds the same problems one row at a time, inside its transaction.
+func checkEvaluators(configurationName string, evaluators []structs.ConfigurationEvaluator) error {
+	seen := make(map[string]bool)
+	for _, evaluator := range evaluators {
+		if len(evaluator.EvaluatorKey) == 0 {
+			return accessors.Errorf(accessors.Validation, "an evaluator needs an evaluatorKey")
+		}
+		if seen[evaluator.EvaluatorKey] {
+			return accessors.Errorf(accessors.Conflict, "%s already has evaluator %s", configurationName, evaluator.EvaluatorKey)
+		}
+		seen[evaluator.EvaluatorKey] = true
+	}
+
+	return nil
+}
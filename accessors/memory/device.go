@@ -0,0 +1,826 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// GetDeviceById returns the device with the given ID
+func (s *Store) GetDeviceById(ctx context.Context, deviceID int) (structs.Device, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	devices := s.devicesWhere(func(d Device) bool {
+		return d.ID == deviceID
+	})
+	if len(devices) < 1 {
+		return structs.Device{}, accessors.Errorf(accessors.NotFound, "No devices found for ID %d", deviceID)
+	}
+
+	return devices[0], nil
+}
+
+// GetDevicesByRoomIdAndRoleId returns the devices in a room with the given role definition
+func (s *Store) GetDevicesByRoomIdAndRoleId(ctx context.Context, roomId, roleId int) ([]structs.Device, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.devicesWhere(func(d Device) bool {
+		return d.RoomID == roomId && s.tables.hasRoleID(d.ID, roleId)
+	}), nil
+}
+
+// GetDevicesByRoomId returns the devices in a room
+func (s *Store) GetDevicesByRoomId(ctx context.Context, roomId int) ([]structs.Device, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.devicesWhere(func(d Device) bool {
+		return d.RoomID == roomId
+	}), nil
+}
+
+// GetDevicesByBuildingAndRoom returns all the devices in the room specified
+func (s *Store) GetDevicesByBuildingAndRoom(ctx context.Context, buildingShortname string, roomName string) ([]structs.Device, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.devicesWhere(func(d Device) bool {
+		return s.tables.inRoom(d, buildingShortname, roomName, false)
+	}), nil
+}
+
+// GetAllDevices returns every device in every room
+func (s *Store) GetAllDevices(ctx context.Context) ([]structs.Device, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.devicesWhere(func(d Device) bool {
+		return true
+	}), nil
+}
+
+// GetDevicesByBuildingAndRoomAndRole returns the devices in the room specified with the given role
+func (s *Store) GetDevicesByBuildingAndRoomAndRole(ctx context.Context, buildingShortname string, roomName string, roleName string) ([]structs.Device, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.devicesWhere(func(d Device) bool {
+		return s.tables.inRoom(d, buildingShortname, roomName, true) && s.tables.hasRole(d.ID, roleName)
+	}), nil
+}
+
+// GetDevicesByRoleAndType returns the devices with the given role and class in rooms with the given
+// designation, which is compared without regard to case as MySQL does
+func (s *Store) GetDevicesByRoleAndType(ctx context.Context, deviceRole string, deviceType string, production string) ([]structs.Device, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.devicesWhere(func(d Device) bool {
+		room, _ := s.tables.roomRow(d.RoomID)
+		class, _ := s.tables.deviceClass(d.ClassID)
+
+		return strings.EqualFold(room.RoomDesignation, production) && like(class.Name, deviceType) && s.tables.hasRole(d.ID, deviceRole)
+	}), nil
+}
+
+// GetDeviceByBuildingAndRoomAndName returns the device specified
+func (s *Store) GetDeviceByBuildingAndRoomAndName(ctx context.Context, buildingShortname string, roomName string, deviceName string) (structs.Device, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	devices := s.devicesWhere(func(d Device) bool {
+		return d.Name == deviceName && s.tables.inRoom(d, buildingShortname, roomName, false)
+	})
+	if len(devices) == 0 {
+		return structs.Device{}, accessors.Errorf(accessors.NotFound, "No devices found with that name.")
+	}
+
+	return devices[0], nil
+}
+
+// GetDeviceCommandsByBuildingAndRoomAndName returns the commands for the device specified
+func (s *Store) GetDeviceCommandsByBuildingAndRoomAndName(ctx context.Context, buildingShortname string, roomName string, deviceName string) ([]structs.Command, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	allCommands := []structs.Command{}
+	for _, d := range s.tables.Devices {
+		if d.Name == deviceName && s.tables.inRoom(d, buildingShortname, roomName, false) {
+			allCommands = append(allCommands, s.tables.commands(d.TypeID)...)
+		}
+	}
+
+	return allCommands, nil
+}
+
+// GetDevicePortsByBuildingAndRoomAndName returns the ports hosted by the device specified
+func (s *Store) GetDevicePortsByBuildingAndRoomAndName(ctx context.Context, buildingShortname string, roomName string, deviceName string) ([]structs.Port, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	allPorts := []structs.Port{}
+	found := false
+	for _, d := range s.tables.Devices {
+		if d.Name == deviceName && s.tables.inRoom(d, buildingShortname, roomName, false) {
+			allPorts = append(allPorts, s.tables.ports(d.ID)...)
+			found = true
+		}
+	}
+
+	if !found {
+		return allPorts, accessors.Errorf(accessors.NotFound, "No devices found with that name.")
+	}
+
+	return allPorts, nil
+}
+
+// GetRolesByDeviceID returns the names of the roles a device has
+func (s *Store) GetRolesByDeviceID(ctx context.Context, deviceID int) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.tables.roles(deviceID), nil
+}
+
+// GetPowerStatesByDeviceID returns the names of the power states a device allows
+func (s *Store) GetPowerStatesByDeviceID(ctx context.Context, deviceID int) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.tables.powerStates(deviceID), nil
+}
+
+// AddDevice adds a device along with its roles and power states. Everything is checked
+// before anything is written, so a bad role or power state doesn't leave a partial device.
+func (s *Store) AddDevice(ctx context.Context, d structs.Device) (structs.Device, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	class, ok := s.tables.deviceClassByName(d.Type)
+	if !ok {
+		return structs.Device{}, accessors.Errorf(accessors.Validation, "device type: %v does not exist", d.Type)
+	}
+
+	deviceType, ok := s.tables.deviceTypeByName(d.Class)
+	if !ok {
+		return structs.Device{}, accessors.Errorf(accessors.Validation, "device class: %v does not exist", d.Class)
+	}
+
+	for _, existing := range s.tables.Devices {
+		if existing.Name == d.Name && s.tables.inRoom(existing, d.Building.Shortname, d.Room.Name, false) {
+			return structs.Device{}, accessors.Errorf(accessors.Conflict, "device already exists in room, please choose a different name")
+		}
+	}
+
+	var roleIDs []int
+	for _, role := range d.Roles {
+		r, ok := s.tables.roleDefinitionByName(role)
+		if !ok {
+			return structs.Device{}, accessors.Errorf(accessors.Validation, "device role definition: %v does not exist", role)
+		}
+		roleIDs = append(roleIDs, r.ID)
+	}
+
+	var powerStateIDs []int
+	for _, ps := range d.PowerStates {
+		p, ok := s.tables.powerStateByName(ps)
+		if !ok {
+			return structs.Device{}, accessors.Errorf(accessors.Validation, "powerstate: %v does not exist", ps)
+		}
+		powerStateIDs = append(powerStateIDs, p.ID)
+	}
+
+	row := Device{
+		ID:          s.nextID("Devices", 0),
+		Name:        d.Name,
+		Address:     d.Address,
+		Input:       d.Input,
+		Output:      d.Output,
+		BuildingID:  d.Building.ID,
+		RoomID:      d.Room.ID,
+		ClassID:     class.ID,
+		TypeID:      deviceType.ID,
+		DisplayName: deviceType.DisplayName,
+	}
+	s.tables.Devices = append(s.tables.Devices, row)
+	d.ID = row.ID
+
+	for _, id := range roleIDs {
+		s.tables.DeviceRoles = append(s.tables.DeviceRoles, structs.DeviceRole{
+			ID:                     s.nextID("DeviceRole", 0),
+			DeviceID:               d.ID,
+			DeviceRoleDefinitionID: id,
+		})
+	}
+
+	for _, id := range powerStateIDs {
+		s.tables.DevicePowerStates = append(s.tables.DevicePowerStates, structs.DevicePowerState{
+			ID:           s.nextID("DevicePowerStates", 0),
+			DeviceID:     d.ID,
+			PowerStateID: id,
+		})
+	}
+
+	// clean up d
+	d.Room.Devices = nil
+	d.Room.Configuration.Evaluators = nil
+
+	return d, nil
+}
+
+// SetDeviceAttribute sets one of the whitelisted columns on a device. Other names are saved as
+// device attributes.
+func (s *Store) SetDeviceAttribute(ctx context.Context, info structs.DeviceAttributeInfo) (structs.Device, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	index := s.tables.deviceIndex(info.DeviceID)
+	if index < 0 {
+		return structs.Device{}, accessors.Errorf(accessors.NotFound, "No devices found for ID %d", info.DeviceID)
+	}
+	row := &s.tables.Devices[index]
+
+	toInt := func() (int, error) {
+		value, err := strconv.Atoi(info.AttributeValue)
+		if err != nil {
+			return 0, &accessors.Error{Kind: accessors.Validation, Err: err}
+		}
+		return value, nil
+	}
+	toBool := func() (bool, error) {
+		switch info.AttributeValue {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return false, accessors.Errorf(accessors.Validation, "Invalid value for a boolean column")
+	}
+
+	var err error
+	switch info.AttributeName {
+	case "address":
+		row.Address = info.AttributeValue
+	case "displayName":
+		row.DisplayName = info.AttributeValue
+	case "input":
+		row.Input, err = toBool()
+	case "output":
+		row.Output, err = toBool()
+	case "buildingID":
+		row.BuildingID, err = toInt()
+	case "roomID":
+		row.RoomID, err = toInt()
+	case "classID":
+		row.ClassID, err = toInt()
+	case "typeID":
+		var typeID int
+		typeID, err = toInt()
+		if err == nil {
+			err = s.tables.checkDeviceAttributes(*row, typeID)
+		}
+		if err == nil {
+			row.TypeID = typeID
+		}
+	default:
+		var attribute structs.DeviceAttribute
+		attribute, err = accessors.ParseAttribute(info.AttributeName, info.AttributeType, info.AttributeValue)
+		if err == nil {
+			_, err = s.putAttribute(row.ID, attribute)
+		}
+	}
+	if err != nil {
+		return structs.Device{}, err
+	}
+
+	devices := s.devicesWhere(func(d Device) bool {
+		return d.ID == info.DeviceID
+	})
+	if len(devices) == 0 {
+		return structs.Device{}, accessors.Errorf(accessors.NotFound, "No devices found for ID %d", info.DeviceID)
+	}
+
+	return devices[0], nil
+}
+
+// DeleteDevice deletes a device along with every row that points at it
+func (s *Store) DeleteDevice(ctx context.Context, buildingShortname string, roomName string, deviceName string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	device, ok := s.tables.deviceByName(buildingShortname, roomName, deviceName)
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	s.tables.deleteDevices(func(d Device) bool {
+		return d.ID == device.ID
+	})
+
+	return nil
+}
+
+// UpdateDevice replaces a device's columns and brings its roles and power states in line with
+// d's. A nil Roles or PowerStates leaves them as they are. Everything is checked before anything
+// is written.
+func (s *Store) UpdateDevice(ctx context.Context, buildingShortname string, roomName string, deviceName string, d structs.Device) (structs.Device, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	index := -1
+	for i, row := range s.tables.Devices {
+		if row.Name == deviceName && s.tables.inRoom(row, buildingShortname, roomName, false) {
+			index = i
+		}
+	}
+
+	if index < 0 {
+		return structs.Device{}, sql.ErrNoRows
+	}
+
+	if len(d.Name) == 0 {
+		d.Name = deviceName
+	}
+
+	if d.Name != deviceName {
+		for _, existing := range s.tables.Devices {
+			if existing.Name == d.Name && s.tables.inRoom(existing, buildingShortname, roomName, false) {
+				return structs.Device{}, accessors.Errorf(accessors.Conflict, "there is already a device called %s in %s-%s", d.Name, buildingShortname, roomName)
+			}
+		}
+	}
+
+	class, ok := s.tables.deviceClassByName(d.Type)
+	if !ok {
+		return structs.Device{}, accessors.Errorf(accessors.Validation, "device type: %v does not exist", d.Type)
+	}
+
+	deviceType, ok := s.tables.deviceTypeByName(d.Class)
+	if !ok {
+		return structs.Device{}, accessors.Errorf(accessors.Validation, "device class: %v does not exist", d.Class)
+	}
+
+	if d.Roles != nil && len(d.Roles) == 0 {
+		return structs.Device{}, accessors.Errorf(accessors.Validation, "a device needs at least one role; devices without roles aren't returned by the API")
+	}
+
+	var roleIDs []int
+	for _, role := range d.Roles {
+		r, ok := s.tables.roleDefinitionByName(role)
+		if !ok {
+			return structs.Device{}, accessors.Errorf(accessors.Validation, "device role definition: %v does not exist", role)
+		}
+		roleIDs = append(roleIDs, r.ID)
+	}
+
+	var powerStateIDs []int
+	for _, ps := range d.PowerStates {
+		p, ok := s.tables.powerStateByName(ps)
+		if !ok {
+			return structs.Device{}, accessors.Errorf(accessors.Validation, "powerstate: %v does not exist", ps)
+		}
+		powerStateIDs = append(powerStateIDs, p.ID)
+	}
+
+	// a new class can come with a schema the device's attributes don't fit
+	err := s.tables.checkDeviceAttributes(s.tables.Devices[index], deviceType.ID)
+	if err != nil {
+		return structs.Device{}, err
+	}
+
+	if len(d.DisplayName) == 0 {
+		d.DisplayName = deviceType.DisplayName
+	}
+
+	row := &s.tables.Devices[index]
+	row.Name = d.Name
+	row.Address = d.Address
+	row.Input = d.Input
+	row.Output = d.Output
+	row.ClassID = class.ID
+	row.TypeID = deviceType.ID
+	row.DisplayName = d.DisplayName
+	id := row.ID
+
+	if d.Roles != nil {
+		s.setDeviceRoles(id, roleIDs)
+	}
+
+	if d.PowerStates != nil {
+		s.setDevicePowerStates(id, powerStateIDs)
+	}
+
+	devices := s.devicesWhere(func(d Device) bool {
+		return d.ID == id
+	})
+
+	return devices[0], nil
+}
+
+// SetDeviceTypeByID points a device at a different entry in DeviceTypes
+func (s *Store) SetDeviceTypeByID(ctx context.Context, id int, deviceID int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	index := s.tables.deviceIndex(deviceID)
+	if index < 0 {
+		return fmt.Errorf("There was a problem updating the device type: incorrect number of rows affected: 0. ")
+	}
+
+	// the new type's schema has to take the attributes the device already has
+	err := s.tables.checkDeviceAttributes(s.tables.Devices[index], id)
+	if err != nil {
+		return err
+	}
+
+	s.tables.Devices[index].TypeID = id
+	return nil
+}
+
+// PutDeviceAttributeByDeviceAndRoomAndBuilding sets volume or muted on an audio device, or
+// anything else as a device attribute
+func (s *Store) PutDeviceAttributeByDeviceAndRoomAndBuilding(ctx context.Context, building string, room string, device string, attribute string, attributeValue string) (structs.Device, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var audioDevices []*AudioDevice
+	for i := range s.tables.AudioDevices {
+		row, ok := s.tables.deviceRow(s.tables.AudioDevices[i].DeviceID)
+		if ok && like(row.Name, device) && s.tables.inRoom(row, building, room, true) {
+			audioDevices = append(audioDevices, &s.tables.AudioDevices[i])
+		}
+	}
+
+	switch strings.ToLower(attribute) {
+	case "volume", "muted":
+		if row, ok := s.tables.deviceByName(building, room, device); ok {
+			err := s.tables.checkDeclaredAttribute(row, strings.ToLower(attribute), attributeValue)
+			if err != nil {
+				return structs.Device{}, err
+			}
+		}
+	}
+
+	switch strings.ToLower(attribute) {
+	case "volume":
+		val, err := strconv.Atoi(attributeValue)
+		if err != nil {
+			return structs.Device{}, &accessors.Error{Kind: accessors.Validation, Err: err}
+		}
+
+		for _, audioDevice := range audioDevices {
+			audioDevice.Volume = val
+		}
+
+	case "muted":
+		var valToSet bool
+		switch attributeValue {
+		case "true":
+			valToSet = true
+		case "false":
+			valToSet = false
+		default:
+			return structs.Device{}, accessors.Errorf(accessors.Validation, "Invalid attribute value, must be a boolean.")
+		}
+
+		for _, audioDevice := range audioDevices {
+			audioDevice.Muted = valToSet
+		}
+
+	default:
+		toSet, err := accessors.ParseAttribute(attribute, "", attributeValue)
+		if err != nil {
+			return structs.Device{}, err
+		}
+
+		row, ok := s.tables.deviceByName(building, room, device)
+		if !ok {
+			return structs.Device{}, sql.ErrNoRows
+		}
+
+		_, err = s.putAttribute(row.ID, toSet)
+		if err != nil {
+			return structs.Device{}, err
+		}
+	}
+
+	devices := s.devicesWhere(func(d Device) bool {
+		return d.Name == device && s.tables.inRoom(d, building, room, false)
+	})
+	if len(devices) == 0 {
+		return structs.Device{}, accessors.Errorf(accessors.NotFound, "No devices found with that name.")
+	}
+
+	return devices[0], nil
+}
+
+// devicesWhere is Tables.devicesWhere with the relations s wasn't asked to expand left off
+func (s *Store) devicesWhere(match func(Device) bool) []structs.Device {
+	devices := s.tables.devicesWhere(match)
+	s.expand.Trim(devices)
+
+	return devices
+}
+
+// devicesWhere builds the complete device for every row that matches. Like GetDevicesByQuery,
+// rows that are missing their room, building, class, type or roles are left out.
+func (t *Tables) devicesWhere(match func(Device) bool) []structs.Device {
+	allDevices := []structs.Device{}
+
+	for _, row := range t.Devices {
+		if !match(row) {
+			continue
+		}
+
+		device, ok := t.device(row)
+		if !ok {
+			continue
+		}
+
+		allDevices = append(allDevices, device)
+	}
+
+	return allDevices
+}
+
+func (t *Tables) device(row Device) (structs.Device, bool) {
+	room, ok := t.roomRow(row.RoomID)
+	if !ok {
+		return structs.Device{}, false
+	}
+
+	building, ok := t.building(row.BuildingID)
+	if !ok {
+		return structs.Device{}, false
+	}
+
+	class, ok := t.deviceClass(row.ClassID)
+	if !ok {
+		return structs.Device{}, false
+	}
+
+	deviceType, ok := t.deviceType(row.TypeID)
+	if !ok {
+		return structs.Device{}, false
+	}
+
+	roles := t.roles(row.ID)
+	if len(roles) == 0 {
+		return structs.Device{}, false
+	}
+
+	device := structs.Device{
+		ID:          row.ID,
+		Name:        row.Name,
+		Address:     row.Address,
+		Input:       row.Input,
+		Output:      row.Output,
+		DisplayName: row.DisplayName,
+		Room: structs.Room{
+			ID:              room.ID,
+			Name:            room.Name,
+			Description:     room.Description,
+			RoomDesignation: room.RoomDesignation,
+		},
+		Building:    building,
+		Type:        class.Name,
+		Class:       deviceType.Name,
+		Roles:       roles,
+		PowerStates: t.powerStates(row.ID),
+		Commands:    t.commands(row.TypeID),
+		Ports:       t.ports(row.ID),
+		Attributes:  t.attributes(row),
+	}
+
+	return device, true
+}
+
+func (t *Tables) deviceRow(id int) (Device, bool) {
+	index := t.deviceIndex(id)
+	if index < 0 {
+		return Device{}, false
+	}
+
+	return t.Devices[index], true
+}
+
+func (t *Tables) deviceIndex(id int) int {
+	for i := range t.Devices {
+		if t.Devices[i].ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// setDeviceRoles leaves the device with exactly the given role definitions, keeping the rows
+// it already has
+func (s *Store) setDeviceRoles(deviceID int, want []int) {
+	wanted := make(map[int]bool)
+	for _, id := range want {
+		wanted[id] = true
+	}
+
+	roles := s.tables.DeviceRoles[:0]
+	for _, row := range s.tables.DeviceRoles {
+		if row.DeviceID == deviceID && !wanted[row.DeviceRoleDefinitionID] {
+			continue
+		}
+		if row.DeviceID == deviceID {
+			delete(wanted, row.DeviceRoleDefinitionID)
+		}
+		roles = append(roles, row)
+	}
+	s.tables.DeviceRoles = roles
+
+	for _, id := range want {
+		if !wanted[id] {
+			continue
+		}
+		delete(wanted, id)
+
+		s.tables.DeviceRoles = append(s.tables.DeviceRoles, structs.DeviceRole{
+			ID:                     s.nextID("DeviceRole", 0),
+			DeviceID:               deviceID,
+			DeviceRoleDefinitionID: id,
+		})
+	}
+}
+
+// setDevicePowerStates is setDeviceRoles for power states
+func (s *Store) setDevicePowerStates(deviceID int, want []int) {
+	wanted := make(map[int]bool)
+	for _, id := range want {
+		wanted[id] = true
+	}
+
+	powerStates := s.tables.DevicePowerStates[:0]
+	for _, row := range s.tables.DevicePowerStates {
+		if row.DeviceID == deviceID && !wanted[row.PowerStateID] {
+			continue
+		}
+		if row.DeviceID == deviceID {
+			delete(wanted, row.PowerStateID)
+		}
+		powerStates = append(powerStates, row)
+	}
+	s.tables.DevicePowerStates = powerStates
+
+	for _, id := range want {
+		if !wanted[id] {
+			continue
+		}
+		delete(wanted, id)
+
+		s.tables.DevicePowerStates = append(s.tables.DevicePowerStates, structs.DevicePowerState{
+			ID:           s.nextID("DevicePowerStates", 0),
+			DeviceID:     deviceID,
+			PowerStateID: id,
+		})
+	}
+}
+
+func (t *Tables) deviceByName(buildingShortname string, roomName string, deviceName string) (Device, bool) {
+	for _, row := range t.Devices {
+		if row.Name == deviceName && t.inRoom(row, buildingShortname, roomName, false) {
+			return row, true
+		}
+	}
+
+	return Device{}, false
+}
+
+// fullName returns the device's building-room-name
+func (t *Tables) fullName(row Device) string {
+	device := structs.Device{Name: row.Name}
+
+	if room, ok := t.roomRow(row.RoomID); ok {
+		device.Room.Name = room.Name
+
+		if building, ok := t.building(room.BuildingID); ok {
+			device.Building = building
+		}
+	}
+
+	return device.GetFullName()
+}
+
+// roomFullName returns the building-room the device is in, or empty if its room doesn't exist
+func (t *Tables) roomFullName(row Device) string {
+	room, ok := t.roomRow(row.RoomID)
+	if !ok {
+		return ""
+	}
+
+	building, ok := t.building(room.BuildingID)
+	if !ok {
+		return ""
+	}
+
+	return building.Shortname + "-" + room.Name
+}
+
+// inRoom reports whether the device is in the room. If fuzzy is set the names are compared
+// the way the MySQL accessors compare them with LIKE.
+func (t *Tables) inRoom(d Device, buildingShortname string, roomName string, fuzzy bool) bool {
+	room, ok := t.roomRow(d.RoomID)
+	if !ok {
+		return false
+	}
+
+	building, ok := t.building(d.BuildingID)
+	if !ok {
+		return false
+	}
+
+	if fuzzy {
+		return like(room.Name, roomName) && like(building.Shortname, buildingShortname)
+	}
+
+	return room.Name == roomName && building.Shortname == buildingShortname
+}
+
+func (t *Tables) commands(deviceTypeID int) []structs.Command {
+	allCommands := []structs.Command{}
+
+	for _, mapping := range t.DeviceTypeCommands {
+		if mapping.DeviceTypeID != deviceTypeID {
+			continue
+		}
+
+		command, ok := t.command(mapping.CommandID)
+		if !ok {
+			continue
+		}
+
+		endpoint, ok := t.endpoint(mapping.EndpointID)
+		if !ok {
+			continue
+		}
+
+		microservice, ok := t.microservice(mapping.MicroserviceID)
+		if !ok {
+			continue
+		}
+
+		allCommands = append(allCommands, structs.Command{
+			Name:         command.Name,
+			Endpoint:     structs.Endpoint{Name: endpoint.Name, Path: endpoint.Path},
+			Microservice: microservice.Address,
+		})
+	}
+
+	return allCommands
+}
+
+func (t *Tables) ports(hostID int) []structs.Port {
+	allPorts := []structs.Port{}
+
+	for _, pc := range t.PortConfigurations {
+		if pc.HostDeviceID != hostID {
+			continue
+		}
+
+		port, ok := t.port(pc.PortID)
+		if !ok {
+			continue
+		}
+
+		source, ok := t.deviceRow(pc.SourceDeviceID)
+		if !ok {
+			continue
+		}
+
+		destination, ok := t.deviceRow(pc.DestinationDeviceID)
+		if !ok {
+			continue
+		}
+
+		host, ok := t.deviceRow(pc.HostDeviceID)
+		if !ok {
+			continue
+		}
+
+		allPorts = append(allPorts, structs.Port{
+			Source:      source.Name,
+			Name:        port.Name,
+			Destination: destination.Name,
+			Host:        host.Name,
+		})
+	}
+
+	return allPorts
+}
+
+// like compares two values the way a LIKE without wildcards does under MySQL's default collation
+func like(value, pattern string) bool {
+	return strings.EqualFold(value, pattern)
+}
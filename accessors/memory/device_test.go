@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetDevicesByRoleAndType(t *testing.T) {
+	tests := []struct {
+		name        string
+		role        string
+		class       string
+		designation string
+		rooms       []string
+	}{
+		{name: "production", role: "VideoOut", class: "display", designation: "production", rooms: []string{"1101"}},
+		{name: "production in capitals", role: "VideoOut", class: "display", designation: "Production", rooms: []string{"1101"}},
+		{name: "stage", role: "VideoOut", class: "display", designation: "STAGE", rooms: []string{"1001D"}},
+		{name: "a role the displays don't have", role: "VideoIn", class: "display", designation: "production"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := newFixtureStore(t)
+
+			devices, err := store.GetDevicesByRoleAndType(context.Background(), test.role, test.class, test.designation)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rooms := []string{}
+			for _, device := range devices {
+				rooms = append(rooms, device.Room.Name)
+			}
+			if len(rooms) != len(test.rooms) || (len(rooms) > 0 && rooms[0] != test.rooms[0]) {
+				t.Errorf("found devices in %v, want %v", rooms, test.rooms)
+			}
+		})
+	}
+}
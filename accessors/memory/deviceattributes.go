@@ -0,0 +1,211 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// GetDeviceAttributes returns a device's attributes, sorted by name, including the defaults from
+// its type's schema for the ones it hasn't set
+func (s *Store) GetDeviceAttributes(ctx context.Context, buildingShortname string, roomName string, deviceName string) ([]structs.DeviceAttribute, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	device, ok := s.tables.deviceByName(buildingShortname, roomName, deviceName)
+	if !ok {
+		return []structs.DeviceAttribute{}, sql.ErrNoRows
+	}
+
+	toReturn := []structs.DeviceAttribute{}
+	set := make(map[string]bool)
+	for _, row := range s.tables.DeviceAttributes {
+		if row.DeviceID != device.ID {
+			continue
+		}
+
+		value, err := accessors.DecodeAttributeValue(row.Type, row.Value)
+		if err != nil {
+			return []structs.DeviceAttribute{}, err
+		}
+
+		toReturn = append(toReturn, structs.DeviceAttribute{Name: row.Name, Type: row.Type, Value: value})
+		set[row.Name] = true
+	}
+
+	schema, err := s.tables.schema(device.TypeID)
+	if err != nil {
+		return []structs.DeviceAttribute{}, err
+	}
+	toReturn = append(toReturn, accessors.AttributeDefaults(schema, set)...)
+
+	sort.Slice(toReturn, func(i, j int) bool {
+		return toReturn[i].Name < toReturn[j].Name
+	})
+
+	return toReturn, nil
+}
+
+// PutDeviceAttribute sets an attribute on a device, adding it if the device doesn't have it yet
+func (s *Store) PutDeviceAttribute(ctx context.Context, buildingShortname string, roomName string, deviceName string, attribute structs.DeviceAttribute) (structs.DeviceAttribute, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	device, ok := s.tables.deviceByName(buildingShortname, roomName, deviceName)
+	if !ok {
+		return structs.DeviceAttribute{}, sql.ErrNoRows
+	}
+
+	return s.putAttribute(device.ID, attribute)
+}
+
+// DeleteDeviceAttribute removes an attribute from a device
+func (s *Store) DeleteDeviceAttribute(ctx context.Context, buildingShortname string, roomName string, deviceName string, name string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	device, ok := s.tables.deviceByName(buildingShortname, roomName, deviceName)
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	deleted := false
+	attributes := s.tables.DeviceAttributes[:0]
+	for _, row := range s.tables.DeviceAttributes {
+		if row.DeviceID == device.ID && row.Name == name {
+			deleted = true
+			continue
+		}
+		attributes = append(attributes, row)
+	}
+	s.tables.DeviceAttributes = attributes
+
+	if !deleted {
+		return accessors.Errorf(accessors.NotFound, "%s-%s-%s has no attribute %s", buildingShortname, roomName, deviceName, name)
+	}
+
+	return nil
+}
+
+// GetDevicesByAttribute returns the devices that have an attribute, whether they set it or get it
+// from their type's default, or, if value isn't empty, the ones whose attribute equals it
+func (s *Store) GetDevicesByAttribute(ctx context.Context, name string, value string) ([]structs.Device, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	matches := make(map[int]bool)
+	set := make(map[int]bool)
+	for _, row := range s.tables.DeviceAttributes {
+		if row.Name != name {
+			continue
+		}
+
+		set[row.DeviceID] = true
+		if len(value) == 0 || accessors.AttributeMatches(row.Type, row.Value, value) {
+			matches[row.DeviceID] = true
+		}
+	}
+
+	// devices that haven't set it have it if their type has a default
+	defaults := make(map[int]bool)
+	for _, row := range s.tables.DeviceTypeAttributes {
+		if row.Name != name || row.Default == nil {
+			continue
+		}
+
+		rule, err := accessors.NormalizeAttributeSchema(row.DeviceTypeAttribute)
+		if err != nil {
+			return []structs.Device{}, err
+		}
+
+		if len(value) == 0 || accessors.AttributeMatches(rule.Type, accessors.EncodeAttributeValue(rule.Default), value) {
+			defaults[row.DeviceTypeID] = true
+		}
+	}
+
+	return s.devicesWhere(func(d Device) bool {
+		return matches[d.ID] || (!set[d.ID] && defaults[d.TypeID])
+	}), nil
+}
+
+// putAttribute inserts or updates an attribute on the device with the given ID, once it's
+// checked it against the schema of the device's type
+func (s *Store) putAttribute(deviceID int, attribute structs.DeviceAttribute) (structs.DeviceAttribute, error) {
+	var typeName string
+	schema := []structs.DeviceTypeAttribute{}
+	if row, ok := s.tables.deviceRow(deviceID); ok {
+		if deviceType, ok := s.tables.deviceType(row.TypeID); ok {
+			typeName = deviceType.Name
+
+			var err error
+			schema, err = s.tables.schema(row.TypeID)
+			if err != nil {
+				return structs.DeviceAttribute{}, err
+			}
+		}
+	}
+
+	attribute, err := accessors.ValidateAttribute(typeName, schema, attribute)
+	if err != nil {
+		return structs.DeviceAttribute{}, err
+	}
+	attribute.IsDefault = false
+	text := accessors.EncodeAttributeValue(attribute.Value)
+
+	for i, row := range s.tables.DeviceAttributes {
+		if row.DeviceID == deviceID && row.Name == attribute.Name {
+			s.tables.DeviceAttributes[i].Type = attribute.Type
+			s.tables.DeviceAttributes[i].Value = text
+			return attribute, nil
+		}
+	}
+
+	s.tables.DeviceAttributes = append(s.tables.DeviceAttributes, DeviceAttribute{
+		ID:       s.nextID("DeviceAttributes", 0),
+		DeviceID: deviceID,
+		Name:     attribute.Name,
+		Type:     attribute.Type,
+		Value:    text,
+	})
+
+	return attribute, nil
+}
+
+// attributes returns a device's attributes keyed by name, along with the defaults its type gives
+// it. Rows that don't decode are left out.
+func (t *Tables) attributes(device Device) map[string]interface{} {
+	toReturn := make(map[string]interface{})
+
+	for _, row := range t.DeviceAttributes {
+		if row.DeviceID != device.ID {
+			continue
+		}
+
+		value, err := accessors.DecodeAttributeValue(row.Type, row.Value)
+		if err != nil {
+			continue
+		}
+
+		toReturn[row.Name] = value
+	}
+
+	set := make(map[string]bool)
+	for name := range toReturn {
+		set[name] = true
+	}
+
+	// a schema that doesn't normalize gives no defaults, rather than failing every lookup
+	schema, err := t.schema(device.TypeID)
+	if err != nil {
+		return toReturn
+	}
+
+	for _, attribute := range accessors.AttributeDefaults(schema, set) {
+		toReturn[attribute.Name] = attribute.Value
+	}
+
+	return toReturn
+}
@@ -0,0 +1,326 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// GetDeviceTypeAttributes returns the attribute schema of an entry in DeviceTypes, sorted by name
+func (s *Store) GetDeviceTypeAttributes(ctx context.Context, typeName string) ([]structs.DeviceTypeAttribute, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	deviceType, ok := s.tables.deviceTypeByName(typeName)
+	if !ok {
+		return []structs.DeviceTypeAttribute{}, accessors.Errorf(accessors.NotFound, "there is no device type called %s", typeName)
+	}
+
+	return s.tables.schema(deviceType.ID)
+}
+
+// SetDeviceTypeAttributes replaces a device type's whole schema in one go, so a type whose devices
+// already have several attributes can declare them all at once. It's refused if any device of the
+// type has attributes the new schema wouldn't allow; an empty schema lets them have any attribute.
+func (s *Store) SetDeviceTypeAttributes(ctx context.Context, typeName string, schema []structs.DeviceTypeAttribute) ([]structs.DeviceTypeAttribute, error) {
+	rules, err := accessors.NormalizeAttributeSchemas(schema)
+	if err != nil {
+		return []structs.DeviceTypeAttribute{}, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deviceType, ok := s.tables.deviceTypeByName(typeName)
+	if !ok {
+		return []structs.DeviceTypeAttribute{}, accessors.Errorf(accessors.NotFound, "there is no device type called %s", typeName)
+	}
+
+	err = s.tables.checkTypeAttributes(deviceType, rules)
+	if err != nil {
+		return []structs.DeviceTypeAttribute{}, err
+	}
+
+	kept := s.tables.DeviceTypeAttributes[:0]
+	for _, row := range s.tables.DeviceTypeAttributes {
+		if row.DeviceTypeID != deviceType.ID {
+			kept = append(kept, row)
+		}
+	}
+	s.tables.DeviceTypeAttributes = kept
+
+	for _, rule := range rules {
+		s.tables.DeviceTypeAttributes = append(s.tables.DeviceTypeAttributes, DeviceTypeAttribute{
+			ID:                  s.nextID("DeviceTypeAttributes", 0),
+			DeviceTypeID:        deviceType.ID,
+			DeviceTypeAttribute: rule,
+		})
+	}
+
+	return s.tables.schema(deviceType.ID)
+}
+
+// AddDeviceTypeAttribute adds an attribute to a device type's schema. It's refused if any device
+// of the type has attributes the new schema wouldn't allow.
+func (s *Store) AddDeviceTypeAttribute(ctx context.Context, typeName string, rule structs.DeviceTypeAttribute) (structs.DeviceTypeAttribute, error) {
+	added, err := accessors.NormalizeAttributeSchema(rule)
+	if err != nil {
+		return structs.DeviceTypeAttribute{}, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deviceType, ok := s.tables.deviceTypeByName(typeName)
+	if !ok {
+		return structs.DeviceTypeAttribute{}, accessors.Errorf(accessors.NotFound, "there is no device type called %s", typeName)
+	}
+
+	if s.tables.schemaIndex(deviceType.ID, added.Name) >= 0 {
+		return structs.DeviceTypeAttribute{}, accessors.Errorf(accessors.Conflict, "%s already has an attribute called %s", typeName, added.Name)
+	}
+
+	schema, err := s.tables.schema(deviceType.ID)
+	if err != nil {
+		return structs.DeviceTypeAttribute{}, err
+	}
+
+	err = s.tables.checkTypeAttributes(deviceType, append(schema, added))
+	if err != nil {
+		return structs.DeviceTypeAttribute{}, err
+	}
+
+	s.tables.DeviceTypeAttributes = append(s.tables.DeviceTypeAttributes, DeviceTypeAttribute{
+		ID:                  s.nextID("DeviceTypeAttributes", 0),
+		DeviceTypeID:        deviceType.ID,
+		DeviceTypeAttribute: added,
+	})
+
+	return added, nil
+}
+
+// UpdateDeviceTypeAttribute replaces the rules for an attribute in a device type's schema. It's
+// refused if any device of the type has a value the new rules wouldn't allow.
+func (s *Store) UpdateDeviceTypeAttribute(ctx context.Context, typeName string, name string, rule structs.DeviceTypeAttribute) (structs.DeviceTypeAttribute, error) {
+	rule.Name = name
+	updated, err := accessors.NormalizeAttributeSchema(rule)
+	if err != nil {
+		return structs.DeviceTypeAttribute{}, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deviceType, ok := s.tables.deviceTypeByName(typeName)
+	if !ok {
+		return structs.DeviceTypeAttribute{}, accessors.Errorf(accessors.NotFound, "there is no device type called %s", typeName)
+	}
+
+	index := s.tables.schemaIndex(deviceType.ID, name)
+	if index < 0 {
+		return structs.DeviceTypeAttribute{}, accessors.Errorf(accessors.NotFound, "%s has no attribute called %s", typeName, name)
+	}
+
+	schema, err := s.tables.schema(deviceType.ID)
+	if err != nil {
+		return structs.DeviceTypeAttribute{}, err
+	}
+
+	for i := range schema {
+		if schema[i].Name == name {
+			schema[i] = updated
+		}
+	}
+
+	err = s.tables.checkTypeAttributes(deviceType, schema)
+	if err != nil {
+		return structs.DeviceTypeAttribute{}, err
+	}
+
+	s.tables.DeviceTypeAttributes[index].DeviceTypeAttribute = updated
+
+	return updated, nil
+}
+
+// DeleteDeviceTypeAttribute removes an attribute from a device type's schema. While the schema
+// has other attributes, devices of the type can't keep one it no longer lists, so it's refused
+// if any of them have it. Removing the last one lets the type's devices have any attribute.
+func (s *Store) DeleteDeviceTypeAttribute(ctx context.Context, typeName string, name string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deviceType, ok := s.tables.deviceTypeByName(typeName)
+	if !ok {
+		return accessors.Errorf(accessors.NotFound, "there is no device type called %s", typeName)
+	}
+
+	index := s.tables.schemaIndex(deviceType.ID, name)
+	if index < 0 {
+		return accessors.Errorf(accessors.NotFound, "%s has no attribute called %s", typeName, name)
+	}
+
+	schema, err := s.tables.schema(deviceType.ID)
+	if err != nil {
+		return err
+	}
+
+	remaining := []structs.DeviceTypeAttribute{}
+	for _, rule := range schema {
+		if rule.Name != name {
+			remaining = append(remaining, rule)
+		}
+	}
+
+	err = s.tables.checkTypeAttributes(deviceType, remaining)
+	if err != nil {
+		return err
+	}
+
+	s.tables.DeviceTypeAttributes = append(s.tables.DeviceTypeAttributes[:index], s.tables.DeviceTypeAttributes[index+1:]...)
+
+	return nil
+}
+
+// schema returns a device type's attribute schema, sorted by name
+func (t *Tables) schema(typeID int) ([]structs.DeviceTypeAttribute, error) {
+	schema := []structs.DeviceTypeAttribute{}
+
+	for _, row := range t.DeviceTypeAttributes {
+		if row.DeviceTypeID != typeID {
+			continue
+		}
+
+		// puts the enum and default in the attribute's type, e.g. after coming from a fixture
+		rule, err := accessors.NormalizeAttributeSchema(row.DeviceTypeAttribute)
+		if err != nil {
+			return []structs.DeviceTypeAttribute{}, err
+		}
+
+		schema = append(schema, rule)
+	}
+
+	sort.Slice(schema, func(i, j int) bool {
+		return schema[i].Name < schema[j].Name
+	})
+
+	return schema, nil
+}
+
+func (t *Tables) schemaIndex(typeID int, name string) int {
+	for i, row := range t.DeviceTypeAttributes {
+		if row.DeviceTypeID == typeID && row.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// checkTypeAttributes makes sure every device of a type would fit schema, returning a Conflict
+// listing the attributes that wouldn't
+func (t *Tables) checkTypeAttributes(deviceType structs.DeviceClass, schema []structs.DeviceTypeAttribute) error {
+	problems := []string{}
+	for _, row := range t.Devices {
+		if row.TypeID == deviceType.ID {
+			problems = append(problems, t.attributeProblems(row, deviceType.Name, schema)...)
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return &accessors.Error{
+			Kind:    accessors.Conflict,
+			Err:     fmt.Errorf("some %s devices have attributes the schema wouldn't allow; change them first", deviceType.Name),
+			Details: problems,
+		}
+	}
+
+	return nil
+}
+
+// checkDeviceAttributes makes sure a device's attributes would fit the schema of the type with
+// the given ID, e.g. before its type changes, returning a Validation error listing the ones that
+// wouldn't
+func (t *Tables) checkDeviceAttributes(row Device, typeID int) error {
+	deviceType, ok := t.deviceType(typeID)
+	if !ok {
+		// a device without a type has no schema to follow
+		return nil
+	}
+
+	schema, err := t.schema(typeID)
+	if err != nil {
+		return err
+	}
+
+	problems := t.attributeProblems(row, deviceType.Name, schema)
+	if len(problems) > 0 {
+		return &accessors.Error{
+			Kind:    accessors.Validation,
+			Err:     fmt.Errorf("the device has attributes its type doesn't allow; change them first"),
+			Details: problems,
+		}
+	}
+
+	return nil
+}
+
+// checkDeclaredAttribute checks a value for one of the audio device columns (volume or muted)
+// against the device type's schema, if it lists that name; the columns themselves aren't
+// device attributes, so a type that doesn't list them doesn't limit them
+func (t *Tables) checkDeclaredAttribute(row Device, name string, text string) error {
+	deviceType, ok := t.deviceType(row.TypeID)
+	if !ok {
+		return nil
+	}
+
+	schema, err := t.schema(row.TypeID)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range schema {
+		if rule.Name != name {
+			continue
+		}
+
+		attribute, err := accessors.ParseAttribute(name, rule.Type, text)
+		if err != nil {
+			return err
+		}
+
+		_, err = accessors.ValidateAttribute(deviceType.Name, schema, attribute)
+		return err
+	}
+
+	return nil
+}
+
+// attributeProblems checks a device's attributes against schema, returning a line for each one
+// that doesn't fit
+func (t *Tables) attributeProblems(row Device, typeName string, schema []structs.DeviceTypeAttribute) []string {
+	problems := []string{}
+
+	for _, stored := range t.DeviceAttributes {
+		if stored.DeviceID != row.ID {
+			continue
+		}
+
+		attribute := structs.DeviceAttribute{Name: stored.Name, Type: stored.Type}
+
+		var err error
+		attribute.Value, err = accessors.DecodeAttributeValue(stored.Type, stored.Value)
+		if err == nil {
+			_, err = accessors.ValidateAttribute(typeName, schema, attribute)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", t.fullName(row), err))
+		}
+	}
+	sort.Strings(problems)
+
+	return problems
+}
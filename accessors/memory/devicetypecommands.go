@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// GetDeviceTypeCommands returns the commands mapped to an entry in DeviceTypes, along with the
+// devices of that type
+func (s *Store) GetDeviceTypeCommands(ctx context.Context, typeName string) (structs.DeviceTypeCommands, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	deviceType, ok := s.tables.deviceTypeByName(typeName)
+	if !ok {
+		return structs.DeviceTypeCommands{}, accessors.Errorf(accessors.NotFound, "there is no device type called %s", typeName)
+	}
+
+	toReturn := structs.DeviceTypeCommands{Type: typeName, Commands: []structs.DeviceTypeCommand{}, Devices: []string{}}
+
+	for _, mapping := range s.tables.DeviceTypeCommands {
+		if mapping.DeviceTypeID != deviceType.ID {
+			continue
+		}
+
+		command, ok := s.tables.command(mapping.CommandID)
+		if !ok {
+			continue
+		}
+
+		endpoint, ok := s.tables.endpoint(mapping.EndpointID)
+		if !ok {
+			continue
+		}
+
+		microservice, ok := s.tables.microservice(mapping.MicroserviceID)
+		if !ok {
+			continue
+		}
+
+		toReturn.Commands = append(toReturn.Commands, structs.DeviceTypeCommand{
+			Command:      command.Name,
+			Endpoint:     endpoint.Name,
+			Microservice: microservice.Name,
+		})
+	}
+
+	for _, row := range s.tables.Devices {
+		if row.TypeID == deviceType.ID {
+			toReturn.Devices = append(toReturn.Devices, s.tables.fullName(row))
+		}
+	}
+
+	sort.Slice(toReturn.Commands, func(i, j int) bool {
+		return toReturn.Commands[i].Command < toReturn.Commands[j].Command
+	})
+	sort.Strings(toReturn.Devices)
+
+	return toReturn, nil
+}
+
+// AddDeviceTypeCommand maps a command to an endpoint and microservice for every device of a type
+func (s *Store) AddDeviceTypeCommand(ctx context.Context, typeName string, command structs.DeviceTypeCommand) (structs.DeviceTypeCommand, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deviceType, ok := s.tables.deviceTypeByName(typeName)
+	if !ok {
+		return structs.DeviceTypeCommand{}, accessors.Errorf(accessors.NotFound, "there is no device type called %s", typeName)
+	}
+
+	mapping := DeviceTypeCommand{DeviceTypeID: deviceType.ID}
+
+	for _, c := range s.tables.Commands {
+		if c.Name == command.Command {
+			mapping.CommandID = c.ID
+		}
+	}
+	if mapping.CommandID == 0 {
+		return structs.DeviceTypeCommand{}, accessors.Errorf(accessors.Validation, "command: %v does not exist", command.Command)
+	}
+
+	for _, e := range s.tables.Endpoints {
+		if e.Name == command.Endpoint {
+			mapping.EndpointID = e.ID
+		}
+	}
+	if mapping.EndpointID == 0 {
+		return structs.DeviceTypeCommand{}, accessors.Errorf(accessors.Validation, "endpoint: %v does not exist", command.Endpoint)
+	}
+
+	for _, m := range s.tables.Microservices {
+		if m.Name == command.Microservice {
+			mapping.MicroserviceID = m.ID
+		}
+	}
+	if mapping.MicroserviceID == 0 {
+		return structs.DeviceTypeCommand{}, accessors.Errorf(accessors.Validation, "microservice: %v does not exist", command.Microservice)
+	}
+
+	for _, existing := range s.tables.DeviceTypeCommands {
+		if existing.DeviceTypeID == mapping.DeviceTypeID && existing.CommandID == mapping.CommandID {
+			return structs.DeviceTypeCommand{}, accessors.Errorf(accessors.Conflict, "%s already has a mapping for %s; delete it first", typeName, command.Command)
+		}
+	}
+
+	mapping.ID = s.nextID("DeviceTypeCommandMapping", 0)
+	s.tables.DeviceTypeCommands = append(s.tables.DeviceTypeCommands, mapping)
+
+	return command, nil
+}
+
+// DeleteDeviceTypeCommand removes a type's mapping for a command
+func (s *Store) DeleteDeviceTypeCommand(ctx context.Context, typeName string, commandName string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deviceType, ok := s.tables.deviceTypeByName(typeName)
+	if !ok {
+		return accessors.Errorf(accessors.NotFound, "there is no device type called %s", typeName)
+	}
+
+	deleted := false
+	mappings := s.tables.DeviceTypeCommands[:0]
+	for _, mapping := range s.tables.DeviceTypeCommands {
+		if mapping.DeviceTypeID == deviceType.ID {
+			if command, ok := s.tables.command(mapping.CommandID); ok && command.Name == commandName {
+				deleted = true
+				continue
+			}
+		}
+		mappings = append(mappings, mapping)
+	}
+	s.tables.DeviceTypeCommands = mappings
+
+	if !deleted {
+		return accessors.Errorf(accessors.NotFound, "%s has no mapping for %s", typeName, commandName)
+	}
+
+	return nil
+}
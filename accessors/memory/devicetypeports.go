@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// AddDeviceTypePort defines a port on an entry in DeviceTypes
+func (s *Store) AddDeviceTypePort(ctx context.Context, typeName string, dtp structs.DeviceTypePort) (structs.DeviceTypePort, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deviceType, ok := s.tables.deviceTypeByName(typeName)
+	if !ok {
+		return structs.DeviceTypePort{}, accessors.Errorf(accessors.NotFound, "there is no device type called %s", typeName)
+	}
+
+	port, ok := s.tables.portByName(dtp.Port.Name)
+	if !ok {
+		return structs.DeviceTypePort{}, accessors.Errorf(accessors.Validation, "port: %v does not exist", dtp.Port.Name)
+	}
+
+	for _, row := range s.tables.DeviceTypePorts {
+		if row.DeviceTypeID == deviceType.ID && row.PortID == port.ID {
+			return structs.DeviceTypePort{}, accessors.Errorf(accessors.Conflict, "%s already has a port called %s", typeName, port.Name)
+		}
+	}
+
+	row := DeviceTypePort{
+		ID:                    s.nextID("DeviceTypePorts", 0),
+		DeviceTypeID:          deviceType.ID,
+		PortID:                port.ID,
+		Description:           dtp.Description,
+		FriendlyName:          dtp.FriendlyName,
+		HostDestinationMirror: dtp.HostDestintionMirror,
+	}
+	s.tables.DeviceTypePorts = append(s.tables.DeviceTypePorts, row)
+
+	dtp.DeviceTypePortID = row.ID
+	dtp.DeviceTypeID = deviceType.ID
+	dtp.DeviceTypeName = typeName
+	dtp.Port = port
+
+	return dtp, nil
+}
+
+// UpdateDeviceTypePort replaces the description, friendly name and host/destination mirroring of
+// one of a type's ports
+func (s *Store) UpdateDeviceTypePort(ctx context.Context, typeName string, portName string, dtp structs.DeviceTypePort) (structs.DeviceTypePort, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	index, err := s.tables.deviceTypePortIndex(typeName, portName)
+	if err != nil {
+		return structs.DeviceTypePort{}, err
+	}
+
+	row := &s.tables.DeviceTypePorts[index]
+	row.Description = dtp.Description
+	row.FriendlyName = dtp.FriendlyName
+	row.HostDestinationMirror = dtp.HostDestintionMirror
+
+	port, _ := s.tables.port(row.PortID)
+
+	return structs.DeviceTypePort{
+		DeviceTypePortID:     row.ID,
+		DeviceTypeID:         row.DeviceTypeID,
+		DeviceTypeName:       typeName,
+		Port:                 port,
+		Description:          row.Description,
+		FriendlyName:         row.FriendlyName,
+		HostDestintionMirror: row.HostDestinationMirror,
+	}, nil
+}
+
+// DeleteDeviceTypePort removes one of a type's ports, refusing while devices of the type have it
+// configured
+func (s *Store) DeleteDeviceTypePort(ctx context.Context, typeName string, portName string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	index, err := s.tables.deviceTypePortIndex(typeName, portName)
+	if err != nil {
+		return err
+	}
+	row := s.tables.DeviceTypePorts[index]
+
+	devices := []string{}
+	seen := make(map[int]bool)
+	for _, pc := range s.tables.PortConfigurations {
+		if pc.PortID != row.PortID || seen[pc.HostDeviceID] {
+			continue
+		}
+
+		host, ok := s.tables.deviceRow(pc.HostDeviceID)
+		if !ok || host.TypeID != row.DeviceTypeID {
+			continue
+		}
+		seen[host.ID] = true
+
+		devices = append(devices, s.tables.fullName(host))
+	}
+
+	if len(devices) > 0 {
+		sort.Strings(devices)
+
+		return &accessors.Error{
+			Kind:    accessors.Conflict,
+			Err:     fmt.Errorf("%s devices still have %s configured; remove those port configurations first", typeName, portName),
+			Details: devices,
+		}
+	}
+
+	s.tables.DeviceTypePorts = append(s.tables.DeviceTypePorts[:index], s.tables.DeviceTypePorts[index+1:]...)
+
+	return nil
+}
+
+func (t *Tables) deviceTypePortIndex(typeName string, portName string) (int, error) {
+	deviceType, ok := t.deviceTypeByName(typeName)
+	if !ok {
+		return -1, accessors.Errorf(accessors.NotFound, "there is no device type called %s", typeName)
+	}
+
+	for i, row := range t.DeviceTypePorts {
+		if row.DeviceTypeID != deviceType.ID {
+			continue
+		}
+
+		if port, ok := t.port(row.PortID); ok && port.Name == portName {
+			return i, nil
+		}
+	}
+
+	return -1, accessors.Errorf(accessors.NotFound, "%s has no port called %s", typeName, portName)
+}
+
+func (t *Tables) portByName(name string) (structs.PortType, bool) {
+	for _, p := range t.Ports {
+		if p.Name == name {
+			return p, true
+		}
+	}
+
+	return structs.PortType{}, false
+}
@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// GetDeviceClasses dumps the DeviceClasses table
+func (s *Store) GetDeviceClasses(ctx context.Context) ([]structs.DeviceType, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	deviceClasses := []structs.DeviceType{}
+	deviceClasses = append(deviceClasses, s.tables.DeviceClasses...)
+
+	return deviceClasses, nil
+}
+
+// GetDeviceTypeByID returns an entry in DeviceClasses by ID
+func (s *Store) GetDeviceTypeByID(ctx context.Context, id int) (structs.DeviceType, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	dt, ok := s.tables.deviceClass(id)
+	if !ok {
+		return structs.DeviceType{}, sql.ErrNoRows
+	}
+
+	return dt, nil
+}
+
+// GetDeviceTypeByName returns an entry in DeviceClasses by name
+func (s *Store) GetDeviceTypeByName(ctx context.Context, name string) (structs.DeviceType, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	dt, ok := s.tables.deviceClassByName(name)
+	if !ok {
+		return structs.DeviceType{}, sql.ErrNoRows
+	}
+
+	return dt, nil
+}
+
+// AddDeviceType adds an entry to DeviceClasses
+func (s *Store) AddDeviceType(ctx context.Context, deviceType structs.DeviceType) (structs.DeviceType, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deviceType.ID = s.nextID("DeviceClasses", deviceType.ID)
+	s.tables.DeviceClasses = append(s.tables.DeviceClasses, deviceType)
+
+	return deviceType, nil
+}
+
+// GetDeviceTypes dumps the DeviceTypes table
+func (s *Store) GetDeviceTypes(ctx context.Context) ([]structs.DeviceClass, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	toReturn := []structs.DeviceClass{}
+	toReturn = append(toReturn, s.tables.DeviceTypes...)
+
+	return toReturn, nil
+}
+
+// GetDeviceClassByName returns an entry in DeviceTypes by name
+func (s *Store) GetDeviceClassByName(ctx context.Context, name string) (structs.DeviceClass, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	dc, ok := s.tables.deviceTypeByName(name)
+	if !ok {
+		return structs.DeviceClass{}, accessors.Errorf(accessors.NotFound, "No device types found")
+	}
+
+	return dc, nil
+}
+
+func (t *Tables) deviceClass(id int) (structs.DeviceType, bool) {
+	for _, dt := range t.DeviceClasses {
+		if dt.ID == id {
+			return dt, true
+		}
+	}
+
+	return structs.DeviceType{}, false
+}
+
+func (t *Tables) deviceClassByName(name string) (structs.DeviceType, bool) {
+	for _, dt := range t.DeviceClasses {
+		if dt.Name == name {
+			return dt, true
+		}
+	}
+
+	return structs.DeviceType{}, false
+}
+
+func (t *Tables) deviceType(id int) (structs.DeviceClass, bool) {
+	for _, dc := range t.DeviceTypes {
+		if dc.ID == id {
+			return dc, true
+		}
+	}
+
+	return structs.DeviceClass{}, false
+}
+
+func (t *Tables) deviceTypeByName(name string) (structs.DeviceClass, bool) {
+	for _, dc := range t.DeviceTypes {
+		if dc.Name == name {
+			return dc, true
+		}
+	}
+
+	return structs.DeviceClass{}, false
+}
@@ -0,0 +1,328 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// GetAllPorts dumps the Ports table
+func (s *Store) GetAllPorts(ctx context.Context) ([]structs.PortType, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	allPorts := []structs.PortType{}
+	allPorts = append(allPorts, s.tables.Ports...)
+
+	return allPorts, nil
+}
+
+// GetPortTypeByName returns an entry in Ports by name
+func (s *Store) GetPortTypeByName(ctx context.Context, name string) (structs.PortType, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, p := range s.tables.Ports {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+
+	return structs.PortType{}, sql.ErrNoRows
+}
+
+// AddPort adds an entry to the Ports table
+func (s *Store) AddPort(ctx context.Context, portToAdd structs.PortType) (structs.PortType, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	portToAdd.ID = s.nextID("Ports", portToAdd.ID)
+	s.tables.Ports = append(s.tables.Ports, portToAdd)
+
+	return portToAdd, nil
+}
+
+// GetPortsByDeviceTypeName returns the ports defined for an entry in DeviceTypes
+func (s *Store) GetPortsByDeviceTypeName(ctx context.Context, typeName string) ([]structs.DeviceTypePort, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	toReturn := []structs.DeviceTypePort{}
+
+	dt, ok := s.tables.deviceTypeByName(typeName)
+	if !ok {
+		return toReturn, nil
+	}
+
+	for _, dtp := range s.tables.DeviceTypePorts {
+		if dtp.DeviceTypeID != dt.ID {
+			continue
+		}
+
+		port, ok := s.tables.port(dtp.PortID)
+		if !ok {
+			continue
+		}
+
+		toReturn = append(toReturn, structs.DeviceTypePort{
+			DeviceTypePortID:     dtp.ID,
+			DeviceTypeID:         dt.ID,
+			DeviceTypeName:       dt.Name,
+			Port:                 port,
+			Description:          dtp.Description,
+			FriendlyName:         dtp.FriendlyName,
+			HostDestintionMirror: dtp.HostDestinationMirror,
+		})
+	}
+
+	return toReturn, nil
+}
+
+// GetPortConfiguration dumps the PortConfiguration table. The MySQL accessor ignores
+// building, room and device as well.
+func (s *Store) GetPortConfiguration(ctx context.Context, building string, room string, device string) ([]structs.PortConfiguration, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	portconfigurations := []structs.PortConfiguration{}
+	portconfigurations = append(portconfigurations, s.tables.PortConfigurations...)
+
+	return portconfigurations, nil
+}
+
+// GetRoomPortConfiguration returns every port configuration with its host, source or
+// destination in a room, sorted by host, port and ID
+func (s *Store) GetRoomPortConfiguration(ctx context.Context, buildingShortname string, roomName string) ([]structs.RoomPort, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	building, ok := s.tables.buildingByShortname(buildingShortname)
+	if !ok {
+		return []structs.RoomPort{}, accessors.Errorf(accessors.NotFound, "No rooms found with that name.")
+	}
+
+	room, ok := s.tables.roomByBuildingAndName(building.ID, roomName)
+	if !ok {
+		return []structs.RoomPort{}, accessors.Errorf(accessors.NotFound, "No rooms found with that name.")
+	}
+
+	return s.tables.roomPorts(func(source, destination, host Device) bool {
+		return source.RoomID == room.ID || destination.RoomID == room.ID || host.RoomID == room.ID
+	}), nil
+}
+
+// GetAllPortConfiguration returns every port configuration, as GetRoomPortConfiguration does for
+// one room
+func (s *Store) GetAllPortConfiguration(ctx context.Context) ([]structs.RoomPort, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.tables.roomPorts(func(source, destination, host Device) bool {
+		return true
+	}), nil
+}
+
+// roomPorts returns the port configurations whose devices match, sorted by host, port and ID. A
+// device that doesn't exist is passed as the zero Device.
+func (t *Tables) roomPorts(match func(source, destination, host Device) bool) []structs.RoomPort {
+	toReturn := []structs.RoomPort{}
+	for _, pc := range t.PortConfigurations {
+		source, hasSource := t.deviceRow(pc.SourceDeviceID)
+		destination, hasDestination := t.deviceRow(pc.DestinationDeviceID)
+		host, hasHost := t.deviceRow(pc.HostDeviceID)
+
+		if !match(source, destination, host) {
+			continue
+		}
+
+		roomPort := structs.RoomPort{ID: pc.ID}
+		if port, ok := t.port(pc.PortID); ok {
+			roomPort.Port = port.Name
+		}
+		if hasSource {
+			roomPort.Source, roomPort.SourceRoom = source.Name, t.roomFullName(source)
+		}
+		if hasDestination {
+			roomPort.Destination, roomPort.DestinationRoom = destination.Name, t.roomFullName(destination)
+		}
+		if hasHost {
+			roomPort.Host, roomPort.HostRoom = host.Name, t.roomFullName(host)
+			if deviceType, ok := t.deviceType(host.TypeID); ok {
+				roomPort.HostType = deviceType.Name
+			}
+		}
+
+		toReturn = append(toReturn, roomPort)
+	}
+
+	sort.Slice(toReturn, func(i, j int) bool {
+		a, b := toReturn[i], toReturn[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		return a.ID < b.ID
+	})
+
+	return toReturn
+}
+
+// GetPortsByHostID returns the port configurations hosted by a device
+func (s *Store) GetPortsByHostID(ctx context.Context, hostID int) ([]structs.PortConfiguration, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	portconfigurations := []structs.PortConfiguration{}
+	for _, pc := range s.tables.PortConfigurations {
+		if pc.HostDeviceID == hostID {
+			portconfigurations = append(portconfigurations, pc)
+		}
+	}
+
+	return portconfigurations, nil
+}
+
+// AddPortConfiguration adds a row to the PortConfiguration table
+func (s *Store) AddPortConfiguration(ctx context.Context, pc structs.PortConfiguration) (structs.PortConfiguration, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	pc.ID = s.nextID("PortConfiguration", pc.ID)
+	s.tables.PortConfigurations = append(s.tables.PortConfigurations, pc)
+
+	return pc, nil
+}
+
+// AddDevicePort wires one of a device's ports to a source and destination in the same room
+func (s *Store) AddDevicePort(ctx context.Context, buildingShortname string, roomName string, deviceName string, port structs.Port) (structs.Port, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	pc, err := s.tables.portConfiguration(buildingShortname, roomName, deviceName, port)
+	if err != nil {
+		return structs.Port{}, err
+	}
+
+	for _, row := range s.tables.PortConfigurations {
+		if row.HostDeviceID == pc.HostDeviceID && row.PortID == pc.PortID {
+			return structs.Port{}, accessors.Errorf(accessors.Conflict, "port %s on %s is already configured", port.Name, deviceName)
+		}
+	}
+
+	pc.ID = s.nextID("PortConfiguration", 0)
+	s.tables.PortConfigurations = append(s.tables.PortConfigurations, pc)
+
+	port.Host = deviceName
+	return port, nil
+}
+
+// UpdateDevicePort points an already configured port at a new source and destination
+func (s *Store) UpdateDevicePort(ctx context.Context, buildingShortname string, roomName string, deviceName string, portName string, port structs.Port) (structs.Port, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	port.Name = portName
+
+	pc, err := s.tables.portConfiguration(buildingShortname, roomName, deviceName, port)
+	if err != nil {
+		return structs.Port{}, err
+	}
+
+	updated := false
+	for i, row := range s.tables.PortConfigurations {
+		if row.HostDeviceID == pc.HostDeviceID && row.PortID == pc.PortID {
+			s.tables.PortConfigurations[i].SourceDeviceID = pc.SourceDeviceID
+			s.tables.PortConfigurations[i].DestinationDeviceID = pc.DestinationDeviceID
+			updated = true
+		}
+	}
+
+	if !updated {
+		return structs.Port{}, accessors.Errorf(accessors.NotFound, "port %s on %s isn't configured", portName, deviceName)
+	}
+
+	port.Host = deviceName
+	return port, nil
+}
+
+// DeleteDevicePort removes the configuration of one of a device's ports
+func (s *Store) DeleteDevicePort(ctx context.Context, buildingShortname string, roomName string, deviceName string, portName string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	host, ok := s.tables.deviceByName(buildingShortname, roomName, deviceName)
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	deleted := false
+	portConfigurations := s.tables.PortConfigurations[:0]
+	for _, row := range s.tables.PortConfigurations {
+		if row.HostDeviceID == host.ID {
+			if p, ok := s.tables.port(row.PortID); ok && p.Name == portName {
+				deleted = true
+				continue
+			}
+		}
+		portConfigurations = append(portConfigurations, row)
+	}
+	s.tables.PortConfigurations = portConfigurations
+
+	if !deleted {
+		return accessors.Errorf(accessors.NotFound, "port %s on %s isn't configured", portName, deviceName)
+	}
+
+	return nil
+}
+
+// portConfiguration is the memory version of the SQL accessor's: it resolves the names in port
+// to a PortConfiguration row and checks them
+func (t *Tables) portConfiguration(buildingShortname string, roomName string, deviceName string, port structs.Port) (structs.PortConfiguration, error) {
+	host, ok := t.deviceByName(buildingShortname, roomName, deviceName)
+	if !ok {
+		return structs.PortConfiguration{}, sql.ErrNoRows
+	}
+
+	source, ok := t.deviceByName(buildingShortname, roomName, port.Source)
+	if !ok {
+		return structs.PortConfiguration{}, accessors.Errorf(accessors.Validation, "source device %q isn't in %s-%s", port.Source, buildingShortname, roomName)
+	}
+
+	destination, ok := t.deviceByName(buildingShortname, roomName, port.Destination)
+	if !ok {
+		return structs.PortConfiguration{}, accessors.Errorf(accessors.Validation, "destination device %q isn't in %s-%s", port.Destination, buildingShortname, roomName)
+	}
+
+	for _, dtp := range t.DeviceTypePorts {
+		if dtp.DeviceTypeID != host.TypeID {
+			continue
+		}
+
+		p, ok := t.port(dtp.PortID)
+		if ok && p.Name == port.Name {
+			return structs.PortConfiguration{
+				DestinationDeviceID: destination.ID,
+				PortID:              p.ID,
+				SourceDeviceID:      source.ID,
+				HostDeviceID:        host.ID,
+			}, nil
+		}
+	}
+
+	return structs.PortConfiguration{}, accessors.Errorf(accessors.Validation, "%s's type has no port called %q", deviceName, port.Name)
+}
+
+func (t *Tables) port(id int) (structs.PortType, bool) {
+	for _, p := range t.Ports {
+		if p.ID == id {
+			return p, true
+		}
+	}
+
+	return structs.PortType{}, false
+}
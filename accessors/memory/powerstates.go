@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// GetPowerStates dumps the PowerStates table
+func (s *Store) GetPowerStates(ctx context.Context) ([]structs.PowerState, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	powerstates := []structs.PowerState{}
+	powerstates = append(powerstates, s.tables.PowerStates...)
+
+	return powerstates, nil
+}
+
+// GetPowerStateByID returns a power state by ID
+func (s *Store) GetPowerStateByID(ctx context.Context, id int) (structs.PowerState, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, ps := range s.tables.PowerStates {
+		if ps.ID == id {
+			return ps, nil
+		}
+	}
+
+	return structs.PowerState{}, sql.ErrNoRows
+}
+
+// GetPowerStateByName returns a power state by name
+func (s *Store) GetPowerStateByName(ctx context.Context, name string) (structs.PowerState, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	ps, ok := s.tables.powerStateByName(name)
+	if !ok {
+		return structs.PowerState{}, sql.ErrNoRows
+	}
+
+	return ps, nil
+}
+
+// AddPowerState adds a power state
+func (s *Store) AddPowerState(ctx context.Context, powerstate structs.PowerState) (structs.PowerState, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	powerstate.ID = s.nextID("PowerStates", powerstate.ID)
+	s.tables.PowerStates = append(s.tables.PowerStates, powerstate)
+
+	return powerstate, nil
+}
+
+// GetDevicePowerStates dumps the DevicePowerStates table
+func (s *Store) GetDevicePowerStates(ctx context.Context) ([]structs.DevicePowerState, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	devicepowerstates := []structs.DevicePowerState{}
+	devicepowerstates = append(devicepowerstates, s.tables.DevicePowerStates...)
+
+	return devicepowerstates, nil
+}
+
+// AddDevicePowerState allows a power state on a device
+func (s *Store) AddDevicePowerState(ctx context.Context, dps structs.DevicePowerState) (structs.DevicePowerState, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	dps.ID = s.nextID("DevicePowerStates", dps.ID)
+	s.tables.DevicePowerStates = append(s.tables.DevicePowerStates, dps)
+
+	return dps, nil
+}
+
+func (t *Tables) powerStateByName(name string) (structs.PowerState, bool) {
+	for _, ps := range t.PowerStates {
+		if ps.Name == name {
+			return ps, true
+		}
+	}
+
+	return structs.PowerState{}, false
+}
+
+func (t *Tables) powerStates(deviceID int) []string {
+	toReturn := []string{}
+
+	for _, dps := range t.DevicePowerStates {
+		if dps.DeviceID != deviceID {
+			continue
+		}
+
+		for _, ps := range t.PowerStates {
+			if ps.ID == dps.PowerStateID {
+				toReturn = append(toReturn, ps.Name)
+			}
+		}
+	}
+
+	return toReturn
+}
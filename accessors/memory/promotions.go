@@ -0,0 +1,216 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// GetRoomPromotions returns the times a room has been moved between designations, or asked to
+// be, oldest first
+func (s *Store) GetRoomPromotions(ctx context.Context, buildingShortname string, roomName string) ([]structs.Promotion, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	room, ok := s.tables.roomByShortnameAndName(buildingShortname, roomName)
+	if !ok {
+		return []structs.Promotion{}, accessors.Errorf(accessors.NotFound, "No rooms found with that name.")
+	}
+
+	promotions := []structs.Promotion{}
+	for _, row := range s.tables.RoomPromotions {
+		if row.RoomID == room.ID {
+			promotions = append(promotions, row.promotion(buildingShortname, room.Name))
+		}
+	}
+
+	return promotions, nil
+}
+
+// PromoteRoom moves a room to the designation promotion.To and records who did it. It doesn't
+// check that the room is ready for its new designation, or that the move needs no approval;
+// that's up to the caller (see package validation), in the same transaction.
+func (s *Store) PromoteRoom(ctx context.Context, buildingShortname string, roomName string, promotion structs.Promotion) (structs.Promotion, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	room, ok := s.tables.roomByShortnameAndName(buildingShortname, roomName)
+	if !ok {
+		return structs.Promotion{}, accessors.Errorf(accessors.NotFound, "No rooms found with that name.")
+	}
+
+	err := accessors.CheckPromotion(buildingShortname, room.Name, room.RoomDesignation, promotion.To)
+	if err != nil {
+		return structs.Promotion{}, err
+	}
+
+	row := RoomPromotion{
+		ID:         s.nextID("RoomPromotions", 0),
+		RoomID:     room.ID,
+		From:       room.RoomDesignation,
+		To:         promotion.To,
+		Status:     structs.PromotionApplied,
+		PromotedBy: promotion.PromotedBy,
+		PromotedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	s.tables.setDesignation(room.ID, promotion.To)
+	s.tables.RoomPromotions = append(s.tables.RoomPromotions, row)
+
+	return row.promotion(buildingShortname, room.Name), nil
+}
+
+// RequestPromotion records a promotion that someone else has to approve before it moves the
+// room. A room can only have one pending at a time.
+func (s *Store) RequestPromotion(ctx context.Context, buildingShortname string, roomName string, promotion structs.Promotion) (structs.Promotion, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	room, ok := s.tables.roomByShortnameAndName(buildingShortname, roomName)
+	if !ok {
+		return structs.Promotion{}, accessors.Errorf(accessors.NotFound, "No rooms found with that name.")
+	}
+
+	err := accessors.CheckPromotion(buildingShortname, room.Name, room.RoomDesignation, promotion.To)
+	if err != nil {
+		return structs.Promotion{}, err
+	}
+
+	for _, row := range s.tables.RoomPromotions {
+		if row.RoomID == room.ID && row.status() == structs.PromotionPending {
+			return structs.Promotion{}, accessors.Errorf(accessors.Conflict, "%s-%s already has a pending promotion, %d; approve or withdraw it first", buildingShortname, room.Name, row.ID)
+		}
+	}
+
+	row := RoomPromotion{
+		ID:         s.nextID("RoomPromotions", 0),
+		RoomID:     room.ID,
+		From:       room.RoomDesignation,
+		To:         promotion.To,
+		Status:     structs.PromotionPending,
+		PromotedBy: promotion.PromotedBy,
+		PromotedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	s.tables.RoomPromotions = append(s.tables.RoomPromotions, row)
+
+	return row.promotion(buildingShortname, room.Name), nil
+}
+
+// ApprovePromotion approves a pending promotion on behalf of approvedBy, who can't be the person
+// who asked for it, and moves the room. Like PromoteRoom, checking the room is the caller's job.
+func (s *Store) ApprovePromotion(ctx context.Context, buildingShortname string, roomName string, id int, approvedBy string) (structs.Promotion, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	room, index, err := s.tables.roomPromotion(buildingShortname, roomName, id)
+	if err != nil {
+		return structs.Promotion{}, err
+	}
+
+	row := s.tables.RoomPromotions[index]
+	err = accessors.CheckApproval(row.promotion(buildingShortname, room.Name), approvedBy, room.RoomDesignation)
+	if err != nil {
+		return structs.Promotion{}, err
+	}
+
+	row.Status = structs.PromotionApplied
+	row.ApprovedBy = approvedBy
+	row.ApprovedAt = time.Now().UTC().Format(time.RFC3339)
+
+	s.tables.setDesignation(room.ID, row.To)
+	s.tables.RoomPromotions[index] = row
+
+	return row.promotion(buildingShortname, room.Name), nil
+}
+
+// WithdrawPromotion drops a pending promotion, leaving the room where it is
+func (s *Store) WithdrawPromotion(ctx context.Context, buildingShortname string, roomName string, id int) (structs.Promotion, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	room, index, err := s.tables.roomPromotion(buildingShortname, roomName, id)
+	if err != nil {
+		return structs.Promotion{}, err
+	}
+
+	row := s.tables.RoomPromotions[index]
+	if row.status() != structs.PromotionPending {
+		return structs.Promotion{}, accessors.Errorf(accessors.Conflict, "promotion %d is %s, not pending", row.ID, row.status())
+	}
+
+	row.Status = structs.PromotionWithdrawn
+	s.tables.RoomPromotions[index] = row
+
+	return row.promotion(buildingShortname, room.Name), nil
+}
+
+func (row RoomPromotion) promotion(buildingShortname string, roomName string) structs.Promotion {
+	return structs.Promotion{
+		ID:         row.ID,
+		Building:   buildingShortname,
+		Room:       roomName,
+		From:       row.From,
+		To:         row.To,
+		Status:     row.status(),
+		PromotedBy: row.PromotedBy,
+		PromotedAt: row.PromotedAt,
+		ApprovedBy: row.ApprovedBy,
+		ApprovedAt: row.ApprovedAt,
+	}
+}
+
+// status is the promotion's status. Fixtures written before promotions could be pending leave
+// it out; those promotions were all applied.
+func (row RoomPromotion) status() string {
+	if len(row.Status) == 0 {
+		return structs.PromotionApplied
+	}
+
+	return row.Status
+}
+
+func (t *Tables) roomByShortnameAndName(buildingShortname string, roomName string) (Room, bool) {
+	building, ok := t.buildingByShortname(buildingShortname)
+	if !ok {
+		return Room{}, false
+	}
+
+	return t.roomByBuildingAndName(building.ID, roomName)
+}
+
+// roomPromotion returns a room and the index in RoomPromotions of one of its promotions
+func (t *Tables) roomPromotion(buildingShortname string, roomName string, id int) (Room, int, error) {
+	room, ok := t.roomByShortnameAndName(buildingShortname, roomName)
+	if !ok {
+		return Room{}, -1, accessors.Errorf(accessors.NotFound, "No rooms found with that name.")
+	}
+
+	for i, row := range t.RoomPromotions {
+		if row.ID == id && row.RoomID == room.ID {
+			return room, i, nil
+		}
+	}
+
+	return Room{}, -1, accessors.Errorf(accessors.NotFound, "%s-%s has no promotion %d", buildingShortname, room.Name, id)
+}
+
+func (t *Tables) setDesignation(roomID int, designation string) {
+	for i := range t.Rooms {
+		if t.Rooms[i].ID == roomID {
+			t.Rooms[i].RoomDesignation = designation
+		}
+	}
+}
+
+// deletePromotions deletes the promotion history of the rooms gone says are going away
+func (t *Tables) deletePromotions(gone func(roomID int) bool) {
+	promotions := t.RoomPromotions[:0]
+	for _, row := range t.RoomPromotions {
+		if !gone(row.RoomID) {
+			promotions = append(promotions, row)
+		}
+	}
+	t.RoomPromotions = promotions
+}
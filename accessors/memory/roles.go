@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// GetDeviceRoleDefs dumps the DeviceRoleDefinition table
+func (s *Store) GetDeviceRoleDefs(ctx context.Context) ([]structs.DeviceRoleDef, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	deviceroledefs := []structs.DeviceRoleDef{}
+	deviceroledefs = append(deviceroledefs, s.tables.DeviceRoleDefinitions...)
+
+	return deviceroledefs, nil
+}
+
+// GetDeviceRoleDefByID returns a role definition by ID
+func (s *Store) GetDeviceRoleDefByID(ctx context.Context, id int) (structs.DeviceRoleDef, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, drd := range s.tables.DeviceRoleDefinitions {
+		if drd.ID == id {
+			return drd, nil
+		}
+	}
+
+	return structs.DeviceRoleDef{}, sql.ErrNoRows
+}
+
+// GetDeviceRoleDefByName returns a role definition by name
+func (s *Store) GetDeviceRoleDefByName(ctx context.Context, name string) (structs.DeviceRoleDef, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	drd, ok := s.tables.roleDefinitionByName(name)
+	if !ok {
+		return structs.DeviceRoleDef{}, sql.ErrNoRows
+	}
+
+	return drd, nil
+}
+
+// AddDeviceRoleDef adds a role definition
+func (s *Store) AddDeviceRoleDef(ctx context.Context, deviceroledef structs.DeviceRoleDef) (structs.DeviceRoleDef, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deviceroledef.ID = s.nextID("DeviceRoleDefinition", deviceroledef.ID)
+	s.tables.DeviceRoleDefinitions = append(s.tables.DeviceRoleDefinitions, deviceroledef)
+
+	return deviceroledef, nil
+}
+
+// GetDeviceRoles dumps the DeviceRole table
+func (s *Store) GetDeviceRoles(ctx context.Context) ([]structs.DeviceRole, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	deviceroles := []structs.DeviceRole{}
+	deviceroles = append(deviceroles, s.tables.DeviceRoles...)
+
+	return deviceroles, nil
+}
+
+// AddDeviceRole gives a device a role
+func (s *Store) AddDeviceRole(ctx context.Context, dr structs.DeviceRole) (structs.DeviceRole, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	dr.ID = s.nextID("DeviceRole", dr.ID)
+	s.tables.DeviceRoles = append(s.tables.DeviceRoles, dr)
+
+	return dr, nil
+}
+
+func (t *Tables) roleDefinitionByName(name string) (structs.DeviceRoleDef, bool) {
+	for _, drd := range t.DeviceRoleDefinitions {
+		if drd.Name == name {
+			return drd, true
+		}
+	}
+
+	return structs.DeviceRoleDef{}, false
+}
+
+func (t *Tables) roles(deviceID int) []string {
+	toReturn := []string{}
+
+	for _, dr := range t.DeviceRoles {
+		if dr.DeviceID != deviceID {
+			continue
+		}
+
+		for _, drd := range t.DeviceRoleDefinitions {
+			if drd.ID == dr.DeviceRoleDefinitionID {
+				toReturn = append(toReturn, drd.Name)
+			}
+		}
+	}
+
+	return toReturn
+}
+
+func (t *Tables) hasRole(deviceID int, roleName string) bool {
+	for _, role := range t.roles(deviceID) {
+		if like(role, roleName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *Tables) hasRoleID(deviceID int, roleDefinitionID int) bool {
+	for _, dr := range t.DeviceRoles {
+		if dr.DeviceID == deviceID && dr.DeviceRoleDefinitionID == roleDefinitionID {
+			return true
+		}
+	}
+
+	return false
+}
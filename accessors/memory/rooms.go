@@ -0,0 +1,302 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// GetAllRooms returns a list of rooms
+func (s *Store) GetAllRooms(ctx context.Context) ([]structs.Room, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	allRooms := []structs.Room{}
+	for _, row := range s.tables.Rooms {
+		allRooms = append(allRooms, row.room())
+	}
+
+	return allRooms, nil
+}
+
+// GetAllRoomDesignations returns each distinct room designation
+func (s *Store) GetAllRoomDesignations(ctx context.Context) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	toReturn := []string{}
+	seen := make(map[string]bool)
+
+	for _, row := range s.tables.Rooms {
+		if seen[row.RoomDesignation] {
+			continue
+		}
+
+		seen[row.RoomDesignation] = true
+		toReturn = append(toReturn, row.RoomDesignation)
+	}
+
+	return toReturn, nil
+}
+
+// GetRoomByID returns a room by ID
+func (s *Store) GetRoomByID(ctx context.Context, id int) (structs.Room, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, row := range s.tables.Rooms {
+		if row.ID == id {
+			return row.room(), nil
+		}
+	}
+
+	return structs.Room{}, sql.ErrNoRows
+}
+
+// GetRoomsByBuilding returns the rooms in a building
+func (s *Store) GetRoomsByBuilding(ctx context.Context, building string) ([]structs.Room, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	allRooms := []structs.Room{}
+
+	b, ok := s.tables.buildingByShortname(building)
+	if !ok {
+		return allRooms, nil
+	}
+
+	for _, row := range s.tables.Rooms {
+		if row.BuildingID == b.ID {
+			allRooms = append(allRooms, row.room())
+		}
+	}
+
+	return allRooms, nil
+}
+
+// GetRoomByBuildingAndName returns a room, with its devices and configuration, by building shortname and room name
+func (s *Store) GetRoomByBuildingAndName(ctx context.Context, buildingShortname string, name string) (structs.Room, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	building, ok := s.tables.buildingByShortname(buildingShortname)
+	if !ok {
+		return structs.Room{}, sql.ErrNoRows
+	}
+
+	row, ok := s.tables.roomByBuildingAndName(building.ID, name)
+	if !ok {
+		return structs.Room{}, accessors.Errorf(accessors.NotFound, "No rooms found with that name.")
+	}
+
+	room := row.room()
+	room.Building = building
+	room.Devices = s.devicesWhere(func(d Device) bool {
+		return d.RoomID == row.ID
+	})
+
+	config, ok := s.tables.configuration(room.ConfigurationID)
+	if !ok {
+		return room, sql.ErrNoRows
+	}
+	room.Configuration = config
+
+	return room, nil
+}
+
+// AddRoom adds a room to the building with the given shortname. It won't add one straight to
+// production; see PromoteRoom.
+func (s *Store) AddRoom(ctx context.Context, buildingShortName string, roomToAdd structs.Room) (structs.Room, error) {
+	var err error
+	roomToAdd.RoomDesignation, err = accessors.CheckDesignation(buildingShortName, roomToAdd.Name, "", roomToAdd.RoomDesignation)
+	if err != nil {
+		return structs.Room{}, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	building, ok := s.tables.buildingByShortname(buildingShortName)
+	if !ok {
+		return structs.Room{}, sql.ErrNoRows
+	}
+
+	if _, ok := s.tables.roomByBuildingAndName(building.ID, roomToAdd.Name); ok {
+		return structs.Room{}, accessors.Errorf(accessors.Conflict, "there is already a room called %s in %s", roomToAdd.Name, buildingShortName)
+	}
+
+	row := Room{
+		ID:              s.nextID("Rooms", 0),
+		Name:            roomToAdd.Name,
+		BuildingID:      building.ID,
+		Description:     roomToAdd.Description,
+		ConfigurationID: roomToAdd.ConfigurationID,
+		RoomDesignation: roomToAdd.RoomDesignation,
+	}
+	s.tables.Rooms = append(s.tables.Rooms, row)
+
+	roomToAdd.ID = row.ID
+	roomToAdd.Building = building
+
+	return roomToAdd, nil
+}
+
+// UpdateRoom changes a room's description, configuration and designation, and renames it if
+// room has a new name. An empty name or designation, or a configuration ID of 0, keeps the
+// current value; the description is always replaced. It won't move a room to production; see
+// PromoteRoom.
+func (s *Store) UpdateRoom(ctx context.Context, buildingShortname string, name string, room structs.Room) (structs.Room, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	building, ok := s.tables.buildingByShortname(buildingShortname)
+	if !ok {
+		return structs.Room{}, sql.ErrNoRows
+	}
+
+	current, ok := s.tables.roomByBuildingAndName(building.ID, name)
+	if !ok {
+		return structs.Room{}, sql.ErrNoRows
+	}
+
+	if len(room.Name) == 0 {
+		room.Name = current.Name
+	}
+	if len(room.RoomDesignation) == 0 {
+		room.RoomDesignation = current.RoomDesignation
+	}
+	if room.ConfigurationID == 0 {
+		room.ConfigurationID = current.ConfigurationID
+	}
+
+	var err error
+	room.RoomDesignation, err = accessors.CheckDesignation(buildingShortname, name, current.RoomDesignation, room.RoomDesignation)
+	if err != nil {
+		return structs.Room{}, err
+	}
+
+	if room.Name != current.Name {
+		if _, ok := s.tables.roomByBuildingAndName(building.ID, room.Name); ok {
+			return structs.Room{}, accessors.Errorf(accessors.Conflict, "there is already a room called %s in %s", room.Name, buildingShortname)
+		}
+	}
+
+	if room.ConfigurationID != current.ConfigurationID {
+		if _, ok := s.tables.configuration(room.ConfigurationID); !ok {
+			return structs.Room{}, accessors.Errorf(accessors.Validation, "room configuration %d does not exist", room.ConfigurationID)
+		}
+	}
+
+	current.Name = room.Name
+	current.Description = room.Description
+	current.ConfigurationID = room.ConfigurationID
+	current.RoomDesignation = room.RoomDesignation
+
+	for i := range s.tables.Rooms {
+		if s.tables.Rooms[i].ID == current.ID {
+			s.tables.Rooms[i] = current
+		}
+	}
+
+	toReturn := current.room()
+	toReturn.Building = building
+	toReturn.Devices = s.devicesWhere(func(d Device) bool {
+		return d.RoomID == current.ID
+	})
+	toReturn.Configuration, _ = s.tables.configuration(toReturn.ConfigurationID)
+
+	return toReturn, nil
+}
+
+// DeleteRoom deletes a room, refusing while devices are in it unless cascade is set
+func (s *Store) DeleteRoom(ctx context.Context, buildingShortname string, name string, cascade bool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	building, ok := s.tables.buildingByShortname(buildingShortname)
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	room, ok := s.tables.roomByBuildingAndName(building.ID, name)
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	if !cascade {
+		devices := []string{}
+		for _, row := range s.tables.Devices {
+			if row.RoomID == room.ID {
+				devices = append(devices, row.Name)
+			}
+		}
+		sort.Strings(devices)
+
+		if len(devices) > 0 {
+			blockers := []string{}
+			for _, device := range devices {
+				blockers = append(blockers, fmt.Sprintf("device %s-%s-%s", buildingShortname, name, device))
+			}
+
+			return &accessors.Error{
+				Kind:    accessors.Conflict,
+				Err:     fmt.Errorf("room %s-%s still has devices; delete them first or pass cascade=true", buildingShortname, name),
+				Details: blockers,
+			}
+		}
+	}
+
+	s.tables.deleteDevices(func(d Device) bool {
+		return d.RoomID == room.ID
+	})
+
+	s.tables.deletePromotions(func(roomID int) bool {
+		return roomID == room.ID
+	})
+
+	rooms := s.tables.Rooms[:0]
+	for _, row := range s.tables.Rooms {
+		if row.ID != room.ID {
+			rooms = append(rooms, row)
+		}
+	}
+	s.tables.Rooms = rooms
+
+	return nil
+}
+
+// room returns the row the way ExtractRoomData does; only the building ID is filled in
+func (r Room) room() structs.Room {
+	return structs.Room{
+		ID:              r.ID,
+		Name:            r.Name,
+		Building:        structs.Building{ID: r.BuildingID},
+		Description:     r.Description,
+		ConfigurationID: r.ConfigurationID,
+		RoomDesignation: r.RoomDesignation,
+	}
+}
+
+func (t *Tables) roomRow(id int) (Room, bool) {
+	for _, row := range t.Rooms {
+		if row.ID == id {
+			return row, true
+		}
+	}
+
+	return Room{}, false
+}
+
+func (t *Tables) roomByBuildingAndName(buildingID int, name string) (Room, bool) {
+	for _, row := range t.Rooms {
+		if row.BuildingID == buildingID && row.Name == name {
+			return row, true
+		}
+	}
+
+	return Room{}, false
+}
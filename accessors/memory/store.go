@@ -0,0 +1,352 @@
+/*
+Package memory is an in-memory implementation of accessors.Store. It keeps the
+same tables the configuration database has and answers the same questions the
+MySQL accessors do, which makes it handy for handler tests and for running the
+microservice on a laptop without a database.
+
+A store can be seeded from a JSON fixture whose shape is the Tables struct.
+*/
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// Room is a row in the Rooms table
+type Room struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	BuildingID      int    `json:"buildingID"`
+	Description     string `json:"description"`
+	ConfigurationID int    `json:"configurationID"`
+	RoomDesignation string `json:"roomDesignation"`
+}
+
+// Device is a row in the Devices table. ClassID points at DeviceClasses and TypeID at DeviceTypes.
+type Device struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Address     string `json:"address"`
+	Input       bool   `json:"input"`
+	Output      bool   `json:"output"`
+	BuildingID  int    `json:"buildingID"`
+	RoomID      int    `json:"roomID"`
+	ClassID     int    `json:"classID"`
+	TypeID      int    `json:"typeID"`
+	DisplayName string `json:"displayName"`
+}
+
+// DeviceTypePort is a row in the DeviceTypePorts table
+type DeviceTypePort struct {
+	ID                    int    `json:"id"`
+	DeviceTypeID          int    `json:"deviceTypeID"`
+	PortID                int    `json:"portID"`
+	Description           string `json:"description"`
+	FriendlyName          string `json:"friendlyName"`
+	HostDestinationMirror bool   `json:"hostDestinationMirror"`
+}
+
+// DeviceTypeCommand is a row in the DeviceTypeCommandMapping table
+type DeviceTypeCommand struct {
+	ID             int `json:"id"`
+	DeviceTypeID   int `json:"deviceTypeID"`
+	CommandID      int `json:"commandID"`
+	MicroserviceID int `json:"microserviceID"`
+	EndpointID     int `json:"endpointID"`
+}
+
+// ConfigurationMapping is a row in the RoomConfigurationMapping table
+type ConfigurationMapping struct {
+	ID              int    `json:"id"`
+	ConfigurationID int    `json:"configurationID"`
+	EvaluatorKey    string `json:"evaluatorKey"`
+	Priority        int    `json:"priority"`
+}
+
+// AudioDevice is a row in the AudioDevices table
+type AudioDevice struct {
+	ID       int  `json:"id"`
+	DeviceID int  `json:"deviceID"`
+	Muted    bool `json:"muted"`
+	Volume   int  `json:"volume"`
+}
+
+// DeviceAttribute is a row in the DeviceAttributes table. Value is the text the SQL backend
+// stores, so a fixture reads the same way either backend would.
+type DeviceAttribute struct {
+	ID       int    `json:"id"`
+	DeviceID int    `json:"deviceID"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+}
+
+// DeviceTypeAttribute is a row in the DeviceTypeAttributes table: one attribute in a device
+// type's schema
+type DeviceTypeAttribute struct {
+	ID           int `json:"id"`
+	DeviceTypeID int `json:"deviceTypeID"`
+	structs.DeviceTypeAttribute
+}
+
+// RoomPromotion is a row in the RoomPromotions table: a room moving from one designation to another
+type RoomPromotion struct {
+	ID         int    `json:"id"`
+	RoomID     int    `json:"roomID"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Status     string `json:"status"`
+	PromotedBy string `json:"promotedBy"`
+	PromotedAt string `json:"promotedAt"`
+	ApprovedBy string `json:"approvedBy,omitempty"`
+	ApprovedAt string `json:"approvedAt,omitempty"`
+}
+
+// Tables holds every table the store knows about. It is also the format of a fixture file.
+type Tables struct {
+	Buildings             []structs.Building          `json:"buildings"`
+	Rooms                 []Room                      `json:"rooms"`
+	Devices               []Device                    `json:"devices"`
+	DeviceClasses         []structs.DeviceType        `json:"deviceClasses"`
+	DeviceTypes           []structs.DeviceClass       `json:"deviceTypes"`
+	DeviceTypePorts       []DeviceTypePort            `json:"deviceTypePorts"`
+	DeviceTypeCommands    []DeviceTypeCommand         `json:"deviceTypeCommands"`
+	DeviceCommands        []structs.DeviceCommand     `json:"deviceCommands"`
+	Commands              []structs.RawCommand        `json:"commands"`
+	Endpoints             []structs.Endpoint          `json:"endpoints"`
+	Microservices         []structs.Microservice      `json:"microservices"`
+	Ports                 []structs.PortType          `json:"ports"`
+	PortConfigurations    []structs.PortConfiguration `json:"portConfigurations"`
+	PowerStates           []structs.PowerState        `json:"powerStates"`
+	DevicePowerStates     []structs.DevicePowerState  `json:"devicePowerStates"`
+	DeviceRoleDefinitions []structs.DeviceRoleDef     `json:"deviceRoleDefinitions"`
+	DeviceRoles           []structs.DeviceRole        `json:"deviceRoles"`
+	AudioDevices          []AudioDevice               `json:"audioDevices"`
+	DeviceAttributes      []DeviceAttribute           `json:"deviceAttributes"`
+	DeviceTypeAttributes  []DeviceTypeAttribute       `json:"deviceTypeAttributes"`
+	Configurations        []structs.RoomConfiguration `json:"roomConfigurations"`
+	ConfigurationMappings []ConfigurationMapping      `json:"roomConfigurationMappings"`
+	RoomPromotions        []RoomPromotion             `json:"roomPromotions"`
+}
+
+// Store is an accessors.Store backed by Tables held in memory. Nothing it does waits on I/O, so
+// it ignores the contexts it's given.
+type Store struct {
+	*state
+	expand accessors.Expand
+}
+
+// state is what a store shares with the copies WithExpansion hands out
+type state struct {
+	mutex  sync.RWMutex
+	tables Tables
+	ids    map[string]int
+}
+
+var _ accessors.Store = (*Store)(nil)
+
+// New returns an empty store
+func New() *Store {
+	return &Store{state: &state{ids: make(map[string]int)}, expand: accessors.ExpandAll}
+}
+
+// NewFromFixture returns a store seeded with the fixture at path
+func NewFromFixture(path string) (*Store, error) {
+	store := New()
+
+	err := store.LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// LoadFile replaces the contents of the store with the JSON fixture at path
+func (s *Store) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return s.Load(file)
+}
+
+// Load replaces the contents of the store with the JSON fixture read from r
+func (s *Store) Load(r io.Reader) error {
+	var tables Tables
+
+	err := json.NewDecoder(r).Decode(&tables)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tables = tables
+	s.ids = tables.highestIDs()
+
+	return nil
+}
+
+// nextID hands out auto increment values. If the caller asked for a specific ID we
+// honor it, the same way an explicit value in an INSERT would be.
+func (s *Store) nextID(table string, requested int) int {
+	if requested != 0 {
+		if requested > s.ids[table] {
+			s.ids[table] = requested
+		}
+		return requested
+	}
+
+	s.ids[table]++
+	return s.ids[table]
+}
+
+func (t *Tables) highestIDs() map[string]int {
+	ids := make(map[string]int)
+	bump := func(table string, id int) {
+		if id > ids[table] {
+			ids[table] = id
+		}
+	}
+
+	for _, row := range t.Buildings {
+		bump("Buildings", row.ID)
+	}
+	for _, row := range t.Rooms {
+		bump("Rooms", row.ID)
+	}
+	for _, row := range t.Devices {
+		bump("Devices", row.ID)
+	}
+	for _, row := range t.DeviceClasses {
+		bump("DeviceClasses", row.ID)
+	}
+	for _, row := range t.DeviceTypes {
+		bump("DeviceTypes", row.ID)
+	}
+	for _, row := range t.DeviceTypePorts {
+		bump("DeviceTypePorts", row.ID)
+	}
+	for _, row := range t.DeviceTypeCommands {
+		bump("DeviceTypeCommandMapping", row.ID)
+	}
+	for _, row := range t.DeviceCommands {
+		bump("DeviceCommands", row.ID)
+	}
+	for _, row := range t.Commands {
+		bump("Commands", row.ID)
+	}
+	for _, row := range t.Endpoints {
+		bump("Endpoints", row.ID)
+	}
+	for _, row := range t.Microservices {
+		bump("Microservices", row.ID)
+	}
+	for _, row := range t.Ports {
+		bump("Ports", row.ID)
+	}
+	for _, row := range t.PortConfigurations {
+		bump("PortConfiguration", row.ID)
+	}
+	for _, row := range t.PowerStates {
+		bump("PowerStates", row.ID)
+	}
+	for _, row := range t.DevicePowerStates {
+		bump("DevicePowerStates", row.ID)
+	}
+	for _, row := range t.DeviceRoleDefinitions {
+		bump("DeviceRoleDefinition", row.ID)
+	}
+	for _, row := range t.DeviceRoles {
+		bump("DeviceRole", row.ID)
+	}
+	for _, row := range t.AudioDevices {
+		bump("AudioDevices", row.ID)
+	}
+	for _, row := range t.DeviceAttributes {
+		bump("DeviceAttributes", row.ID)
+	}
+	for _, row := range t.DeviceTypeAttributes {
+		bump("DeviceTypeAttributes", row.ID)
+	}
+	for _, row := range t.Configurations {
+		bump("RoomConfiguration", row.ID)
+	}
+	for _, row := range t.ConfigurationMappings {
+		bump("RoomConfigurationMapping", row.ID)
+	}
+	for _, row := range t.RoomPromotions {
+		bump("RoomPromotions", row.ID)
+	}
+
+	return ids
+}
+
+// Transaction runs fn against a copy of the tables and swaps the copy in if fn returns nil.
+// Other writers wait until it's done. Use the Store fn is given; calling back into s from
+// inside fn deadlocks.
+func (s *Store) Transaction(ctx context.Context, fn func(accessors.Store) error) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	tx := &Store{state: &state{tables: s.tables.clone(), ids: make(map[string]int)}, expand: s.expand}
+	for table, id := range s.ids {
+		tx.ids[table] = id
+	}
+
+	err := fn(tx)
+	if err != nil {
+		return err
+	}
+
+	s.tables = tx.tables
+	s.ids = tx.ids
+
+	return nil
+}
+
+// WithExpansion returns a view of the store that leaves off the device relations not in expand.
+// Everything is in memory anyway, so they're built and then trimmed.
+func (s *Store) WithExpansion(expand accessors.Expand) accessors.Store {
+	return &Store{state: s.state, expand: expand}
+}
+
+// clone copies every table, so appending to or editing rows in the copy leaves t alone
+func (t *Tables) clone() Tables {
+	return Tables{
+		Buildings:             append([]structs.Building(nil), t.Buildings...),
+		Rooms:                 append([]Room(nil), t.Rooms...),
+		Devices:               append([]Device(nil), t.Devices...),
+		DeviceClasses:         append([]structs.DeviceType(nil), t.DeviceClasses...),
+		DeviceTypes:           append([]structs.DeviceClass(nil), t.DeviceTypes...),
+		DeviceTypePorts:       append([]DeviceTypePort(nil), t.DeviceTypePorts...),
+		DeviceTypeCommands:    append([]DeviceTypeCommand(nil), t.DeviceTypeCommands...),
+		DeviceCommands:        append([]structs.DeviceCommand(nil), t.DeviceCommands...),
+		Commands:              append([]structs.RawCommand(nil), t.Commands...),
+		Endpoints:             append([]structs.Endpoint(nil), t.Endpoints...),
+		Microservices:         append([]structs.Microservice(nil), t.Microservices...),
+		Ports:                 append([]structs.PortType(nil), t.Ports...),
+		PortConfigurations:    append([]structs.PortConfiguration(nil), t.PortConfigurations...),
+		PowerStates:           append([]structs.PowerState(nil), t.PowerStates...),
+		DevicePowerStates:     append([]structs.DevicePowerState(nil), t.DevicePowerStates...),
+		DeviceRoleDefinitions: append([]structs.DeviceRoleDef(nil), t.DeviceRoleDefinitions...),
+		DeviceRoles:           append([]structs.DeviceRole(nil), t.DeviceRoles...),
+		AudioDevices:          append([]AudioDevice(nil), t.AudioDevices...),
+		DeviceAttributes:      append([]DeviceAttribute(nil), t.DeviceAttributes...),
+		DeviceTypeAttributes:  append([]DeviceTypeAttribute(nil), t.DeviceTypeAttributes...),
+		Configurations:        append([]structs.RoomConfiguration(nil), t.Configurations...),
+		ConfigurationMappings: append([]ConfigurationMapping(nil), t.ConfigurationMappings...),
+		RoomPromotions:        append([]RoomPromotion(nil), t.RoomPromotions...),
+	}
+}
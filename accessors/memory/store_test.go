@@ -0,0 +1,176 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+)
+
+// newFixtureStore returns a store seeded with docs/fixture.json. ITB-1101 has D1 (a SonyXBR
+// display with AudioOut and VideoOut, On and Standby, four commands, hdmi1 configured from PC1
+// and an inputDelay attribute), PC1 and CP1; ITB-1001D has another D1.
+func newFixtureStore(t *testing.T) *Store {
+	store, err := NewFromFixture("../../docs/fixture.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store
+}
+
+func TestLoadKeepsFixtureIDs(t *testing.T) {
+	store := newFixtureStore(t)
+	ctx := context.Background()
+
+	building, err := store.AddBuilding(ctx, "Talmage Building", "TMCB", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the fixture has building 1, so a new one is numbered after it
+	if building.ID != 2 {
+		t.Errorf("new building's ID = %d, want 2", building.ID)
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	ctx := context.Background()
+	broken := errors.New("broken")
+
+	tests := []struct {
+		name string
+		err  error
+		kept bool
+	}{
+		{name: "commit", err: nil, kept: true},
+		{name: "roll back", err: broken, kept: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := newFixtureStore(t)
+
+			err := store.Transaction(ctx, func(tx accessors.Store) error {
+				_, err := tx.AddBuilding(ctx, "Talmage Building", "TMCB", "")
+				if err != nil {
+					return err
+				}
+
+				// the transaction sees its own writes
+				_, err = tx.GetBuildingByShortname(ctx, "TMCB")
+				if err != nil {
+					t.Errorf("the transaction can't see the building it added: %s", err)
+				}
+
+				return test.err
+			})
+			if err != test.err {
+				t.Fatalf("Transaction returned %v, want %v", err, test.err)
+			}
+
+			_, err = store.GetBuildingByShortname(ctx, "TMCB")
+			if test.kept && err != nil {
+				t.Errorf("the building wasn't kept: %s", err)
+			}
+			if !test.kept && accessors.KindOf(err) != accessors.NotFound {
+				t.Errorf("the building wasn't rolled back: %v", err)
+			}
+
+			// a rolled back transaction doesn't use up IDs either
+			building, err := store.AddBuilding(ctx, "Eyring Science Center", "ESC", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := 2
+			if test.kept {
+				want = 3
+			}
+			if building.ID != want {
+				t.Errorf("next building's ID = %d, want %d", building.ID, want)
+			}
+		})
+	}
+}
+
+func TestWithExpansion(t *testing.T) {
+	tests := []struct {
+		name   string
+		expand accessors.Expand
+	}{
+		{name: "everything", expand: accessors.ExpandAll},
+		{name: "nothing", expand: accessors.Expand{}},
+		{name: "roles", expand: accessors.Expand{Roles: true}},
+		{name: "commands and ports", expand: accessors.Expand{Commands: true, Ports: true}},
+		{name: "power states and attributes", expand: accessors.Expand{PowerStates: true, Attributes: true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := newFixtureStore(t)
+
+			device, err := store.WithExpansion(test.expand).GetDeviceByBuildingAndRoomAndName(context.Background(), "ITB", "1101", "D1")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			relations := []struct {
+				name     string
+				expanded bool
+				loaded   bool
+			}{
+				{"commands", test.expand.Commands, device.Commands != nil},
+				{"ports", test.expand.Ports, device.Ports != nil},
+				{"power states", test.expand.PowerStates, device.PowerStates != nil},
+				{"roles", test.expand.Roles, device.Roles != nil},
+				{"attributes", test.expand.Attributes, device.Attributes != nil},
+			}
+			for _, relation := range relations {
+				if relation.expanded != relation.loaded {
+					t.Errorf("%s: expanded %v but loaded %v", relation.name, relation.expanded, relation.loaded)
+				}
+			}
+		})
+	}
+}
+
+// TestWithExpansionSharesTables makes sure a narrowed view writes to, and reads from, the store
+// it came from
+func TestWithExpansionSharesTables(t *testing.T) {
+	store := newFixtureStore(t)
+	ctx := context.Background()
+
+	view := store.WithExpansion(accessors.Expand{})
+	_, err := view.AddBuilding(ctx, "Talmage Building", "TMCB", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = store.GetBuildingByShortname(ctx, "TMCB")
+	if err != nil {
+		t.Errorf("the store can't see a building added through its view: %s", err)
+	}
+}
+
+func TestGetDeviceNotFound(t *testing.T) {
+	store := newFixtureStore(t)
+
+	_, err := store.GetDeviceByBuildingAndRoomAndName(context.Background(), "ITB", "1101", "D9")
+	if accessors.KindOf(err) != accessors.NotFound {
+		t.Errorf("got %v, want a NotFound error", err)
+	}
+
+	devices, err := store.GetDevicesByBuildingAndRoom(context.Background(), "ITB", "1101")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{}
+	for _, device := range devices {
+		names = append(names, device.Name)
+	}
+	if len(names) != 3 {
+		t.Errorf("ITB-1101 has devices %v, want D1, PC1 and CP1", names)
+	}
+}
@@ -1,14 +1,15 @@
 package accessors
 
 import (
+	"context"
 	"database/sql"
 	"log"
 
 	"github.com/byuoitav/configuration-database-microservice/structs"
 )
 
-func (accessorGroup *AccessorGroup) GetMicroservices() ([]structs.Microservice, error) {
-	rows, err := accessorGroup.Database.Query("SELECT * FROM Microservices")
+func (accessorGroup *AccessorGroup) GetMicroservices(ctx context.Context) ([]structs.Microservice, error) {
+	rows, err := accessorGroup.db().QueryContext(ctx, "SELECT * FROM Microservices")
 	if err != nil {
 		return []structs.Microservice{}, err
 	}
@@ -22,8 +23,8 @@ func (accessorGroup *AccessorGroup) GetMicroservices() ([]structs.Microservice,
 	return microservices, nil
 }
 
-func (accessorGroup *AccessorGroup) AddMicroservice(microservice structs.Microservice) (structs.Microservice, error) {
-	result, err := accessorGroup.Database.Exec("Insert into Microservices (microserviceID, name, address, description) VALUES(?,?,?,?)", microservice.ID, microservice.Name, microservice.Address, microservice.Description)
+func (accessorGroup *AccessorGroup) AddMicroservice(ctx context.Context, microservice structs.Microservice) (structs.Microservice, error) {
+	result, err := accessorGroup.db().ExecContext(ctx, "Insert into Microservices (microserviceID, name, address, description) VALUES(?,?,?,?)", cleanID(microservice.ID), microservice.Name, microservice.Address, microservice.Description)
 	if err != nil {
 		return structs.Microservice{}, err
 	}
@@ -37,8 +38,8 @@ func (accessorGroup *AccessorGroup) AddMicroservice(microservice structs.Microse
 	return microservice, nil
 }
 
-func (accessorGroup *AccessorGroup) GetMicroserviceByAddress(address string) (structs.Microservice, error) {
-	row := accessorGroup.Database.QueryRow("SELECT * FROM Microservices WHERE address = ? ", address)
+func (accessorGroup *AccessorGroup) GetMicroserviceByAddress(ctx context.Context, address string) (structs.Microservice, error) {
+	row := accessorGroup.db().QueryRowContext(ctx, "SELECT * FROM Microservices WHERE address = ? ", address)
 
 	m, err := extractMicroservice(row)
 	if err != nil {
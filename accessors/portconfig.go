@@ -1,13 +1,14 @@
 package accessors
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/byuoitav/configuration-database-microservice/structs"
 )
 
-func (accessorGroup *AccessorGroup) GetPortConfiguration(building string, room string, device string) ([]structs.PortConfiguration, error) {
-	rows, err := accessorGroup.Database.Query("SELECT * FROM PortConfiguration")
+func (accessorGroup *AccessorGroup) GetPortConfiguration(ctx context.Context, building string, room string, device string) ([]structs.PortConfiguration, error) {
+	rows, err := accessorGroup.db().QueryContext(ctx, "SELECT * FROM PortConfiguration")
 	if err != nil {
 		return []structs.PortConfiguration{}, err
 	}
@@ -21,8 +22,8 @@ func (accessorGroup *AccessorGroup) GetPortConfiguration(building string, room s
 	return portconfigurations, nil
 }
 
-func (ag *AccessorGroup) GetPortsByHostID(hostID int) ([]structs.PortConfiguration, error) {
-	rows, err := ag.Database.Query("SELECT * FROM PortConfiguration WHERE hostDeviceID = ?", hostID)
+func (ag *AccessorGroup) GetPortsByHostID(ctx context.Context, hostID int) ([]structs.PortConfiguration, error) {
+	rows, err := ag.db().QueryContext(ctx, "SELECT * FROM PortConfiguration WHERE hostDeviceID = ?", hostID)
 	if err != nil {
 		return []structs.PortConfiguration{}, err
 	}
@@ -36,8 +37,8 @@ func (ag *AccessorGroup) GetPortsByHostID(hostID int) ([]structs.PortConfigurati
 	return portconfigurations, nil
 }
 
-func (accessorGroup *AccessorGroup) AddPortConfiguration(pc structs.PortConfiguration) (structs.PortConfiguration, error) {
-	response, err := accessorGroup.Database.Exec("INSERT INTO PortConfiguration (portID, hostDeviceID, sourceDeviceID, destinationDeviceID) VALUES(?,?,?,?)", cleanPort(pc.PortID), cleanPort(pc.HostDeviceID), cleanPort(pc.SourceDeviceID), cleanPort(pc.DestinationDeviceID))
+func (accessorGroup *AccessorGroup) AddPortConfiguration(ctx context.Context, pc structs.PortConfiguration) (structs.PortConfiguration, error) {
+	response, err := accessorGroup.db().ExecContext(ctx, "INSERT INTO PortConfiguration (portID, hostDeviceID, sourceDeviceID, destinationDeviceID) VALUES(?,?,?,?)", cleanPort(pc.PortID), cleanPort(pc.HostDeviceID), cleanPort(pc.SourceDeviceID), cleanPort(pc.DestinationDeviceID))
 	if err != nil {
 		return structs.PortConfiguration{}, err
 	}
@@ -48,6 +49,128 @@ func (accessorGroup *AccessorGroup) AddPortConfiguration(pc structs.PortConfigur
 	return pc, nil
 }
 
+// AddDevicePort wires one of a device's ports. The source and destination are devices in the
+// same room, and the port has to be one the host's type has in DeviceTypePorts.
+func (accessorGroup *AccessorGroup) AddDevicePort(ctx context.Context, buildingShortname string, roomName string, deviceName string, port structs.Port) (added structs.Port, err error) {
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		pc, err := tx.portConfiguration(ctx, buildingShortname, roomName, deviceName, port)
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = tx.db().QueryRowContext(ctx, "SELECT COUNT(*) FROM PortConfiguration WHERE hostDeviceID = ? AND portID = ?", pc.HostDeviceID, pc.PortID).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return Errorf(Conflict, "port %s on %s is already configured", port.Name, deviceName)
+		}
+
+		_, err = tx.AddPortConfiguration(ctx, pc)
+		return err
+	})
+	if err != nil {
+		return structs.Port{}, err
+	}
+
+	port.Host = deviceName
+	return port, nil
+}
+
+// UpdateDevicePort points an already configured port at a new source and destination
+func (accessorGroup *AccessorGroup) UpdateDevicePort(ctx context.Context, buildingShortname string, roomName string, deviceName string, portName string, port structs.Port) (updated structs.Port, err error) {
+	port.Name = portName
+
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		pc, err := tx.portConfiguration(ctx, buildingShortname, roomName, deviceName, port)
+		if err != nil {
+			return err
+		}
+
+		// MySQL doesn't count rows an UPDATE leaves as they were, so check for the port first
+		var count int
+		err = tx.db().QueryRowContext(ctx, "SELECT COUNT(*) FROM PortConfiguration WHERE hostDeviceID = ? AND portID = ?", pc.HostDeviceID, pc.PortID).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return Errorf(NotFound, "port %s on %s isn't configured", portName, deviceName)
+		}
+
+		_, err = tx.db().ExecContext(ctx, "UPDATE PortConfiguration SET sourceDeviceID = ?, destinationDeviceID = ? WHERE hostDeviceID = ? AND portID = ?",
+			pc.SourceDeviceID, pc.DestinationDeviceID, pc.HostDeviceID, pc.PortID)
+		return err
+	})
+	if err != nil {
+		return structs.Port{}, err
+	}
+
+	port.Host = deviceName
+	return port, nil
+}
+
+// DeleteDevicePort removes the configuration of one of a device's ports
+func (accessorGroup *AccessorGroup) DeleteDevicePort(ctx context.Context, buildingShortname string, roomName string, deviceName string, portName string) error {
+	return accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		hostID, err := tx.deviceID(ctx, buildingShortname, roomName, deviceName)
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = tx.db().QueryRowContext(ctx, "SELECT COUNT(*) FROM PortConfiguration WHERE hostDeviceID = ? AND portID IN (SELECT portID FROM Ports WHERE name = ?)", hostID, portName).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return Errorf(NotFound, "port %s on %s isn't configured", portName, deviceName)
+		}
+
+		_, err = tx.db().ExecContext(ctx, "DELETE FROM PortConfiguration WHERE hostDeviceID = ? AND portID IN (SELECT portID FROM Ports WHERE name = ?)", hostID, portName)
+		return err
+	})
+}
+
+// portConfiguration turns the names in port into the IDs of a PortConfiguration row hosted by
+// deviceName, checking the source and destination are in the host's room and the port is on
+// the host's type
+func (accessorGroup *AccessorGroup) portConfiguration(ctx context.Context, buildingShortname string, roomName string, deviceName string, port structs.Port) (structs.PortConfiguration, error) {
+	pc := structs.PortConfiguration{}
+
+	hostID, err := accessorGroup.deviceID(ctx, buildingShortname, roomName, deviceName)
+	if err != nil {
+		return pc, err
+	}
+	pc.HostDeviceID = hostID
+
+	pc.SourceDeviceID, err = accessorGroup.deviceID(ctx, buildingShortname, roomName, port.Source)
+	if KindOf(err) == NotFound {
+		return pc, Errorf(Validation, "source device %q isn't in %s-%s", port.Source, buildingShortname, roomName)
+	} else if err != nil {
+		return pc, err
+	}
+
+	pc.DestinationDeviceID, err = accessorGroup.deviceID(ctx, buildingShortname, roomName, port.Destination)
+	if KindOf(err) == NotFound {
+		return pc, Errorf(Validation, "destination device %q isn't in %s-%s", port.Destination, buildingShortname, roomName)
+	} else if err != nil {
+		return pc, err
+	}
+
+	err = accessorGroup.db().QueryRowContext(ctx, `SELECT Ports.portID FROM Ports
+		JOIN DeviceTypePorts ON DeviceTypePorts.portID = Ports.portID
+		JOIN Devices ON Devices.typeID = DeviceTypePorts.deviceTypeID
+		WHERE Devices.deviceID = ? AND Ports.name = ?`, hostID, port.Name).Scan(&pc.PortID)
+	if KindOf(err) == NotFound {
+		return pc, Errorf(Validation, "%s's type has no port called %q", deviceName, port.Name)
+	} else if err != nil {
+		return pc, err
+	}
+
+	return pc, nil
+}
+
 func cleanPort(portID int) interface{} {
 	if portID == 0 {
 		return nil
@@ -96,3 +219,80 @@ func exctractPortConfigurationData(rows *sql.Rows) ([]structs.PortConfiguration,
 
 	return portconfigurations, nil
 }
+
+// GetRoomPortConfiguration returns every port configuration with its host, source or
+// destination in a room, sorted by host, port and ID. The devices are given by name along with
+// their rooms, so ones that point outside the room (or at nothing) can be picked out.
+func (accessorGroup *AccessorGroup) GetRoomPortConfiguration(ctx context.Context, buildingShortname string, roomName string) ([]structs.RoomPort, error) {
+	room, err := accessorGroup.roomByBuildingAndName(ctx, buildingShortname, roomName)
+	if KindOf(err) == NotFound {
+		return []structs.RoomPort{}, Errorf(NotFound, "No rooms found with that name.")
+	} else if err != nil {
+		return []structs.RoomPort{}, err
+	}
+
+	return accessorGroup.roomPorts(ctx, "WHERE source.roomID = ? OR destination.roomID = ? OR host.roomID = ?", room.ID, room.ID, room.ID)
+}
+
+// GetAllPortConfiguration returns every port configuration, as GetRoomPortConfiguration does for
+// one room, so every room's can be checked with one query
+func (accessorGroup *AccessorGroup) GetAllPortConfiguration(ctx context.Context) ([]structs.RoomPort, error) {
+	return accessorGroup.roomPorts(ctx, "")
+}
+
+// roomPorts returns the port configurations that match where, sorted by host, port and ID
+func (accessorGroup *AccessorGroup) roomPorts(ctx context.Context, where string, args ...interface{}) ([]structs.RoomPort, error) {
+	rows, err := accessorGroup.db().QueryContext(ctx, `SELECT PortConfiguration.portConfigurationID, Ports.name,
+		source.name, sourceBuilding.shortName, sourceRoom.name,
+		destination.name, destinationBuilding.shortName, destinationRoom.name,
+		host.name, hostBuilding.shortName, hostRoom.name, DeviceTypes.typeName
+		FROM PortConfiguration
+		LEFT JOIN Ports ON Ports.portID = PortConfiguration.portID
+		LEFT JOIN Devices source ON source.deviceID = PortConfiguration.sourceDeviceID
+		LEFT JOIN Rooms sourceRoom ON sourceRoom.roomID = source.roomID
+		LEFT JOIN Buildings sourceBuilding ON sourceBuilding.buildingID = sourceRoom.buildingID
+		LEFT JOIN Devices destination ON destination.deviceID = PortConfiguration.destinationDeviceID
+		LEFT JOIN Rooms destinationRoom ON destinationRoom.roomID = destination.roomID
+		LEFT JOIN Buildings destinationBuilding ON destinationBuilding.buildingID = destinationRoom.buildingID
+		LEFT JOIN Devices host ON host.deviceID = PortConfiguration.hostDeviceID
+		LEFT JOIN Rooms hostRoom ON hostRoom.roomID = host.roomID
+		LEFT JOIN Buildings hostBuilding ON hostBuilding.buildingID = hostRoom.buildingID
+		LEFT JOIN DeviceTypes ON DeviceTypes.deviceTypeID = host.typeID
+		`+where+`
+		ORDER BY host.name, Ports.name, PortConfiguration.portConfigurationID`, args...)
+	if err != nil {
+		return []structs.RoomPort{}, err
+	}
+	defer rows.Close()
+
+	toReturn := []structs.RoomPort{}
+	for rows.Next() {
+		var port, source, sourceBuilding, sourceRoom, destination, destinationBuilding, destinationRoom, host, hostBuilding, hostRoom, hostType sql.NullString
+		roomPort := structs.RoomPort{}
+
+		err = rows.Scan(&roomPort.ID, &port, &source, &sourceBuilding, &sourceRoom, &destination, &destinationBuilding, &destinationRoom,
+			&host, &hostBuilding, &hostRoom, &hostType)
+		if err != nil {
+			return []structs.RoomPort{}, err
+		}
+
+		roomPort.Port = port.String
+		roomPort.Source, roomPort.SourceRoom = source.String, roomFullName(sourceBuilding, sourceRoom)
+		roomPort.Destination, roomPort.DestinationRoom = destination.String, roomFullName(destinationBuilding, destinationRoom)
+		roomPort.Host, roomPort.HostRoom = host.String, roomFullName(hostBuilding, hostRoom)
+		roomPort.HostType = hostType.String
+
+		toReturn = append(toReturn, roomPort)
+	}
+
+	return toReturn, rows.Err()
+}
+
+// roomFullName is a room's building-room, or empty if the device it was joined through doesn't exist
+func roomFullName(building sql.NullString, room sql.NullString) string {
+	if !building.Valid || !room.Valid {
+		return ""
+	}
+
+	return building.String + "-" + room.String
+}
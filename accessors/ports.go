@@ -1,14 +1,15 @@
 package accessors
 
 import (
+	"context"
 	"database/sql"
 	"log"
 
 	"github.com/byuoitav/configuration-database-microservice/structs"
 )
 
-func (accessorGroup *AccessorGroup) GetAllPorts() ([]structs.PortType, error) {
-	rows, err := accessorGroup.Database.Query("SELECT * FROM Ports")
+func (accessorGroup *AccessorGroup) GetAllPorts(ctx context.Context) ([]structs.PortType, error) {
+	rows, err := accessorGroup.db().QueryContext(ctx, "SELECT * FROM Ports")
 	if err != nil {
 		return []structs.PortType{}, err
 	}
@@ -23,9 +24,9 @@ func (accessorGroup *AccessorGroup) GetAllPorts() ([]structs.PortType, error) {
 }
 
 //AddPort adds an entry to the Ports table in the database
-func (accessorGroup *AccessorGroup) AddPort(portToAdd structs.PortType) (structs.PortType, error) {
+func (accessorGroup *AccessorGroup) AddPort(ctx context.Context, portToAdd structs.PortType) (structs.PortType, error) {
 
-	result, err := accessorGroup.Database.Exec("INSERT into Ports (portID, name, description) VALUES(?,?,?)", portToAdd.ID, portToAdd.Name, portToAdd.Description)
+	result, err := accessorGroup.db().ExecContext(ctx, "INSERT into Ports (portID, name, description) VALUES(?,?,?)", cleanID(portToAdd.ID), portToAdd.Name, portToAdd.Description)
 	if err != nil {
 		return structs.PortType{}, err
 	}
@@ -39,8 +40,8 @@ func (accessorGroup *AccessorGroup) AddPort(portToAdd structs.PortType) (structs
 	return portToAdd, nil
 }
 
-func (accessorGroup *AccessorGroup) GetPortTypeByName(name string) (structs.PortType, error) {
-	row := accessorGroup.Database.QueryRow("SELECT * FROM Ports  WHERE name = ? ", name)
+func (accessorGroup *AccessorGroup) GetPortTypeByName(ctx context.Context, name string) (structs.PortType, error) {
+	row := accessorGroup.db().QueryRowContext(ctx, "SELECT * FROM Ports  WHERE name = ? ", name)
 
 	p, err := extractPortType(row)
 	if err != nil {
@@ -50,7 +51,7 @@ func (accessorGroup *AccessorGroup) GetPortTypeByName(name string) (structs.Port
 	return p, nil
 }
 
-func (accessorGroup *AccessorGroup) GetPortsByDeviceTypeName(typeName string) ([]structs.DeviceTypePort, error) {
+func (accessorGroup *AccessorGroup) GetPortsByDeviceTypeName(ctx context.Context, typeName string) ([]structs.DeviceTypePort, error) {
 	log.Printf("Getting ports for class %v", typeName)
 
 	query :=
@@ -62,7 +63,7 @@ func (accessorGroup *AccessorGroup) GetPortsByDeviceTypeName(typeName string) ([
 	WHERE dt.typeName = ?
 	`
 
-	rows, err := accessorGroup.Database.Query(query, typeName)
+	rows, err := accessorGroup.db().QueryContext(ctx, query, typeName)
 	if err != nil {
 		log.Printf("error: %v", err.Error())
 		return []structs.DeviceTypePort{}, err
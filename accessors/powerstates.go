@@ -1,14 +1,15 @@
 package accessors
 
 import (
+	"context"
 	"database/sql"
 	"log"
 
 	"github.com/byuoitav/configuration-database-microservice/structs"
 )
 
-func (accessorGroup *AccessorGroup) GetPowerStates() ([]structs.PowerState, error) {
-	rows, err := accessorGroup.Database.Query("SELECT * FROM PowerStates")
+func (accessorGroup *AccessorGroup) GetPowerStates(ctx context.Context) ([]structs.PowerState, error) {
+	rows, err := accessorGroup.db().QueryContext(ctx, "SELECT * FROM PowerStates")
 	if err != nil {
 		return []structs.PowerState{}, err
 	}
@@ -22,8 +23,8 @@ func (accessorGroup *AccessorGroup) GetPowerStates() ([]structs.PowerState, erro
 	return powerstates, nil
 }
 
-func (accessorGroup *AccessorGroup) AddPowerState(powerstate structs.PowerState) (structs.PowerState, error) {
-	result, err := accessorGroup.Database.Exec("Insert into PowerStates (powerStateID, name, description) VALUES(?,?,?)", powerstate.ID, powerstate.Name, powerstate.Description)
+func (accessorGroup *AccessorGroup) AddPowerState(ctx context.Context, powerstate structs.PowerState) (structs.PowerState, error) {
+	result, err := accessorGroup.db().ExecContext(ctx, "Insert into PowerStates (powerStateID, name, description) VALUES(?,?,?)", cleanID(powerstate.ID), powerstate.Name, powerstate.Description)
 	if err != nil {
 		return structs.PowerState{}, err
 	}
@@ -37,8 +38,8 @@ func (accessorGroup *AccessorGroup) AddPowerState(powerstate structs.PowerState)
 	return powerstate, nil
 }
 
-func (accessorGroup *AccessorGroup) GetPowerStateByID(id int) (structs.PowerState, error) {
-	row := accessorGroup.Database.QueryRow("SELECT * FROM PowerStates WHERE powerStateID = ?", id)
+func (accessorGroup *AccessorGroup) GetPowerStateByID(ctx context.Context, id int) (structs.PowerState, error) {
+	row := accessorGroup.db().QueryRowContext(ctx, "SELECT * FROM PowerStates WHERE powerStateID = ?", id)
 
 	ps, err := extractPowerState(row)
 	if err != nil {
@@ -48,8 +49,8 @@ func (accessorGroup *AccessorGroup) GetPowerStateByID(id int) (structs.PowerStat
 	return ps, nil
 }
 
-func (accessorGroup *AccessorGroup) GetPowerStateByName(name string) (structs.PowerState, error) {
-	row := accessorGroup.Database.QueryRow("SELECT * FROM PowerStates WHERE name = ?", name)
+func (accessorGroup *AccessorGroup) GetPowerStateByName(ctx context.Context, name string) (structs.PowerState, error) {
+	row := accessorGroup.db().QueryRowContext(ctx, "SELECT * FROM PowerStates WHERE name = ?", name)
 
 	ps, err := extractPowerState(row)
 	if err != nil {
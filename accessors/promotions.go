@@ -0,0 +1,290 @@
+package accessors
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// Production is the designation of the rooms the AV API is deployed to
+const Production = "production"
+
+// IsProduction says whether a designation means production. MySQL compares designations without
+// regard to case, so "Production" is read back as production and has to be treated as such.
+func IsProduction(designation string) bool {
+	return strings.EqualFold(strings.TrimSpace(designation), Production)
+}
+
+// CheckDesignation is the gate every write that sets a room's designation goes through: rooms in
+// production get deployed, so they only get there through PromoteRoom, which checks them first
+// and records who moved them. from is the room's current designation, empty for a new room. It
+// returns the designation to store, which spells production the one way.
+func CheckDesignation(buildingShortname string, roomName string, from string, to string) (string, error) {
+	if !IsProduction(to) {
+		return to, nil
+	}
+	if IsProduction(from) {
+		return Production, nil
+	}
+
+	if len(from) == 0 {
+		return "", Errorf(Conflict, "rooms can't be added straight to production; add %s-%s with another designation, then move it with POST /buildings/%s/rooms/%s/promote?to=production, which checks it first", buildingShortname, roomName, buildingShortname, roomName)
+	}
+
+	return "", Errorf(Conflict, "rooms are moved to production with POST /buildings/%s/rooms/%s/promote?to=production, which checks them first", buildingShortname, roomName)
+}
+
+// CheckPromotion makes sure a room isn't already where a promotion would move it
+func CheckPromotion(buildingShortname string, roomName string, current string, to string) error {
+	if strings.EqualFold(strings.TrimSpace(current), to) {
+		return Errorf(Conflict, "%s-%s is already %s", buildingShortname, roomName, to)
+	}
+
+	return nil
+}
+
+// CheckApproval makes sure approvedBy can approve a pending promotion, and that it still applies
+// to its room, whose designation is now current
+func CheckApproval(promotion structs.Promotion, approvedBy string, current string) error {
+	if promotion.Status != structs.PromotionPending {
+		return Errorf(Conflict, "promotion %d is %s, not pending", promotion.ID, promotion.Status)
+	}
+	if strings.EqualFold(approvedBy, promotion.PromotedBy) {
+		return Errorf(Validation, "promotion %d has to be approved by someone other than %s, who asked for it", promotion.ID, promotion.PromotedBy)
+	}
+	if !strings.EqualFold(strings.TrimSpace(current), promotion.From) {
+		return Errorf(Conflict, "%s-%s has moved from %s to %s since promotion %d was asked for; withdraw it and ask again", promotion.Building, promotion.Room, promotion.From, current, promotion.ID)
+	}
+
+	return nil
+}
+
+// GetRoomPromotions returns the times a room has been moved between designations, or asked to
+// be, oldest first
+func (accessorGroup *AccessorGroup) GetRoomPromotions(ctx context.Context, buildingShortname string, roomName string) ([]structs.Promotion, error) {
+	room, err := accessorGroup.promotionRoom(ctx, buildingShortname, roomName)
+	if err != nil {
+		return []structs.Promotion{}, err
+	}
+
+	rows, err := accessorGroup.db().QueryContext(ctx, `SELECT `+promotionColumns+` FROM RoomPromotions
+		WHERE roomID = ? ORDER BY roomPromotionID`, room.ID)
+	if err != nil {
+		return []structs.Promotion{}, err
+	}
+	defer rows.Close()
+
+	promotions := []structs.Promotion{}
+	for rows.Next() {
+		promotion, err := scanPromotion(rows, buildingShortname, room.Name)
+		if err != nil {
+			return []structs.Promotion{}, err
+		}
+
+		promotions = append(promotions, promotion)
+	}
+
+	return promotions, rows.Err()
+}
+
+// PromoteRoom moves a room to the designation promotion.To and records who did it. It doesn't
+// check that the room is ready for its new designation, or that the move needs no approval;
+// that's up to the caller (see package validation), in the same transaction.
+func (accessorGroup *AccessorGroup) PromoteRoom(ctx context.Context, buildingShortname string, roomName string, promotion structs.Promotion) (promoted structs.Promotion, err error) {
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		room, err := tx.promotionRoom(ctx, buildingShortname, roomName)
+		if err != nil {
+			return err
+		}
+
+		err = CheckPromotion(buildingShortname, room.Name, room.RoomDesignation, promotion.To)
+		if err != nil {
+			return err
+		}
+
+		promotion.Building = buildingShortname
+		promotion.Room = room.Name
+		promotion.From = room.RoomDesignation
+		promotion.Status = structs.PromotionApplied
+		promotion.PromotedAt = time.Now().UTC().Format(time.RFC3339)
+		promotion.ApprovedBy = ""
+		promotion.ApprovedAt = ""
+
+		_, err = tx.db().ExecContext(ctx, "UPDATE Rooms SET roomDesignation = ? WHERE roomID = ?", promotion.To, room.ID)
+		if err != nil {
+			return err
+		}
+
+		promoted, err = tx.insertPromotion(ctx, room.ID, promotion)
+		return err
+	})
+
+	return promoted, err
+}
+
+// RequestPromotion records a promotion that someone else has to approve before it moves the
+// room. A room can only have one pending at a time.
+func (accessorGroup *AccessorGroup) RequestPromotion(ctx context.Context, buildingShortname string, roomName string, promotion structs.Promotion) (requested structs.Promotion, err error) {
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		room, err := tx.promotionRoom(ctx, buildingShortname, roomName)
+		if err != nil {
+			return err
+		}
+
+		err = CheckPromotion(buildingShortname, room.Name, room.RoomDesignation, promotion.To)
+		if err != nil {
+			return err
+		}
+
+		var pending int
+		err = tx.db().QueryRowContext(ctx, "SELECT roomPromotionID FROM RoomPromotions WHERE roomID = ? AND status = ?", room.ID, structs.PromotionPending).Scan(&pending)
+		if err == nil {
+			return Errorf(Conflict, "%s-%s already has a pending promotion, %d; approve or withdraw it first", buildingShortname, room.Name, pending)
+		} else if KindOf(err) != NotFound {
+			return err
+		}
+
+		promotion.Building = buildingShortname
+		promotion.Room = room.Name
+		promotion.From = room.RoomDesignation
+		promotion.Status = structs.PromotionPending
+		promotion.PromotedAt = time.Now().UTC().Format(time.RFC3339)
+		promotion.ApprovedBy = ""
+		promotion.ApprovedAt = ""
+
+		requested, err = tx.insertPromotion(ctx, room.ID, promotion)
+		return err
+	})
+
+	return requested, err
+}
+
+// ApprovePromotion approves a pending promotion on behalf of approvedBy, who can't be the person
+// who asked for it, and moves the room. Like PromoteRoom, checking the room is the caller's job.
+func (accessorGroup *AccessorGroup) ApprovePromotion(ctx context.Context, buildingShortname string, roomName string, id int, approvedBy string) (approved structs.Promotion, err error) {
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		room, promotion, err := tx.roomPromotion(ctx, buildingShortname, roomName, id)
+		if err != nil {
+			return err
+		}
+
+		err = CheckApproval(promotion, approvedBy, room.RoomDesignation)
+		if err != nil {
+			return err
+		}
+
+		promotion.Status = structs.PromotionApplied
+		promotion.ApprovedBy = approvedBy
+		promotion.ApprovedAt = time.Now().UTC().Format(time.RFC3339)
+
+		_, err = tx.db().ExecContext(ctx, "UPDATE Rooms SET roomDesignation = ? WHERE roomID = ?", promotion.To, room.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.db().ExecContext(ctx, "UPDATE RoomPromotions SET status = ?, approvedBy = ?, approvedAt = ? WHERE roomPromotionID = ?",
+			promotion.Status, promotion.ApprovedBy, promotion.ApprovedAt, promotion.ID)
+		if err != nil {
+			return err
+		}
+
+		approved = promotion
+		return nil
+	})
+
+	return approved, err
+}
+
+// WithdrawPromotion drops a pending promotion, leaving the room where it is
+func (accessorGroup *AccessorGroup) WithdrawPromotion(ctx context.Context, buildingShortname string, roomName string, id int) (withdrawn structs.Promotion, err error) {
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		_, promotion, err := tx.roomPromotion(ctx, buildingShortname, roomName, id)
+		if err != nil {
+			return err
+		}
+
+		if promotion.Status != structs.PromotionPending {
+			return Errorf(Conflict, "promotion %d is %s, not pending", promotion.ID, promotion.Status)
+		}
+
+		promotion.Status = structs.PromotionWithdrawn
+		_, err = tx.db().ExecContext(ctx, "UPDATE RoomPromotions SET status = ? WHERE roomPromotionID = ?", promotion.Status, promotion.ID)
+		if err != nil {
+			return err
+		}
+
+		withdrawn = promotion
+		return nil
+	})
+
+	return withdrawn, err
+}
+
+// promotionColumns are the RoomPromotions columns scanPromotion reads, in order
+const promotionColumns = "roomPromotionID, fromDesignation, toDesignation, status, promotedBy, promotedAt, approvedBy, approvedAt"
+
+// scanPromotion reads a promotion from a row of promotionColumns
+func scanPromotion(row interface {
+	Scan(dest ...interface{}) error
+}, buildingShortname string, roomName string) (structs.Promotion, error) {
+	promotion := structs.Promotion{Building: buildingShortname, Room: roomName}
+	var approvedBy, approvedAt sql.NullString
+
+	err := row.Scan(&promotion.ID, &promotion.From, &promotion.To, &promotion.Status, &promotion.PromotedBy, &promotion.PromotedAt, &approvedBy, &approvedAt)
+	if err != nil {
+		return structs.Promotion{}, err
+	}
+
+	promotion.ApprovedBy = approvedBy.String
+	promotion.ApprovedAt = approvedAt.String
+
+	return promotion, nil
+}
+
+func (accessorGroup *AccessorGroup) promotionRoom(ctx context.Context, buildingShortname string, roomName string) (structs.Room, error) {
+	room, err := accessorGroup.roomByBuildingAndName(ctx, buildingShortname, roomName)
+	if KindOf(err) == NotFound {
+		return structs.Room{}, Errorf(NotFound, "No rooms found with that name.")
+	}
+
+	return room, err
+}
+
+// roomPromotion returns a room and one of its promotions
+func (accessorGroup *AccessorGroup) roomPromotion(ctx context.Context, buildingShortname string, roomName string, id int) (structs.Room, structs.Promotion, error) {
+	room, err := accessorGroup.promotionRoom(ctx, buildingShortname, roomName)
+	if err != nil {
+		return structs.Room{}, structs.Promotion{}, err
+	}
+
+	row := accessorGroup.db().QueryRowContext(ctx, `SELECT `+promotionColumns+` FROM RoomPromotions
+		WHERE roomPromotionID = ? AND roomID = ?`, id, room.ID)
+	promotion, err := scanPromotion(row, buildingShortname, room.Name)
+	if KindOf(err) == NotFound {
+		return structs.Room{}, structs.Promotion{}, Errorf(NotFound, "%s-%s has no promotion %d", buildingShortname, room.Name, id)
+	}
+
+	return room, promotion, err
+}
+
+func (accessorGroup *AccessorGroup) insertPromotion(ctx context.Context, roomID int, promotion structs.Promotion) (structs.Promotion, error) {
+	approvedBy := sql.NullString{String: promotion.ApprovedBy, Valid: len(promotion.ApprovedBy) > 0}
+	approvedAt := sql.NullString{String: promotion.ApprovedAt, Valid: len(promotion.ApprovedAt) > 0}
+
+	result, err := accessorGroup.db().ExecContext(ctx, "INSERT INTO RoomPromotions (roomID, fromDesignation, toDesignation, status, promotedBy, promotedAt, approvedBy, approvedAt) VALUES (?,?,?,?,?,?,?,?)",
+		roomID, promotion.From, promotion.To, promotion.Status, promotion.PromotedBy, promotion.PromotedAt, approvedBy, approvedAt)
+	if err != nil {
+		return structs.Promotion{}, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return structs.Promotion{}, err
+	}
+
+	promotion.ID = int(id)
+	return promotion, nil
+}
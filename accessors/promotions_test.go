@@ -0,0 +1,155 @@
+package accessors_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// promotionStep is something done to the promotion alice asked for at the start of a test, and
+// the kind of error it should fail with, or succeeds
+type promotionStep struct {
+	name string
+	do   func(ctx context.Context, store accessors.Store, id int) error
+	kind accessors.Kind
+}
+
+func approve(approvedBy string) func(context.Context, accessors.Store, int) error {
+	return func(ctx context.Context, store accessors.Store, id int) error {
+		_, err := store.ApprovePromotion(ctx, "ITB", "1001D", id, approvedBy)
+		return err
+	}
+}
+
+func withdraw(ctx context.Context, store accessors.Store, id int) error {
+	_, err := store.WithdrawPromotion(ctx, "ITB", "1001D", id)
+	return err
+}
+
+func TestPromotions(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []promotionStep
+		// status and designation are where the promotion and ITB-1001D end up
+		status      string
+		designation string
+	}{
+		{
+			name:        "approve",
+			steps:       []promotionStep{{"bob approves", approve("bob"), succeeds}},
+			status:      structs.PromotionApplied,
+			designation: "production",
+		},
+		{
+			name: "approve your own request",
+			steps: []promotionStep{
+				{"alice approves", approve("alice"), accessors.Validation},
+				{"Alice approves", approve("Alice"), accessors.Validation},
+			},
+			status:      structs.PromotionPending,
+			designation: "stage",
+		},
+		{
+			name: "approve twice",
+			steps: []promotionStep{
+				{"bob approves", approve("bob"), succeeds},
+				{"carol approves", approve("carol"), accessors.Conflict},
+			},
+			status:      structs.PromotionApplied,
+			designation: "production",
+		},
+		{
+			name:        "withdraw",
+			steps:       []promotionStep{{"withdraw", withdraw, succeeds}},
+			status:      structs.PromotionWithdrawn,
+			designation: "stage",
+		},
+		{
+			name: "withdraw after approval",
+			steps: []promotionStep{
+				{"bob approves", approve("bob"), succeeds},
+				{"withdraw", withdraw, accessors.Conflict},
+			},
+			status:      structs.PromotionApplied,
+			designation: "production",
+		},
+		{
+			name: "approve after withdrawing",
+			steps: []promotionStep{
+				{"withdraw", withdraw, succeeds},
+				{"bob approves", approve("bob"), accessors.Conflict},
+			},
+			status:      structs.PromotionWithdrawn,
+			designation: "stage",
+		},
+		{
+			name: "a missing promotion",
+			steps: []promotionStep{
+				{"bob approves the next one", func(ctx context.Context, store accessors.Store, id int) error {
+					return approve("bob")(ctx, store, id+1)
+				}, accessors.NotFound},
+				{"withdraw the next one", func(ctx context.Context, store accessors.Store, id int) error {
+					return withdraw(ctx, store, id+1)
+				}, accessors.NotFound},
+				{"bob approves it in another room", func(ctx context.Context, store accessors.Store, id int) error {
+					_, err := store.ApprovePromotion(ctx, "ITB", "1101", id, "bob")
+					return err
+				}, accessors.NotFound},
+			},
+			status:      structs.PromotionPending,
+			designation: "stage",
+		},
+		{
+			name: "ask twice",
+			steps: []promotionStep{
+				{"carol asks", func(ctx context.Context, store accessors.Store, id int) error {
+					_, err := store.RequestPromotion(ctx, "ITB", "1001D", structs.Promotion{To: "production", PromotedBy: "carol"})
+					return err
+				}, accessors.Conflict},
+			},
+			status:      structs.PromotionPending,
+			designation: "stage",
+		},
+	}
+
+	for backend, newStore := range testStores {
+		for _, test := range tests {
+			t.Run(backend+"/"+test.name, func(t *testing.T) {
+				store, done := newStore(t)
+				defer done()
+
+				ctx := context.Background()
+
+				requested, err := store.RequestPromotion(ctx, "ITB", "1001D", structs.Promotion{To: "production", PromotedBy: "alice"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if requested.Status != structs.PromotionPending {
+					t.Fatalf("requested promotion is %s, want pending", requested.Status)
+				}
+
+				for _, step := range test.steps {
+					checkKind(t, step.name, step.do(ctx, store, requested.ID), step.kind)
+				}
+
+				promotions, err := store.GetRoomPromotions(ctx, "ITB", "1001D")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(promotions) != 1 || promotions[0].Status != test.status {
+					t.Errorf("promotions = %+v, want the one, %s", promotions, test.status)
+				}
+
+				room, err := store.GetRoomByBuildingAndName(ctx, "ITB", "1001D")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if room.RoomDesignation != test.designation {
+					t.Errorf("ITB-1001D is %s, want %s", room.RoomDesignation, test.designation)
+				}
+			})
+		}
+	}
+}
@@ -1,18 +1,19 @@
 package accessors
 
 import (
+	"context"
 	"database/sql"
-	"errors"
+	"fmt"
 	"log"
 
 	"github.com/byuoitav/configuration-database-microservice/structs"
 )
 
 // GetAllRooms returns a list of rooms from the database
-func (accessorGroup *AccessorGroup) GetAllRooms() ([]structs.Room, error) {
+func (accessorGroup *AccessorGroup) GetAllRooms(ctx context.Context) ([]structs.Room, error) {
 	allBuildings := []structs.Building{}
 
-	rows, err := accessorGroup.Database.Query("SELECT * FROM Buildings")
+	rows, err := accessorGroup.db().QueryContext(ctx, "SELECT * FROM Buildings")
 	if err != nil {
 		return []structs.Room{}, err
 	}
@@ -30,8 +31,8 @@ func (accessorGroup *AccessorGroup) GetAllRooms() ([]structs.Room, error) {
 
 	allRooms := []structs.Room{}
 
-	//	rows, err = accessorGroup.Database.Query("SELECT * FROM Rooms WHERE roomDesignation = 'production'")
-	rows, err = accessorGroup.Database.Query("SELECT * FROM Rooms ")
+	//	rows, err = accessorGroup.db().QueryContext(ctx, "SELECT * FROM Rooms WHERE roomDesignation = 'production'")
+	rows, err = accessorGroup.db().QueryContext(ctx, "SELECT * FROM Rooms ")
 	if err != nil {
 		return []structs.Room{}, err
 	}
@@ -46,10 +47,10 @@ func (accessorGroup *AccessorGroup) GetAllRooms() ([]structs.Room, error) {
 	return allRooms, nil
 }
 
-func (accessorGroup *AccessorGroup) GetAllRoomDesignations() ([]string, error) {
+func (accessorGroup *AccessorGroup) GetAllRoomDesignations(ctx context.Context) ([]string, error) {
 	toReturn := []string{}
 
-	rows, err := accessorGroup.Database.Query("SELECT DISTINCT roomDesignation FROM Rooms")
+	rows, err := accessorGroup.db().QueryContext(ctx, "SELECT DISTINCT roomDesignation FROM Rooms")
 	if err != nil {
 		return toReturn, err
 	}
@@ -64,10 +65,10 @@ func (accessorGroup *AccessorGroup) GetAllRoomDesignations() ([]string, error) {
 }
 
 // GetRoomByID returns a room from the database by ID
-func (accessorGroup *AccessorGroup) GetRoomByID(id int) (structs.Room, error) {
+func (accessorGroup *AccessorGroup) GetRoomByID(ctx context.Context, id int) (structs.Room, error) {
 	room := &structs.Room{}
 
-	err := accessorGroup.Database.QueryRow("SELECT * FROM Rooms WHERE roomID=?", id).Scan(&room.ID, &room.Name, &room.Building.ID, &room.Description, &room.ConfigurationID, &room.RoomDesignation)
+	err := accessorGroup.db().QueryRowContext(ctx, "SELECT * FROM Rooms WHERE roomID=?", id).Scan(&room.ID, &room.Name, &room.Building.ID, &room.Description, &room.ConfigurationID, &room.RoomDesignation)
 	if err != nil {
 		return structs.Room{}, err
 	}
@@ -99,12 +100,12 @@ func (accessorGroup *AccessorGroup) ExtractRoomData(rows *sql.Rows) (rooms []str
 }
 
 // GetRoomsByBuilding returns a room from the database by building
-func (accessorGroup *AccessorGroup) GetRoomsByBuilding(building string) ([]structs.Room, error) {
+func (accessorGroup *AccessorGroup) GetRoomsByBuilding(ctx context.Context, building string) ([]structs.Room, error) {
 
-	//rows, err := accessorGroup.Database.Query(`SELECT Rooms.roomID,
+	//rows, err := accessorGroup.db().QueryContext(ctx, `SELECT Rooms.roomID,
 	//Rooms.name, Rooms.buildingID, Rooms.description, Rooms.configurationID, Rooms.roomDesignation FROM Rooms
 	//JOIN Buildings ON Rooms.buildingID = Buildings.buildingID WHERE Buildings.shortName=? AND Rooms.roomDesignation = 'production'`, building)
-	rows, err := accessorGroup.Database.Query(`SELECT Rooms.roomID,
+	rows, err := accessorGroup.db().QueryContext(ctx, `SELECT Rooms.roomID,
 	Rooms.name, Rooms.buildingID, Rooms.description, Rooms.configurationID, Rooms.roomDesignation FROM Rooms
 	JOIN Buildings ON Rooms.buildingID = Buildings.buildingID WHERE Buildings.shortName=?`, building)
 	if err != nil {
@@ -121,9 +122,9 @@ func (accessorGroup *AccessorGroup) GetRoomsByBuilding(building string) ([]struc
 }
 
 // Getstructs.RoomByBuildingAndName returns a room from the database by building shortname and room name
-func (accessorGroup *AccessorGroup) GetRoomByBuildingAndName(buildingShortname string, name string) (structs.Room, error) {
+func (accessorGroup *AccessorGroup) GetRoomByBuildingAndName(ctx context.Context, buildingShortname string, name string) (structs.Room, error) {
 	log.Printf("Getting building info for %s - %s...", buildingShortname, name)
-	building, err := accessorGroup.GetBuildingByShortname(buildingShortname)
+	building, err := accessorGroup.GetBuildingByShortname(ctx, buildingShortname)
 	//
 	log.Printf("TEST: building.ID = %v", building.ID)
 	//
@@ -133,7 +134,7 @@ func (accessorGroup *AccessorGroup) GetRoomByBuildingAndName(buildingShortname s
 
 	room := structs.Room{}
 	log.Printf("Getting room info for %s-%s...", buildingShortname, name)
-	row, err := accessorGroup.Database.Query("SELECT * FROM Rooms WHERE buildingID=? AND name=?", building.ID, name)
+	row, err := accessorGroup.db().QueryContext(ctx, "SELECT * FROM Rooms WHERE buildingID=? AND name=?", building.ID, name)
 	if err != nil {
 		return structs.Room{}, err
 	}
@@ -145,20 +146,20 @@ func (accessorGroup *AccessorGroup) GetRoomByBuildingAndName(buildingShortname s
 	}
 
 	if len(rooms) < 1 {
-		return structs.Room{}, errors.New("No rooms found with that name.")
+		return structs.Room{}, Errorf(NotFound, "No rooms found with that name.")
 	}
 
 	room = rooms[0]
 	room.Building = building
 
 	log.Printf("Getting device info for %s-%s...", buildingShortname, name)
-	room.Devices, err = accessorGroup.GetDevicesByBuildingAndRoom(buildingShortname, name)
+	room.Devices, err = accessorGroup.GetDevicesByBuildingAndRoom(ctx, buildingShortname, name)
 	if err != nil {
 		return room, err
 	}
 
 	log.Printf("Getting configuration information for %s-%s, room key %v...", buildingShortname, name, room.ConfigurationID)
-	room.Configuration, err = accessorGroup.GetConfigurationByConfigurationID(room.ConfigurationID)
+	room.Configuration, err = accessorGroup.GetConfigurationByConfigurationID(ctx, room.ConfigurationID)
 	if err != nil {
 		return room, err
 	}
@@ -167,15 +168,42 @@ func (accessorGroup *AccessorGroup) GetRoomByBuildingAndName(buildingShortname s
 	return room, nil
 }
 
-func (accessorGroup *AccessorGroup) AddRoom(buildingShortName string, roomToAdd structs.Room) (structs.Room, error) {
+// AddRoom adds a room to the building with the given shortname. It won't add one straight to
+// production; see PromoteRoom.
+func (accessorGroup *AccessorGroup) AddRoom(ctx context.Context, buildingShortName string, roomToAdd structs.Room) (added structs.Room, err error) {
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		added, err = tx.addRoom(ctx, buildingShortName, roomToAdd)
+		return err
+	})
+	if err != nil {
+		return structs.Room{}, err
+	}
+
+	return added, nil
+}
+
+func (accessorGroup *AccessorGroup) addRoom(ctx context.Context, buildingShortName string, roomToAdd structs.Room) (structs.Room, error) {
 	log.Printf("Adding room %v to building %v...", roomToAdd.Name, buildingShortName)
 
-	building, err := accessorGroup.GetBuildingByShortname(buildingShortName)
+	var err error
+	roomToAdd.RoomDesignation, err = CheckDesignation(buildingShortName, roomToAdd.Name, "", roomToAdd.RoomDesignation)
+	if err != nil {
+		return structs.Room{}, err
+	}
+
+	building, err := accessorGroup.GetBuildingByShortname(ctx, buildingShortName)
 	if err != nil {
 		return structs.Room{}, err
 	}
 
-	result, err := accessorGroup.Database.Exec("INSERT into Rooms (name, buildingID, description, configurationID, roomDesignation) VALUES (?,?,?,?,?)",
+	_, err = accessorGroup.roomByBuildingAndName(ctx, buildingShortName, roomToAdd.Name)
+	if err == nil {
+		return structs.Room{}, Errorf(Conflict, "there is already a room called %s in %s", roomToAdd.Name, buildingShortName)
+	} else if KindOf(err) != NotFound {
+		return structs.Room{}, err
+	}
+
+	result, err := accessorGroup.db().ExecContext(ctx, "INSERT into Rooms (name, buildingID, description, configurationID, roomDesignation) VALUES (?,?,?,?,?)",
 		roomToAdd.Name, building.ID, roomToAdd.Description, roomToAdd.ConfigurationID, roomToAdd.RoomDesignation)
 	if err != nil {
 		return structs.Room{}, err
@@ -191,3 +219,121 @@ func (accessorGroup *AccessorGroup) AddRoom(buildingShortName string, roomToAdd
 
 	return roomToAdd, nil
 }
+
+// UpdateRoom changes a room's description, configuration and designation, and renames it if
+// room has a new name. An empty name or designation, or a configuration ID of 0, keeps the
+// current value; the description is always replaced. It won't move a room to production; see
+// PromoteRoom.
+func (accessorGroup *AccessorGroup) UpdateRoom(ctx context.Context, buildingShortname string, name string, room structs.Room) (updated structs.Room, err error) {
+	err = accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		current, err := tx.roomByBuildingAndName(ctx, buildingShortname, name)
+		if err != nil {
+			return err
+		}
+
+		if len(room.Name) == 0 {
+			room.Name = current.Name
+		}
+		if len(room.RoomDesignation) == 0 {
+			room.RoomDesignation = current.RoomDesignation
+		}
+		if room.ConfigurationID == 0 {
+			room.ConfigurationID = current.ConfigurationID
+		}
+
+		room.RoomDesignation, err = CheckDesignation(buildingShortname, name, current.RoomDesignation, room.RoomDesignation)
+		if err != nil {
+			return err
+		}
+
+		if room.Name != current.Name {
+			_, err = tx.roomByBuildingAndName(ctx, buildingShortname, room.Name)
+			if err == nil {
+				return Errorf(Conflict, "there is already a room called %s in %s", room.Name, buildingShortname)
+			} else if KindOf(err) != NotFound {
+				return err
+			}
+		}
+
+		if room.ConfigurationID != current.ConfigurationID {
+			configs, err := tx.names(ctx, "SELECT name FROM RoomConfiguration WHERE roomConfigurationID = ?", room.ConfigurationID)
+			if err != nil {
+				return err
+			}
+			if len(configs) == 0 {
+				return Errorf(Validation, "room configuration %d does not exist", room.ConfigurationID)
+			}
+		}
+
+		_, err = tx.db().ExecContext(ctx, "UPDATE Rooms SET name = ?, description = ?, configurationID = ?, roomDesignation = ? WHERE roomID = ?",
+			room.Name, room.Description, room.ConfigurationID, room.RoomDesignation, current.ID)
+		if err != nil {
+			return err
+		}
+
+		updated, err = tx.GetRoomByBuildingAndName(ctx, buildingShortname, room.Name)
+		return err
+	})
+
+	return updated, err
+}
+
+// DeleteRoom deletes a room. If devices are still in it, it refuses with a Conflict listing
+// them, unless cascade is set, in which case they're deleted too, along with their roles,
+// power states and port configurations.
+func (accessorGroup *AccessorGroup) DeleteRoom(ctx context.Context, buildingShortname string, name string, cascade bool) error {
+	return accessorGroup.transaction(ctx, func(tx *AccessorGroup) error {
+		room, err := tx.roomByBuildingAndName(ctx, buildingShortname, name)
+		if err != nil {
+			return err
+		}
+
+		if !cascade {
+			devices, err := tx.names(ctx, "SELECT name FROM Devices WHERE roomID = ? ORDER BY name", room.ID)
+			if err != nil {
+				return err
+			}
+
+			if len(devices) > 0 {
+				blockers := []string{}
+				for _, device := range devices {
+					blockers = append(blockers, fmt.Sprintf("device %s-%s-%s", buildingShortname, name, device))
+				}
+
+				return &Error{
+					Kind:    Conflict,
+					Err:     fmt.Errorf("room %s-%s still has devices; delete them first or pass cascade=true", buildingShortname, name),
+					Details: blockers,
+				}
+			}
+		}
+
+		err = tx.deleteDevicesWhere(ctx, "roomID = ?", room.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.db().ExecContext(ctx, "DELETE FROM RoomPromotions WHERE roomID = ?", room.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.db().ExecContext(ctx, "DELETE FROM Rooms WHERE roomID = ?", room.ID)
+		return err
+	})
+}
+
+// roomByBuildingAndName returns just the Rooms row, without the devices and configuration
+// GetRoomByBuildingAndName fills in
+func (accessorGroup *AccessorGroup) roomByBuildingAndName(ctx context.Context, buildingShortname string, name string) (structs.Room, error) {
+	room := structs.Room{}
+
+	err := accessorGroup.db().QueryRowContext(ctx, `SELECT Rooms.roomID, Rooms.name, Rooms.buildingID, Rooms.description, Rooms.configurationID, Rooms.roomDesignation FROM Rooms
+		JOIN Buildings ON Buildings.buildingID = Rooms.buildingID
+		WHERE Buildings.shortName = ? AND Rooms.name = ?`, buildingShortname, name).Scan(&room.ID, &room.Name, &room.Building.ID, &room.Description, &room.ConfigurationID, &room.RoomDesignation)
+	if err != nil {
+		return structs.Room{}, err
+	}
+
+	return room, nil
+}
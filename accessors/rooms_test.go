@@ -0,0 +1,173 @@
+package accessors_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+func TestAddRoom(t *testing.T) {
+	tests := []struct {
+		name     string
+		building string
+		room     structs.Room
+		kind     accessors.Kind
+	}{
+		{name: "add", building: "ITB", room: structs.Room{Name: "1102", ConfigurationID: 1, RoomDesignation: "stage"}, kind: succeeds},
+		{name: "one that exists", building: "ITB", room: structs.Room{Name: "1101", ConfigurationID: 1, RoomDesignation: "stage"}, kind: accessors.Conflict},
+		{name: "straight to production", building: "ITB", room: structs.Room{Name: "1102", ConfigurationID: 1, RoomDesignation: "production"}, kind: accessors.Conflict},
+		{name: "to a missing building", building: "HBLL", room: structs.Room{Name: "1102", ConfigurationID: 1, RoomDesignation: "stage"}, kind: accessors.NotFound},
+	}
+
+	for store, newStore := range testStores {
+		for _, test := range tests {
+			t.Run(store+"/"+test.name, func(t *testing.T) {
+				ctx := context.Background()
+				s, done := newStore(t)
+				defer done()
+
+				_, err := s.AddRoom(ctx, test.building, test.room)
+				checkKind(t, "AddRoom", err, test.kind)
+
+				// a failed add leaves ITB with the rooms it had
+				rooms, err := s.GetRoomsByBuilding(ctx, "ITB")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				want := 2
+				if test.kind == succeeds {
+					want = 3
+				}
+				if len(rooms) != want {
+					t.Errorf("ITB has %d rooms, want %d", len(rooms), want)
+				}
+			})
+		}
+	}
+}
+
+func TestUpdateRoom(t *testing.T) {
+	tests := []struct {
+		name string
+		room string
+		to   structs.Room
+		kind accessors.Kind
+		// want is the name the room ends up with
+		want string
+	}{
+		{name: "rename", room: "1001D", to: structs.Room{Name: "1001E", Description: "Test cube"}, kind: succeeds, want: "1001E"},
+		{name: "keep the name", room: "1001D", to: structs.Room{Description: "Test cube"}, kind: succeeds, want: "1001D"},
+		{name: "onto a taken name", room: "1001D", to: structs.Room{Name: "1101"}, kind: accessors.Conflict},
+		{name: "to production", room: "1001D", to: structs.Room{RoomDesignation: "production"}, kind: accessors.Conflict},
+		{name: "to a missing configuration", room: "1001D", to: structs.Room{ConfigurationID: 9}, kind: accessors.Validation},
+		{name: "a missing room", room: "9999", to: structs.Room{Name: "9998"}, kind: accessors.NotFound},
+	}
+
+	for store, newStore := range testStores {
+		for _, test := range tests {
+			t.Run(store+"/"+test.name, func(t *testing.T) {
+				ctx := context.Background()
+				s, done := newStore(t)
+				defer done()
+
+				updated, err := s.UpdateRoom(ctx, "ITB", test.room, test.to)
+				checkKind(t, "UpdateRoom", err, test.kind)
+				if test.kind != succeeds {
+					return
+				}
+
+				if updated.Name != test.want || updated.RoomDesignation != "stage" {
+					t.Errorf("updated to %s (%s), want %s (stage)", updated.Name, updated.RoomDesignation, test.want)
+				}
+
+				// the devices move with the room
+				devices, err := s.GetDevicesByBuildingAndRoom(ctx, "ITB", test.want)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(devices) != 1 {
+					t.Errorf("%s has %d devices after the update, want 1", test.want, len(devices))
+				}
+			})
+		}
+	}
+}
+
+func TestDeleteRoom(t *testing.T) {
+	tests := []struct {
+		name    string
+		room    string
+		cascade bool
+		kind    accessors.Kind
+		// details are what a Conflict lists
+		details []string
+		// devices and ports are how many devices and port configurations are left after
+		devices int
+		ports   int
+	}{
+		{
+			name:    "with devices",
+			room:    "1101",
+			kind:    accessors.Conflict,
+			details: []string{"device ITB-1101-CP1", "device ITB-1101-D1", "device ITB-1101-PC1"},
+			devices: 4,
+			ports:   1,
+		},
+		{name: "cascade", room: "1101", cascade: true, kind: succeeds, devices: 1, ports: 0},
+		{name: "cascade leaves the other rooms alone", room: "1001D", cascade: true, kind: succeeds, devices: 3, ports: 1},
+		{name: "an empty room", room: "1102", kind: succeeds, devices: 4, ports: 1},
+		{name: "a missing room", room: "9999", kind: accessors.NotFound, devices: 4, ports: 1},
+	}
+
+	for store, newStore := range testStores {
+		for _, test := range tests {
+			t.Run(store+"/"+test.name, func(t *testing.T) {
+				ctx := context.Background()
+				s, done := newStore(t)
+				defer done()
+
+				_, err := s.AddRoom(ctx, "ITB", structs.Room{Name: "1102", ConfigurationID: 1, RoomDesignation: "stage"})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = s.DeleteRoom(ctx, "ITB", test.room, test.cascade)
+				checkKind(t, "DeleteRoom", err, test.kind)
+
+				if test.details != nil {
+					accessorError, ok := err.(*accessors.Error)
+					if !ok || !reflect.DeepEqual(accessorError.Details, test.details) {
+						t.Errorf("got %#v, want details %q", err, test.details)
+					}
+				}
+
+				_, err = s.GetRoomByBuildingAndName(ctx, "ITB", test.room)
+				if test.kind == succeeds {
+					checkKind(t, "GetRoomByBuildingAndName after deleting", err, accessors.NotFound)
+				} else if test.kind != accessors.NotFound {
+					checkKind(t, "GetRoomByBuildingAndName after failing to delete", err, succeeds)
+				}
+
+				devices, err := s.GetAllDevices(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(devices) != test.devices {
+					t.Errorf("%d devices left, want %d", len(devices), test.devices)
+				}
+
+				ports, err := s.GetAllPortConfiguration(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(ports) != test.ports {
+					t.Errorf("%d port configurations left, want %d", len(ports), test.ports)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package accessors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// schemaTable is a table (or view) and the columns the accessors read from it. Tables read
+// with SELECT * are scanned by position, so for those the columns have to match exactly.
+// migration is the version of the migration that adds the table, or 0 if the database had it
+// before there were migrations.
+type schemaTable struct {
+	name       string
+	columns    []string
+	selectStar bool
+	migration  int
+}
+
+var expectedSchema = []schemaTable{
+	{"Buildings", []string{"buildingID", "name", "shortName", "description"}, true, 0},
+	{"Rooms", []string{"roomID", "name", "buildingID", "description", "configurationID", "roomDesignation"}, true, 0},
+	{"Devices", []string{"deviceID", "name", "address", "input", "output", "buildingID", "roomID", "classID", "typeID", "displayName"}, false, 0},
+	{"DeviceClasses", []string{"deviceClassID", "name", "description"}, true, 0},
+	{"DeviceTypes", []string{"deviceTypeID", "typeName", "typeDescription", "typeDisplayName"}, false, 0},
+	{"DeviceTypePorts", []string{"deviceTypePortID", "deviceTypeID", "portID", "description", "friendlyName", "hostDestinationMirror"}, false, 0},
+	{"DeviceTypeCommandMapping", []string{"deviceTypeID", "commandID", "microserviceID", "endpointID"}, false, 0},
+	{"DeviceCommands", []string{"deviceCommandID", "deviceID", "commandID", "microserviceID", "endpointID", "enabled"}, false, 0},
+	{"Commands", []string{"commandID", "name", "description", "priority"}, true, 0},
+	{"Endpoints", []string{"endpointID", "name", "path", "description"}, true, 0},
+	{"Microservices", []string{"microserviceID", "name", "address", "description"}, true, 0},
+	{"Ports", []string{"portID", "name", "description"}, true, 0},
+	{"PortConfiguration", []string{"portConfigurationID", "destinationDeviceID", "portID", "sourceDeviceID", "hostDeviceID"}, true, 0},
+	{"PowerStates", []string{"powerStateID", "name", "description"}, true, 0},
+	{"DevicePowerStates", []string{"devicePowerStateID", "deviceID", "powerStateID"}, true, 0},
+	{"DeviceRoleDefinition", []string{"deviceRoleDefinitionID", "name", "description"}, true, 0},
+	{"DeviceRole", []string{"deviceRoleID", "deviceID", "deviceRoleDefinitionID"}, true, 0},
+	{"AudioDevices", []string{"deviceID", "muted", "volume"}, false, 0},
+	{"DeviceAttributes", []string{"deviceAttributeID", "deviceID", "name", "type", "value"}, false, 4},
+	{"DeviceTypeAttributes", []string{"deviceTypeAttributeID", "deviceTypeID", "name", "type", "description", "minimum", "maximum", "enumValues", "defaultValue"}, false, 5},
+	{"RoomPromotions", []string{"roomPromotionID", "roomID", "fromDesignation", "toDesignation", "promotedBy", "approvedBy", "promotedAt", "status", "approvedAt"}, false, 6},
+	{"RoomConfiguration", []string{"roomConfigurationID", "name", "description", "roomConfigurationKey", "roomInitializationKey"}, false, 0},
+	{"vConfigurationMapping", []string{"ConfigurationID", "EvaluatorKey", "Priority"}, false, 0},
+}
+
+// VerifySchema checks the database against the columns the accessors scan. It returns one line
+// for each problem it finds, and one for each table that's missing because the migration that
+// adds it hasn't been applied yet (MySQL isn't migrated automatically). Only the routes that use
+// those tables fail without them, so they're kept apart from the problems. An error means it
+// couldn't check at all.
+func (accessorGroup *AccessorGroup) VerifySchema(ctx context.Context) (problems []string, pending []string, err error) {
+	err = accessorGroup.Database.PingContext(ctx)
+	if err != nil {
+		return []string{}, []string{}, err
+	}
+
+	problems, pending = []string{}, []string{}
+
+	for _, table := range expectedSchema {
+		columns, err := accessorGroup.columns(ctx, table.name)
+		if err != nil && table.migration > 0 {
+			pending = append(pending, fmt.Sprintf("%s: %s (it's added by migration %d)", table.name, err, table.migration))
+			continue
+		} else if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", table.name, err))
+			continue
+		}
+
+		problems = append(problems, table.compare(columns)...)
+	}
+
+	return problems, pending, nil
+}
+
+// columns returns the columns of a table in the order SELECT * returns them
+func (accessorGroup *AccessorGroup) columns(ctx context.Context, table string) ([]string, error) {
+	rows, err := accessorGroup.db().QueryContext(ctx, "SELECT * FROM "+table+" LIMIT 0")
+	if err != nil {
+		return []string{}, err
+	}
+	defer rows.Close()
+
+	return rows.Columns()
+}
+
+func (table schemaTable) compare(columns []string) []string {
+	problems := []string{}
+
+	have := make(map[string]bool)
+	for _, column := range columns {
+		have[strings.ToLower(column)] = true
+	}
+
+	for _, column := range table.columns {
+		if !have[strings.ToLower(column)] {
+			problems = append(problems, fmt.Sprintf("%s: missing column %s", table.name, column))
+		}
+	}
+
+	if len(problems) > 0 || !table.selectStar {
+		return problems
+	}
+
+	if len(columns) != len(table.columns) {
+		return append(problems, fmt.Sprintf("%s: SELECT * returns %d columns (%s), the accessors scan %d (%s)",
+			table.name, len(columns), strings.Join(columns, ", "), len(table.columns), strings.Join(table.columns, ", ")))
+	}
+
+	for i := range columns {
+		if !strings.EqualFold(columns[i], table.columns[i]) {
+			return append(problems, fmt.Sprintf("%s: SELECT * returns the columns in the order %s, the accessors scan them as %s",
+				table.name, strings.Join(columns, ", "), strings.Join(table.columns, ", ")))
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,77 @@
+//go:build cgo
+// +build cgo
+
+package accessors
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/byuoitav/configuration-database-microservice/migrations"
+)
+
+func TestVerifySchema(t *testing.T) {
+	tests := []struct {
+		name       string
+		statements []string
+		problems   int
+		pending    int
+	}{
+		{name: "migrated"},
+		{
+			name:       "before the later migrations",
+			statements: []string{"DROP TABLE DeviceAttributes", "DROP TABLE DeviceTypeAttributes", "DROP TABLE RoomPromotions"},
+			pending:    3,
+		},
+		{
+			name:       "missing a table migrations don't add",
+			statements: []string{"DROP TABLE AudioDevices"},
+			problems:   1,
+		},
+		{
+			name:       "missing a column from a migrated table",
+			statements: []string{"ALTER TABLE RoomPromotions DROP COLUMN approvedAt"},
+			problems:   1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "schema")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			accessorGroup := new(AccessorGroup)
+			accessorGroup.OpenSQLite(filepath.Join(dir, "schema.db"))
+			defer accessorGroup.Database.Close()
+
+			err = migrations.Up(accessorGroup.Database, migrations.SQLite)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, statement := range test.statements {
+				_, err = accessorGroup.Database.Exec(statement)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			problems, pending, err := accessorGroup.VerifySchema(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(problems) != test.problems {
+				t.Errorf("problems = %q, want %d", problems, test.problems)
+			}
+			if len(pending) != test.pending {
+				t.Errorf("pending = %q, want %d", pending, test.pending)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package accessors
+
+import (
+	_ "github.com/mattn/go-sqlite3" // Blank import due to its use as a driver
+
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// OpenSQLite opens (creating if necessary) the SQLite database at path and sets it in the struct.
+// A new file has no tables; run the migrations against it before using it.
+func (accessorGroup *AccessorGroup) OpenSQLite(path string) {
+	dataSourceName := fmt.Sprintf("file:%s?_foreign_keys=1&_busy_timeout=5000", path)
+
+	database, err := sql.Open("sqlite3", dataSourceName)
+	if err != nil { // Die if there was an error
+		log.Panicf("Could not open the database: %s\n", err)
+	}
+
+	accessorGroup.Database = database
+	accessorGroup.Driver = "sqlite3"
+}
@@ -0,0 +1,151 @@
+package accessors
+
+import (
+	"context"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// Store is everything the handlers need from a configuration backend. The MySQL
+// AccessorGroup implements it, and so can anything else that wants to stand in
+// for the database (fakes in tests, other storage engines, etc.).
+//
+// Every method takes the context of the request it's serving; the SQL backends cancel their
+// queries once it's done.
+//
+// Methods that take raw SQL fragments (GetDevicesByQuery, GetConfigurationByQuery)
+// or *sql.Rows are deliberately left out; they only make sense for SQL backends.
+type Store interface {
+	// Transaction runs fn against a Store whose writes are all kept if fn returns nil, and
+	// all thrown away if it doesn't.
+	Transaction(ctx context.Context, fn func(Store) error) error
+
+	// WithExpansion returns a Store that only loads the device relations in expand
+	WithExpansion(expand Expand) Store
+
+	// Buildings
+	GetAllBuildings(ctx context.Context) ([]structs.Building, error)
+	GetBuildingByID(ctx context.Context, id int) (structs.Building, error)
+	GetBuildingByShortname(ctx context.Context, shortname string) (structs.Building, error)
+	AddBuilding(ctx context.Context, name string, shortname string, description string) (structs.Building, error)
+	UpdateBuilding(ctx context.Context, shortname string, building structs.Building) (structs.Building, error)
+	DeleteBuilding(ctx context.Context, shortname string, cascade bool) error
+
+	// Rooms
+	GetAllRooms(ctx context.Context) ([]structs.Room, error)
+	GetAllRoomDesignations(ctx context.Context) ([]string, error)
+	GetRoomByID(ctx context.Context, id int) (structs.Room, error)
+	GetRoomsByBuilding(ctx context.Context, building string) ([]structs.Room, error)
+	GetRoomByBuildingAndName(ctx context.Context, buildingShortname string, name string) (structs.Room, error)
+	AddRoom(ctx context.Context, buildingShortName string, roomToAdd structs.Room) (structs.Room, error)
+	UpdateRoom(ctx context.Context, buildingShortname string, name string, room structs.Room) (structs.Room, error)
+	DeleteRoom(ctx context.Context, buildingShortname string, name string, cascade bool) error
+	GetRoomPromotions(ctx context.Context, buildingShortname string, roomName string) ([]structs.Promotion, error)
+	PromoteRoom(ctx context.Context, buildingShortname string, roomName string, promotion structs.Promotion) (structs.Promotion, error)
+	RequestPromotion(ctx context.Context, buildingShortname string, roomName string, promotion structs.Promotion) (structs.Promotion, error)
+	ApprovePromotion(ctx context.Context, buildingShortname string, roomName string, id int, approvedBy string) (structs.Promotion, error)
+	WithdrawPromotion(ctx context.Context, buildingShortname string, roomName string, id int) (structs.Promotion, error)
+
+	// Devices
+	GetDeviceById(ctx context.Context, deviceID int) (structs.Device, error)
+	GetDevicesByRoomId(ctx context.Context, roomId int) ([]structs.Device, error)
+	GetDevicesByRoomIdAndRoleId(ctx context.Context, roomId, roleId int) ([]structs.Device, error)
+	GetAllDevices(ctx context.Context) ([]structs.Device, error)
+	GetDevicesByBuildingAndRoom(ctx context.Context, buildingShortname string, roomName string) ([]structs.Device, error)
+	GetDevicesByBuildingAndRoomAndRole(ctx context.Context, buildingShortname string, roomName string, roleName string) ([]structs.Device, error)
+	GetDevicesByRoleAndType(ctx context.Context, deviceRole string, deviceType string, production string) ([]structs.Device, error)
+	GetDeviceByBuildingAndRoomAndName(ctx context.Context, buildingShortname string, roomName string, deviceName string) (structs.Device, error)
+	GetDeviceCommandsByBuildingAndRoomAndName(ctx context.Context, buildingShortname string, roomName string, deviceName string) ([]structs.Command, error)
+	GetDevicePortsByBuildingAndRoomAndName(ctx context.Context, buildingShortname string, roomName string, deviceName string) ([]structs.Port, error)
+	GetRolesByDeviceID(ctx context.Context, deviceID int) ([]string, error)
+	GetPowerStatesByDeviceID(ctx context.Context, deviceID int) ([]string, error)
+	AddDevice(ctx context.Context, d structs.Device) (structs.Device, error)
+	UpdateDevice(ctx context.Context, buildingShortname string, roomName string, deviceName string, d structs.Device) (structs.Device, error)
+	DeleteDevice(ctx context.Context, buildingShortname string, roomName string, deviceName string) error
+	SetDeviceAttribute(ctx context.Context, info structs.DeviceAttributeInfo) (structs.Device, error)
+	SetDeviceTypeByID(ctx context.Context, id int, deviceID int) error
+	PutDeviceAttributeByDeviceAndRoomAndBuilding(ctx context.Context, building string, room string, device string, attribute string, attributeValue string) (structs.Device, error)
+
+	// Device attributes
+	GetDeviceAttributes(ctx context.Context, buildingShortname string, roomName string, deviceName string) ([]structs.DeviceAttribute, error)
+	PutDeviceAttribute(ctx context.Context, buildingShortname string, roomName string, deviceName string, attribute structs.DeviceAttribute) (structs.DeviceAttribute, error)
+	DeleteDeviceAttribute(ctx context.Context, buildingShortname string, roomName string, deviceName string, name string) error
+	GetDevicesByAttribute(ctx context.Context, name string, value string) ([]structs.Device, error)
+
+	// Configurations
+	GetConfigurations(ctx context.Context) ([]structs.RoomConfiguration, error)
+	GetConfigurationByConfigurationID(ctx context.Context, configurationID int) (structs.RoomConfiguration, error)
+	GetConfigurationByConfigurationName(ctx context.Context, name string) (structs.RoomConfiguration, error)
+	GetConfigurationByRoomAndBuilding(ctx context.Context, building string, room string) (structs.RoomConfiguration, error)
+	GetEvaluatorsForConfigurationByID(ctx context.Context, configurationID int) ([]structs.ConfigurationEvaluator, error)
+	AddConfiguration(ctx context.Context, config structs.RoomConfiguration) (structs.RoomConfiguration, error)
+	UpdateConfiguration(ctx context.Context, name string, config structs.RoomConfiguration) (structs.RoomConfiguration, error)
+	AddConfigurationEvaluator(ctx context.Context, configurationName string, evaluator structs.ConfigurationEvaluator) (structs.ConfigurationEvaluator, error)
+	UpdateConfigurationEvaluator(ctx context.Context, configurationName string, evaluatorKey string, evaluator structs.ConfigurationEvaluator) (structs.ConfigurationEvaluator, error)
+	DeleteConfigurationEvaluator(ctx context.Context, configurationName string, evaluatorKey string) error
+
+	// Device classes and types
+	GetDeviceClasses(ctx context.Context) ([]structs.DeviceType, error)
+	GetDeviceTypeByID(ctx context.Context, id int) (structs.DeviceType, error)
+	GetDeviceTypeByName(ctx context.Context, name string) (structs.DeviceType, error)
+	AddDeviceType(ctx context.Context, deviceType structs.DeviceType) (structs.DeviceType, error)
+	GetDeviceTypes(ctx context.Context) ([]structs.DeviceClass, error)
+	GetDeviceClassByName(ctx context.Context, name string) (structs.DeviceClass, error)
+	GetDeviceTypeCommands(ctx context.Context, typeName string) (structs.DeviceTypeCommands, error)
+	AddDeviceTypeCommand(ctx context.Context, typeName string, command structs.DeviceTypeCommand) (structs.DeviceTypeCommand, error)
+	DeleteDeviceTypeCommand(ctx context.Context, typeName string, commandName string) error
+	GetDeviceTypeAttributes(ctx context.Context, typeName string) ([]structs.DeviceTypeAttribute, error)
+	SetDeviceTypeAttributes(ctx context.Context, typeName string, schema []structs.DeviceTypeAttribute) ([]structs.DeviceTypeAttribute, error)
+	AddDeviceTypeAttribute(ctx context.Context, typeName string, rule structs.DeviceTypeAttribute) (structs.DeviceTypeAttribute, error)
+	UpdateDeviceTypeAttribute(ctx context.Context, typeName string, name string, rule structs.DeviceTypeAttribute) (structs.DeviceTypeAttribute, error)
+	DeleteDeviceTypeAttribute(ctx context.Context, typeName string, name string) error
+
+	// Ports
+	GetAllPorts(ctx context.Context) ([]structs.PortType, error)
+	GetPortTypeByName(ctx context.Context, name string) (structs.PortType, error)
+	AddPort(ctx context.Context, portToAdd structs.PortType) (structs.PortType, error)
+	GetPortsByDeviceTypeName(ctx context.Context, typeName string) ([]structs.DeviceTypePort, error)
+	AddDeviceTypePort(ctx context.Context, typeName string, dtp structs.DeviceTypePort) (structs.DeviceTypePort, error)
+	UpdateDeviceTypePort(ctx context.Context, typeName string, portName string, dtp structs.DeviceTypePort) (structs.DeviceTypePort, error)
+	DeleteDeviceTypePort(ctx context.Context, typeName string, portName string) error
+	GetPortConfiguration(ctx context.Context, building string, room string, device string) ([]structs.PortConfiguration, error)
+	GetRoomPortConfiguration(ctx context.Context, buildingShortname string, roomName string) ([]structs.RoomPort, error)
+	GetAllPortConfiguration(ctx context.Context) ([]structs.RoomPort, error)
+	GetPortsByHostID(ctx context.Context, hostID int) ([]structs.PortConfiguration, error)
+	AddPortConfiguration(ctx context.Context, pc structs.PortConfiguration) (structs.PortConfiguration, error)
+	AddDevicePort(ctx context.Context, buildingShortname string, roomName string, deviceName string, port structs.Port) (structs.Port, error)
+	UpdateDevicePort(ctx context.Context, buildingShortname string, roomName string, deviceName string, portName string, port structs.Port) (structs.Port, error)
+	DeleteDevicePort(ctx context.Context, buildingShortname string, roomName string, deviceName string, portName string) error
+
+	// Commands, endpoints and microservices
+	GetAllCommands(ctx context.Context) ([]structs.RawCommand, error)
+	GetRawCommandByName(ctx context.Context, name string) (structs.RawCommand, error)
+	AddRawCommand(ctx context.Context, rc structs.RawCommand) (structs.RawCommand, error)
+	AddDeviceCommand(ctx context.Context, dc structs.DeviceCommand) (structs.DeviceCommand, error)
+	GetAllEndpoints(ctx context.Context) ([]structs.Endpoint, error)
+	GetEndpointByName(ctx context.Context, name string) (structs.Endpoint, error)
+	AddEndpoint(ctx context.Context, toAdd structs.Endpoint) (structs.Endpoint, error)
+	RemoveEndpointByName(ctx context.Context, name string) error
+	GetMicroservices(ctx context.Context) ([]structs.Microservice, error)
+	GetMicroserviceByAddress(ctx context.Context, address string) (structs.Microservice, error)
+	AddMicroservice(ctx context.Context, microservice structs.Microservice) (structs.Microservice, error)
+
+	// Power states
+	GetPowerStates(ctx context.Context) ([]structs.PowerState, error)
+	GetPowerStateByID(ctx context.Context, id int) (structs.PowerState, error)
+	GetPowerStateByName(ctx context.Context, name string) (structs.PowerState, error)
+	AddPowerState(ctx context.Context, powerstate structs.PowerState) (structs.PowerState, error)
+	GetDevicePowerStates(ctx context.Context) ([]structs.DevicePowerState, error)
+	AddDevicePowerState(ctx context.Context, dps structs.DevicePowerState) (structs.DevicePowerState, error)
+
+	// Roles
+	GetDeviceRoleDefs(ctx context.Context) ([]structs.DeviceRoleDef, error)
+	GetDeviceRoleDefByID(ctx context.Context, id int) (structs.DeviceRoleDef, error)
+	GetDeviceRoleDefByName(ctx context.Context, name string) (structs.DeviceRoleDef, error)
+	AddDeviceRoleDef(ctx context.Context, deviceroledef structs.DeviceRoleDef) (structs.DeviceRoleDef, error)
+	GetDeviceRoles(ctx context.Context) ([]structs.DeviceRole, error)
+	AddDeviceRole(ctx context.Context, dr structs.DeviceRole) (structs.DeviceRole, error)
+}
+
+// make sure the MySQL accessors keep satisfying the interface
+var _ Store = (*AccessorGroup)(nil)
@@ -0,0 +1,87 @@
+//go:build cgo
+// +build cgo
+
+package accessors_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/migrations"
+)
+
+func init() {
+	testStores["sqlite"] = func(t *testing.T) (accessors.Store, func()) {
+		return newFixtureDatabase(t)
+	}
+}
+
+// fixtureStatements fill a migrated database with what docs/fixture.json has. Migration 2 seeds
+// the role definitions and power states with the fixture's IDs.
+var fixtureStatements = []string{
+	"INSERT INTO Buildings (buildingID, name, shortName, description) VALUES (1, 'Information Technology Building', 'ITB', 'Home of OIT AV')",
+	"INSERT INTO RoomConfiguration (roomConfigurationID, name, description, roomConfigurationKey, roomInitializationKey) VALUES (1, 'Default', 'The default room configuration', 'Default', 'Default')",
+	`INSERT INTO RoomConfigurationMapping (roomConfigurationID, evaluatorKey, priority) VALUES
+		(1, 'PowerOnDefault', 1), (1, 'StandbyDefault', 9999), (1, 'ChangeVideoInputDefault', 1337)`,
+	`INSERT INTO Rooms (roomID, name, buildingID, description, configurationID, roomDesignation) VALUES
+		(1, '1101', 1, 'Conference room', 1, 'production'), (2, '1001D', 1, 'Test cube', 1, 'stage')`,
+	`INSERT INTO DeviceClasses (deviceClassID, name, description) VALUES
+		(1, 'display', 'A TV or projector'), (2, 'computer', 'A computer input'), (3, 'pi', 'A Raspberry Pi touchpanel')`,
+	`INSERT INTO DeviceTypes (deviceTypeID, typeName, typeDescription, typeDisplayName) VALUES
+		(1, 'SonyXBR', 'The Sony XBR TV line.', 'Sony XBR'), (2, 'non-controllable', 'A device we don''t talk to', 'Non-controllable'), (3, 'Pi3', 'A Raspberry Pi 3', 'Raspberry Pi 3')`,
+	`INSERT INTO Devices (deviceID, name, address, input, output, buildingID, roomID, classID, typeID, displayName) VALUES
+		(1, 'D1', 'ITB-1101-D1.byu.edu', 0, 1, 1, 1, 1, 1, 'Sony XBR'),
+		(2, 'PC1', '0.0.0.0', 1, 0, 1, 1, 2, 2, 'Computer'),
+		(3, 'CP1', 'ITB-1101-CP1.byu.edu', 0, 0, 1, 1, 3, 3, 'Raspberry Pi 3'),
+		(4, 'D1', 'ITB-1001D-D1.byu.edu', 0, 1, 1, 2, 1, 1, 'Sony XBR')`,
+	"INSERT INTO DeviceRole (deviceID, deviceRoleDefinitionID) VALUES (1, 1), (1, 2), (2, 3), (2, 4), (3, 5), (3, 6), (4, 1), (4, 2)",
+	"INSERT INTO DevicePowerStates (deviceID, powerStateID) VALUES (1, 1), (1, 2), (4, 1), (4, 2)",
+	`INSERT INTO Commands (commandID, name, description, priority) VALUES
+		(1, 'PowerOn', 'Pull out of standby', 1), (2, 'Standby', 'Put into standby', 1),
+		(3, 'ChangeInput', 'Change the input to the supplied port', 10), (4, 'SetVolume', 'Change the volume to supplied value', 10)`,
+	`INSERT INTO Endpoints (endpointID, name, path, description) VALUES
+		(1, 'PowerOn', '/:address/power/on', 'Standard PowerOn endpoint.'), (2, 'Standby', '/:address/power/standby', 'Standard standby endpoint.'),
+		(3, 'ChangeInput', '/:address/input/:port', 'Standard ChangeInput endpoint.'), (4, 'SetVolume', '/:address/volume/set/:level', 'Standard SetVolume endpoint.')`,
+	"INSERT INTO Microservices (microserviceID, name, address, description) VALUES (1, 'sony-control-microservice', 'http://localhost:8007', '')",
+	"INSERT INTO DeviceTypeCommandMapping (deviceTypeID, commandID, microserviceID, endpointID) VALUES (1, 1, 1, 1), (1, 2, 1, 2), (1, 3, 1, 3), (1, 4, 1, 4)",
+	"INSERT INTO Ports (portID, name, description) VALUES (1, 'hdmi1', 'HDMI 1'), (2, 'hdmi2', 'HDMI 2')",
+	`INSERT INTO DeviceTypePorts (deviceTypeID, portID, description, friendlyName, hostDestinationMirror) VALUES
+		(1, 1, 'HDMI 1', 'HDMI 1', 1), (1, 2, 'HDMI 2', 'HDMI 2', 1)`,
+	"INSERT INTO PortConfiguration (destinationDeviceID, portID, sourceDeviceID, hostDeviceID) VALUES (1, 1, 2, 1)",
+	"INSERT INTO AudioDevices (deviceID, muted, volume) VALUES (1, 0, 30), (4, 0, 30)",
+	"INSERT INTO DeviceAttributes (deviceID, name, type, value) VALUES (1, 'inputDelay', 'int', '0')",
+}
+
+// newFixtureDatabase migrates a new SQLite database and fills it with fixtureStatements
+func newFixtureDatabase(t *testing.T) (*accessors.AccessorGroup, func()) {
+	dir, err := ioutil.TempDir("", "fixture")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accessorGroup := new(accessors.AccessorGroup)
+	accessorGroup.OpenSQLite(filepath.Join(dir, "fixture.db"))
+	done := func() {
+		accessorGroup.Database.Close()
+		os.RemoveAll(dir)
+	}
+
+	err = migrations.Up(accessorGroup.Database, migrations.SQLite)
+	if err != nil {
+		done()
+		t.Fatal(err)
+	}
+
+	for _, statement := range fixtureStatements {
+		_, err = accessorGroup.Database.Exec(statement)
+		if err != nil {
+			done()
+			t.Fatalf("%s: %s", statement, err)
+		}
+	}
+
+	return accessorGroup, done
+}
@@ -0,0 +1,35 @@
+package accessors_test
+
+import (
+	"testing"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/accessors/memory"
+)
+
+// testStores make the stores the tests in this package run against, seeded alike with the
+// buildings, rooms and devices in docs/fixture.json, and return a func that cleans up after
+// them. stores_sqlite_test.go adds SQLite where cgo is available.
+var testStores = map[string]func(t *testing.T) (accessors.Store, func()){
+	"memory": func(t *testing.T) (accessors.Store, func()) {
+		store, err := memory.NewFromFixture("../docs/fixture.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return store, func() {}
+	},
+}
+
+// succeeds is the kind checkKind expects of a call that shouldn't fail
+const succeeds accessors.Kind = -1
+
+// checkKind makes sure err is the kind of error want says, or nil if want is succeeds
+func checkKind(t *testing.T, what string, err error, want accessors.Kind) {
+	if want == succeeds && err != nil {
+		t.Errorf("%s: %s", what, err)
+	}
+	if want != succeeds && (err == nil || accessors.KindOf(err) != want) {
+		t.Errorf("%s: got %v, want a %s error", what, err, want)
+	}
+}
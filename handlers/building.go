@@ -4,15 +4,16 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/byuoitav/configuration-database-microservice/accessors"
 	"github.com/byuoitav/configuration-database-microservice/structs"
 	"github.com/labstack/echo"
 )
 
 //GetAllBuildings gets all buildings
 func (handlerGroup *HandlerGroup) GetAllBuildings(context echo.Context) error {
-	response, err := handlerGroup.Accessors.GetAllBuildings()
+	response, err := handlerGroup.Accessors.GetAllBuildings(context.Request().Context())
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -22,12 +23,12 @@ func (handlerGroup *HandlerGroup) GetAllBuildings(context echo.Context) error {
 func (handlerGroup *HandlerGroup) GetBuildingByID(context echo.Context) error {
 	id, err := strconv.Atoi(context.Param("id"))
 	if err != nil {
-		return err
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	response, err := handlerGroup.Accessors.GetBuildingByID(id)
+	response, err := handlerGroup.Accessors.GetBuildingByID(context.Request().Context(), id)
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -35,9 +36,9 @@ func (handlerGroup *HandlerGroup) GetBuildingByID(context echo.Context) error {
 
 //GetBuildingByShortname gets building by shortname (i.e. ITB or HBLL)
 func (handlerGroup *HandlerGroup) GetBuildingByShortname(context echo.Context) error {
-	response, err := handlerGroup.Accessors.GetBuildingByShortname(context.Param("shortname"))
+	response, err := handlerGroup.Accessors.GetBuildingByShortname(context.Request().Context(), context.Param("building"))
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -48,17 +49,52 @@ func (handlerGroup *HandlerGroup) AddBuilding(context echo.Context) error {
 	var building structs.Building
 	err := context.Bind(&building)
 	if err != nil {
-		return context.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
-	_, err = handlerGroup.Accessors.GetBuildingByShortname(building.Shortname)
+	_, err = handlerGroup.Accessors.GetBuildingByShortname(context.Request().Context(), building.Shortname)
 	if err == nil {
-		return context.JSON(http.StatusBadRequest, "Building already exists in database")
+		return accessors.Errorf(accessors.Conflict, "Building already exists in database")
+	} else if accessors.KindOf(err) != accessors.NotFound {
+		return err
 	}
 
-	building, err = handlerGroup.Accessors.AddBuilding(building.Name, building.Shortname, building.Description)
+	building, err = handlerGroup.Accessors.AddBuilding(context.Request().Context(), building.Name, building.Shortname, building.Description)
 	if err != nil {
-		return context.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 	return context.JSON(http.StatusOK, building)
 }
+
+//UpdateBuilding replaces a building's name, shortname and description. The building is
+//addressed by its current shortname.
+func (handlerGroup *HandlerGroup) UpdateBuilding(context echo.Context) error {
+	var building structs.Building
+	err := context.Bind(&building)
+	if err != nil {
+		return err
+	}
+
+	response, err := handlerGroup.Accessors.UpdateBuilding(context.Request().Context(), context.Param("building"), building)
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+//DeleteBuilding deletes a building. It refuses while rooms or devices are in the building,
+//unless ?cascade=true, which deletes them too.
+func (handlerGroup *HandlerGroup) DeleteBuilding(context echo.Context) error {
+	cascade, err := cascade(context)
+	if err != nil {
+		return err
+	}
+
+	err = handlerGroup.Accessors.DeleteBuilding(context.Request().Context(), context.Param("building"), cascade)
+	if err != nil {
+		return err
+	}
+
+	return context.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo"
+)
+
+func TestBuildings(t *testing.T) {
+	tests := []handlerTest{
+		{name: "get", method: echo.GET, path: "/buildings/ITB", status: http.StatusOK, contains: `"shortname":"ITB"`},
+		{name: "get by id", method: echo.GET, path: "/buildings/id/1", status: http.StatusOK, contains: `"shortname":"ITB"`},
+		{name: "get one that doesn't exist", method: echo.GET, path: "/buildings/HBLL", status: http.StatusNotFound},
+		{name: "add", method: echo.POST, path: "/buildings/TMCB", body: `{"name":"Talmage","shortname":"TMCB"}`, status: http.StatusOK, contains: `"shortname":"TMCB"`},
+		{name: "add one that exists", method: echo.POST, path: "/buildings/ITB", body: `{"name":"IT Building","shortname":"ITB"}`, status: http.StatusConflict},
+		{name: "rename", method: echo.PUT, path: "/buildings/ITB", body: `{"name":"IT Building","shortname":"ITB2"}`, status: http.StatusOK, contains: `"shortname":"ITB2"`},
+		{name: "update one that doesn't exist", method: echo.PUT, path: "/buildings/HBLL", body: `{"name":"Library"}`, status: http.StatusNotFound},
+		{name: "delete with rooms in it", method: echo.DELETE, path: "/buildings/ITB", status: http.StatusConflict, contains: "ITB-1101-D1"},
+		{name: "delete with rooms in it, cascading", method: echo.DELETE, path: "/buildings/ITB?cascade=true", status: http.StatusNoContent},
+		{name: "delete one that doesn't exist", method: echo.DELETE, path: "/buildings/HBLL", status: http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := newTestRouter(t, func(router *echo.Echo, handlerGroup *HandlerGroup) {
+				// echo names a path's parameter after the last route registered there, so these
+				// all have to agree with each other, as they do in server.go
+				router.GET("/buildings/id/:id", handlerGroup.GetBuildingByID)
+				router.GET("/buildings/:building", handlerGroup.GetBuildingByShortname)
+				router.POST("/buildings/:building", handlerGroup.AddBuilding)
+				router.PUT("/buildings/:building", handlerGroup.UpdateBuilding)
+				router.DELETE("/buildings/:building", handlerGroup.DeleteBuilding)
+			})
+
+			test.run(t, router)
+		})
+	}
+}
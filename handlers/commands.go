@@ -10,9 +10,9 @@ import (
 /*GetAllCommands simply returns a dump of the commands table in the DB.
  */
 func (handlerGroup *HandlerGroup) GetAllCommands(context echo.Context) error {
-	response, err := handlerGroup.Accessors.GetAllCommands()
+	response, err := handlerGroup.Accessors.GetAllCommands(context.Request().Context())
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -24,15 +24,15 @@ func (handlerGroup *HandlerGroup) AddCommand(context echo.Context) error {
 
 	err := context.Bind(&cmd)
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 	if cmdName != cmd.Name {
-		return context.JSON(http.StatusBadRequest, "Endpoint parameter and json name must match!")
+		return echo.NewHTTPError(http.StatusBadRequest, "Endpoint parameter and json name must match!")
 	}
 
-	response, err := handlerGroup.Accessors.AddRawCommand(cmd)
+	response, err := handlerGroup.Accessors.AddRawCommand(context.Request().Context(), cmd)
 	if err != nil {
-		return context.JSON(http.StatusInternalServerError, response)
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
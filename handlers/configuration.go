@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+	"github.com/labstack/echo"
+)
+
+// AddConfiguration adds a room configuration, and any evaluators listed with it
+func (handlerGroup *HandlerGroup) AddConfiguration(context echo.Context) error {
+	var config structs.RoomConfiguration
+	err := context.Bind(&config)
+	if err != nil {
+		return err
+	}
+
+	if len(config.Name) > 0 && config.Name != context.Param("configuration") {
+		return echo.NewHTTPError(http.StatusBadRequest, "Endpoint parameter and json name must match!")
+	}
+	config.Name = context.Param("configuration")
+
+	response, err := handlerGroup.Accessors.AddConfiguration(context.Request().Context(), config)
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+// UpdateConfiguration changes a room configuration's keys and description, or renames it. An
+// empty name, roomKey or roomInitKey keeps the current value; evaluators, if given, replace the
+// current ones.
+func (handlerGroup *HandlerGroup) UpdateConfiguration(context echo.Context) error {
+	var config structs.RoomConfiguration
+	err := context.Bind(&config)
+	if err != nil {
+		return err
+	}
+
+	response, err := handlerGroup.Accessors.UpdateConfiguration(context.Request().Context(), context.Param("configuration"), config)
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+// AddConfigurationEvaluator adds an evaluator to a room configuration at the priority in the body
+func (handlerGroup *HandlerGroup) AddConfigurationEvaluator(context echo.Context) error {
+	evaluator, err := bindEvaluator(context)
+	if err != nil {
+		return err
+	}
+
+	response, err := handlerGroup.Accessors.AddConfigurationEvaluator(context.Request().Context(), context.Param("configuration"), evaluator)
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+// UpdateConfigurationEvaluator moves an evaluator to the priority in the body
+func (handlerGroup *HandlerGroup) UpdateConfigurationEvaluator(context echo.Context) error {
+	evaluator, err := bindEvaluator(context)
+	if err != nil {
+		return err
+	}
+
+	response, err := handlerGroup.Accessors.UpdateConfigurationEvaluator(context.Request().Context(), context.Param("configuration"), context.Param("evaluator"), evaluator)
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+// DeleteConfigurationEvaluator removes an evaluator from a room configuration
+func (handlerGroup *HandlerGroup) DeleteConfigurationEvaluator(context echo.Context) error {
+	err := handlerGroup.Accessors.DeleteConfigurationEvaluator(context.Request().Context(), context.Param("configuration"), context.Param("evaluator"))
+	if err != nil {
+		return err
+	}
+
+	return context.NoContent(http.StatusNoContent)
+}
+
+func bindEvaluator(context echo.Context) (structs.ConfigurationEvaluator, error) {
+	var evaluator structs.ConfigurationEvaluator
+	err := context.Bind(&evaluator)
+	if err != nil {
+		return structs.ConfigurationEvaluator{}, err
+	}
+
+	if len(evaluator.EvaluatorKey) > 0 && evaluator.EvaluatorKey != context.Param("evaluator") {
+		return structs.ConfigurationEvaluator{}, echo.NewHTTPError(http.StatusBadRequest, "Endpoint parameter and json evaluatorKey must match!")
+	}
+	evaluator.EvaluatorKey = context.Param("evaluator")
+
+	return evaluator, nil
+}
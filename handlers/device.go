@@ -18,9 +18,14 @@ func (handlerGroup *HandlerGroup) SetDeviceAttribute(context echo.Context) error
 		return err
 	}
 
-	device, err := handlerGroup.Accessors.SetDeviceAttribute(info)
+	store, err := handlerGroup.expanded(context)
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	device, err := store.SetDeviceAttribute(context.Request().Context(), info)
+	if err != nil {
+		return err
 	}
 
 	return context.JSON(http.StatusOK, device)
@@ -28,18 +33,28 @@ func (handlerGroup *HandlerGroup) SetDeviceAttribute(context echo.Context) error
 
 func (handlerGroup *HandlerGroup) GetDevicesByBuildingAndRoom(context echo.Context) error {
 
-	response, err := handlerGroup.Accessors.GetDevicesByBuildingAndRoom(context.Param("building"), context.Param("room"))
+	store, err := handlerGroup.expanded(context)
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	response, err := store.GetDevicesByBuildingAndRoom(context.Request().Context(), context.Param("building"), context.Param("room"))
+	if err != nil {
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
 }
 
 func (handlerGroup *HandlerGroup) GetDevicesByBuildingAndRoomAndRole(context echo.Context) error {
-	response, err := handlerGroup.Accessors.GetDevicesByBuildingAndRoomAndRole(context.Param("building"), context.Param("room"), context.Param("role"))
+	store, err := handlerGroup.expanded(context)
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	response, err := store.GetDevicesByBuildingAndRoomAndRole(context.Request().Context(), context.Param("building"), context.Param("room"), context.Param("role"))
+	if err != nil {
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -49,53 +64,78 @@ func (handlerGroup *HandlerGroup) GetDeviceById(context echo.Context) error {
 
 	deviceId, err := strconv.Atoi(context.Param("id"))
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, fmt.Sprintf("invalid device ID: %s", err.Error()))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid device ID: %s", err.Error()))
 	}
 
-	response, err := handlerGroup.Accessors.GetDeviceById(deviceId)
+	store, err := handlerGroup.expanded(context)
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	response, err := store.GetDeviceById(context.Request().Context(), deviceId)
+	if err != nil {
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
 }
 
 func (handlerGroup *HandlerGroup) PutDeviceAttributeByDeviceAndRoomAndBuilding(context echo.Context) error {
-	response, err := handlerGroup.Accessors.PutDeviceAttributeByDeviceAndRoomAndBuilding(
+	store, err := handlerGroup.expanded(context)
+	if err != nil {
+		return err
+	}
+
+	response, err := store.PutDeviceAttributeByDeviceAndRoomAndBuilding(context.Request().Context(),
 		context.Param("building"),
 		context.Param("room"),
 		context.Param("device"),
 		context.Param("attribute"),
 		context.Param("value"))
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
 }
 
 func (handlerGroup *HandlerGroup) GetDeviceByBuildingAndRoomAndName(context echo.Context) error {
-	response, err := handlerGroup.Accessors.GetDeviceByBuildingAndRoomAndName(context.Param("building"), context.Param("room"), context.Param("device"))
+	store, err := handlerGroup.expanded(context)
+	if err != nil {
+		return err
+	}
+
+	response, err := store.GetDeviceByBuildingAndRoomAndName(context.Request().Context(), context.Param("building"), context.Param("room"), context.Param("device"))
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
 }
 
 func (handlerGroup *HandlerGroup) GetDevicesByRoleAndType(context echo.Context) error {
-	response, err := handlerGroup.Accessors.GetDevicesByRoleAndType(context.Param("role"), context.Param("type"), "production")
+	store, err := handlerGroup.expanded(context)
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	response, err := store.GetDevicesByRoleAndType(context.Request().Context(), context.Param("role"), context.Param("type"), "production")
+	if err != nil {
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
 }
 
 func (handlerGroup *HandlerGroup) GetStageDevicesByRoleAndType(context echo.Context) error {
-	response, err := handlerGroup.Accessors.GetDevicesByRoleAndType(context.Param("role"), context.Param("type"), "stage")
+	store, err := handlerGroup.expanded(context)
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	response, err := store.GetDevicesByRoleAndType(context.Request().Context(), context.Param("role"), context.Param("type"), "stage")
+	if err != nil {
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -104,13 +144,18 @@ func (handlerGroup *HandlerGroup) GetStageDevicesByRoleAndType(context echo.Cont
 func (handlerGroup *HandlerGroup) GetBranchDevicesByRoleAndType(context echo.Context) error {
 	branch := context.Param("branch")
 	log.Printf("Getting %v devices by role %s and type %s", branch, context.Param("role"), context.Param("type"))
-	response, err := handlerGroup.Accessors.GetDevicesByRoleAndType(context.Param("role"), context.Param("type"), branch)
+	store, err := handlerGroup.expanded(context)
+	if err != nil {
+		return err
+	}
+
+	response, err := store.GetDevicesByRoleAndType(context.Request().Context(), context.Param("role"), context.Param("type"), branch)
 	if err != nil {
 		log.Printf("[error] %s", err.Error())
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
-	log.Printf("Response: %s", response)
+	log.Printf("Response: %v", response)
 	return context.JSON(http.StatusOK, response)
 
 }
@@ -124,25 +169,62 @@ func (handlerGroup *HandlerGroup) AddDevice(context echo.Context) error {
 	err := context.Bind(&d)
 
 	if dN != d.Name {
-		return context.JSON(http.StatusBadRequest, "Parameter and device name must match!")
+		return echo.NewHTTPError(http.StatusBadRequest, "Parameter and device name must match!")
 	}
 
-	building, err := handlerGroup.Accessors.GetBuildingByShortname(buildingSN)
+	building, err := handlerGroup.Accessors.GetBuildingByShortname(context.Request().Context(), buildingSN)
 	if err != nil {
 		return err
 	}
 	d.Building = building
 
-	room, err := handlerGroup.Accessors.GetRoomByBuildingAndName(buildingSN, roomN)
+	room, err := handlerGroup.Accessors.GetRoomByBuildingAndName(context.Request().Context(), buildingSN, roomN)
 	if err != nil {
 		return err
 	}
 	d.Room = room
 
-	response, err := handlerGroup.Accessors.AddDevice(d)
+	store, err := handlerGroup.expanded(context)
+	if err != nil {
+		return err
+	}
+
+	response, err := store.AddDevice(context.Request().Context(), d)
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+// UpdateDevice replaces a device with the one in the body, adding and removing roles and power
+// states to match. Leaving roles or powerstates out of the body keeps the current ones.
+func (handlerGroup *HandlerGroup) UpdateDevice(context echo.Context) error {
+	var d structs.Device
+	err := context.Bind(&d)
+	if err != nil {
+		return err
+	}
+
+	store, err := handlerGroup.expanded(context)
+	if err != nil {
+		return err
+	}
+
+	response, err := store.UpdateDevice(context.Request().Context(), context.Param("building"), context.Param("room"), context.Param("device"), d)
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
 }
+
+// DeleteDevice deletes a device along with its roles, power states and port configurations
+func (handlerGroup *HandlerGroup) DeleteDevice(context echo.Context) error {
+	err := handlerGroup.Accessors.DeleteDevice(context.Request().Context(), context.Param("building"), context.Param("room"), context.Param("device"))
+	if err != nil {
+		return err
+	}
+
+	return context.NoContent(http.StatusNoContent)
+}
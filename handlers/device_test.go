@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo"
+)
+
+func TestDevices(t *testing.T) {
+	const d1 = "/buildings/ITB/rooms/1101/devices/D1"
+
+	tests := []handlerTest{
+		{name: "get", method: echo.GET, path: d1, status: http.StatusOK, contains: `"name":"D1"`},
+		{name: "update", method: echo.PUT, path: d1, body: `{"name":"D2","address":"ITB-1101-D2.byu.edu","type":"display","class":"SonyXBR","roles":["VideoOut"]}`, status: http.StatusOK, contains: `"roles":["VideoOut"]`},
+		{name: "update with a role that doesn't exist", method: echo.PUT, path: d1, body: `{"type":"display","class":"SonyXBR","roles":["Hologram"]}`, status: http.StatusUnprocessableEntity, contains: "Hologram"},
+		{name: "rename onto another device", method: echo.PUT, path: d1, body: `{"name":"PC1","type":"display","class":"SonyXBR"}`, status: http.StatusConflict},
+		{name: "update one that doesn't exist", method: echo.PUT, path: "/buildings/ITB/rooms/1101/devices/D9", body: `{"type":"display","class":"SonyXBR"}`, status: http.StatusNotFound},
+		{name: "delete", method: echo.DELETE, path: d1, status: http.StatusNoContent},
+		{name: "delete one that doesn't exist", method: echo.DELETE, path: "/buildings/ITB/rooms/1101/devices/D9", status: http.StatusNotFound},
+		{name: "delete from a room that doesn't exist", method: echo.DELETE, path: "/buildings/ITB/rooms/9999/devices/D1", status: http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := newTestRouter(t, func(router *echo.Echo, handlerGroup *HandlerGroup) {
+				router.GET("/buildings/:building/rooms/:room/devices/:device", handlerGroup.GetDeviceByBuildingAndRoomAndName)
+				router.PUT("/buildings/:building/rooms/:room/devices/:device", handlerGroup.UpdateDevice)
+				router.DELETE("/buildings/:building/rooms/:room/devices/:device", handlerGroup.DeleteDevice)
+			})
+
+			test.run(t, router)
+		})
+	}
+}
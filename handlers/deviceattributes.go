@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+	"github.com/labstack/echo"
+)
+
+// GetDeviceAttributes lists a device's attributes with their types
+func (handlerGroup *HandlerGroup) GetDeviceAttributes(context echo.Context) error {
+	response, err := handlerGroup.Accessors.GetDeviceAttributes(context.Request().Context(), context.Param("building"), context.Param("room"), context.Param("device"))
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+// PutDeviceAttribute sets an attribute on a device. The body is {"value": ..., "type": ...};
+// without a type, the attribute gets the one its JSON value has.
+func (handlerGroup *HandlerGroup) PutDeviceAttribute(context echo.Context) error {
+	var attribute structs.DeviceAttribute
+	err := context.Bind(&attribute)
+	if err != nil {
+		return err
+	}
+
+	if len(attribute.Name) > 0 && attribute.Name != context.Param("attribute") {
+		return echo.NewHTTPError(http.StatusBadRequest, "Endpoint parameter and json name must match!")
+	}
+	attribute.Name = context.Param("attribute")
+
+	response, err := handlerGroup.Accessors.PutDeviceAttribute(context.Request().Context(), context.Param("building"), context.Param("room"), context.Param("device"), attribute)
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+// DeleteDeviceAttribute removes an attribute from a device
+func (handlerGroup *HandlerGroup) DeleteDeviceAttribute(context echo.Context) error {
+	err := handlerGroup.Accessors.DeleteDeviceAttribute(context.Request().Context(), context.Param("building"), context.Param("room"), context.Param("device"), context.Param("attribute"))
+	if err != nil {
+		return err
+	}
+
+	return context.NoContent(http.StatusNoContent)
+}
+
+// GetDevicesByAttribute returns the devices that have an attribute; ?value= narrows them to
+// the ones where it has that value
+func (handlerGroup *HandlerGroup) GetDevicesByAttribute(context echo.Context) error {
+	store, err := handlerGroup.expanded(context)
+	if err != nil {
+		return err
+	}
+
+	response, err := store.GetDevicesByAttribute(context.Request().Context(), context.Param("attribute"), context.QueryParam("value"))
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
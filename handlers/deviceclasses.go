@@ -8,9 +8,9 @@ import (
 )
 
 func (handlerGroup *HandlerGroup) GetDeviceClasses(context echo.Context) error {
-	response, err := handlerGroup.Accessors.GetDeviceTypes()
+	response, err := handlerGroup.Accessors.GetDeviceTypes(context.Request().Context())
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -24,24 +24,24 @@ func (handlerGroup *HandlerGroup) SetDeviceTypeByID(context echo.Context) error
 	var vals SetTypeIDStruct
 	err := context.Bind(&vals)
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	deviceIDString := context.Param("deviceID")
 
 	deviceID, err := strconv.Atoi(deviceIDString)
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	err = handlerGroup.Accessors.SetDeviceTypeByID(vals.TypeID, deviceID)
+	err = handlerGroup.Accessors.SetDeviceTypeByID(context.Request().Context(), vals.TypeID, deviceID)
 	if err != nil {
-		return context.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
-	device, err := handlerGroup.Accessors.GetDeviceById(deviceID)
+	device, err := handlerGroup.Accessors.GetDeviceById(context.Request().Context(), deviceID)
 	if err != nil {
-		return context.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, device)
@@ -14,16 +14,16 @@ func (handlerGroup *HandlerGroup) AddDeviceCommand(context echo.Context) error {
 	var dc structs.DeviceCommand
 	err := context.Bind(&dc)
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 	// have to convert dc.ID to a string to compare it to a string (dcID)
 	if id != strconv.Itoa(dc.ID) {
-		return context.JSON(http.StatusBadRequest, "Endpoint parameter and json id must match!")
+		return echo.NewHTTPError(http.StatusBadRequest, "Endpoint parameter and json id must match!")
 	}
 
-	response, err := handlerGroup.Accessors.AddDeviceCommand(dc)
+	response, err := handlerGroup.Accessors.AddDeviceCommand(context.Request().Context(), dc)
 	if err != nil {
-		return context.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -14,16 +14,16 @@ func (handlerGroup *HandlerGroup) AddDevicePowerState(context echo.Context) erro
 
 	err := context.Bind(&dps)
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 	// have to convert dc.ID to a string to compare it to a string (dcID)
 	if dpsID != strconv.Itoa(dps.ID) {
-		return context.JSON(http.StatusBadRequest, "Endpoint parameter and json id must match!")
+		return echo.NewHTTPError(http.StatusBadRequest, "Endpoint parameter and json id must match!")
 	}
 
-	response, err := handlerGroup.Accessors.AddDevicePowerState(dps)
+	response, err := handlerGroup.Accessors.AddDevicePowerState(context.Request().Context(), dps)
 	if err != nil {
-		return context.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
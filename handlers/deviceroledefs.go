@@ -9,9 +9,9 @@ import (
 )
 
 func (handlerGroup *HandlerGroup) GetDeviceRoleDefs(context echo.Context) error {
-	response, err := handlerGroup.Accessors.GetDeviceRoleDefs()
+	response, err := handlerGroup.Accessors.GetDeviceRoleDefs(context.Request().Context())
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -23,15 +23,15 @@ func (handlerGroup *HandlerGroup) AddDeviceRoleDef(context echo.Context) error {
 
 	err := context.Bind(&drd)
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 	if drdName != drd.Name {
-		return context.JSON(http.StatusBadRequest, "Endpoint parameter and json name must match!")
+		return echo.NewHTTPError(http.StatusBadRequest, "Endpoint parameter and json name must match!")
 	}
 
-	response, err := handlerGroup.Accessors.AddDeviceRoleDef(drd)
+	response, err := handlerGroup.Accessors.AddDeviceRoleDef(context.Request().Context(), drd)
 	if err != nil {
-		return context.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -41,12 +41,12 @@ func (handlerGroup *HandlerGroup) GetDeviceRoleDefsById(context echo.Context) er
 
 	id, err := strconv.Atoi(context.Param("id"))
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	response, err := handlerGroup.Accessors.GetDeviceRoleDefByID(id)
+	response, err := handlerGroup.Accessors.GetDeviceRoleDefByID(context.Request().Context(), id)
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -14,16 +14,16 @@ func (handlerGroup *HandlerGroup) AddDeviceRole(context echo.Context) error {
 
 	err := context.Bind(&dr)
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 	// have to convert dc.ID to a string to compare it to a string (dcID)
 	if drID != strconv.Itoa(dr.ID) {
-		return context.JSON(http.StatusBadRequest, "Endpoint parameter and json id must match!")
+		return echo.NewHTTPError(http.StatusBadRequest, "Endpoint parameter and json id must match!")
 	}
 
-	response, err := handlerGroup.Accessors.AddDeviceRole(dr)
+	response, err := handlerGroup.Accessors.AddDeviceRole(context.Request().Context(), dr)
 	if err != nil {
-		return context.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -8,9 +8,9 @@ import (
 )
 
 func (handlerGroup *HandlerGroup) GetDeviceTypes(context echo.Context) error {
-	response, err := handlerGroup.Accessors.GetDeviceClasses()
+	response, err := handlerGroup.Accessors.GetDeviceClasses(context.Request().Context())
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -22,16 +22,141 @@ func (handlerGroup *HandlerGroup) AddDeviceType(context echo.Context) error {
 
 	err := context.Bind(&deviceType)
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 	if deviceTypeName != deviceType.Name {
-		return context.JSON(http.StatusBadRequest, "Endpoint parameter and json name must match!")
+		return echo.NewHTTPError(http.StatusBadRequest, "Endpoint parameter and json name must match!")
 	}
 
-	response, err := handlerGroup.Accessors.AddDeviceType(deviceType)
+	response, err := handlerGroup.Accessors.AddDeviceType(context.Request().Context(), deviceType)
 	if err != nil {
-		return context.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
 }
+
+// GetDeviceTypeCommands lists the commands mapped to a device type and the devices of that
+// type. The type is an entry in DeviceTypes, i.e. what devices show as their class.
+func (handlerGroup *HandlerGroup) GetDeviceTypeCommands(context echo.Context) error {
+	response, err := handlerGroup.Accessors.GetDeviceTypeCommands(context.Request().Context(), context.Param("devicetype"))
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+// AddDeviceTypeCommand maps a command to an endpoint and microservice, all given by name, for
+// every device of a type
+func (handlerGroup *HandlerGroup) AddDeviceTypeCommand(context echo.Context) error {
+	var command structs.DeviceTypeCommand
+	err := context.Bind(&command)
+	if err != nil {
+		return err
+	}
+
+	if len(command.Command) > 0 && command.Command != context.Param("command") {
+		return echo.NewHTTPError(http.StatusBadRequest, "Endpoint parameter and json command must match!")
+	}
+	command.Command = context.Param("command")
+
+	response, err := handlerGroup.Accessors.AddDeviceTypeCommand(context.Request().Context(), context.Param("devicetype"), command)
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+// DeleteDeviceTypeCommand removes a device type's mapping for a command
+func (handlerGroup *HandlerGroup) DeleteDeviceTypeCommand(context echo.Context) error {
+	err := handlerGroup.Accessors.DeleteDeviceTypeCommand(context.Request().Context(), context.Param("devicetype"), context.Param("command"))
+	if err != nil {
+		return err
+	}
+
+	return context.NoContent(http.StatusNoContent)
+}
+
+// GetDeviceTypeAttributes lists the attributes devices of a type can have, with their types,
+// ranges, enums and defaults. A type with none listed takes any attribute.
+func (handlerGroup *HandlerGroup) GetDeviceTypeAttributes(context echo.Context) error {
+	response, err := handlerGroup.Accessors.GetDeviceTypeAttributes(context.Request().Context(), context.Param("devicetype"))
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+// SetDeviceTypeAttributes replaces a device type's whole attribute schema with the list in the body
+func (handlerGroup *HandlerGroup) SetDeviceTypeAttributes(context echo.Context) error {
+	var schema []structs.DeviceTypeAttribute
+	err := context.Bind(&schema)
+	if err != nil {
+		return err
+	}
+
+	response, err := handlerGroup.Accessors.SetDeviceTypeAttributes(context.Request().Context(), context.Param("devicetype"), schema)
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+// AddDeviceTypeAttribute adds an attribute to a device type's schema
+func (handlerGroup *HandlerGroup) AddDeviceTypeAttribute(context echo.Context) error {
+	rule, err := bindDeviceTypeAttribute(context)
+	if err != nil {
+		return err
+	}
+
+	response, err := handlerGroup.Accessors.AddDeviceTypeAttribute(context.Request().Context(), context.Param("devicetype"), rule)
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+// UpdateDeviceTypeAttribute replaces the rules for an attribute in a device type's schema
+func (handlerGroup *HandlerGroup) UpdateDeviceTypeAttribute(context echo.Context) error {
+	rule, err := bindDeviceTypeAttribute(context)
+	if err != nil {
+		return err
+	}
+
+	response, err := handlerGroup.Accessors.UpdateDeviceTypeAttribute(context.Request().Context(), context.Param("devicetype"), context.Param("attribute"), rule)
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+// DeleteDeviceTypeAttribute removes an attribute from a device type's schema
+func (handlerGroup *HandlerGroup) DeleteDeviceTypeAttribute(context echo.Context) error {
+	err := handlerGroup.Accessors.DeleteDeviceTypeAttribute(context.Request().Context(), context.Param("devicetype"), context.Param("attribute"))
+	if err != nil {
+		return err
+	}
+
+	return context.NoContent(http.StatusNoContent)
+}
+
+func bindDeviceTypeAttribute(context echo.Context) (structs.DeviceTypeAttribute, error) {
+	var rule structs.DeviceTypeAttribute
+	err := context.Bind(&rule)
+	if err != nil {
+		return structs.DeviceTypeAttribute{}, err
+	}
+
+	if len(rule.Name) > 0 && rule.Name != context.Param("attribute") {
+		return structs.DeviceTypeAttribute{}, echo.NewHTTPError(http.StatusBadRequest, "Endpoint parameter and json name must match!")
+	}
+	rule.Name = context.Param("attribute")
+
+	return rule, nil
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo"
+)
+
+func TestDeviceTypeAttributes(t *testing.T) {
+	const sony = "/devices/types/SonyXBR/attributes"
+
+	tests := []handlerTest{
+		{name: "set the schema", method: echo.PUT, path: sony, body: `[{"name":"inputDelay","type":"int","enum":[0,50]},{"name":"volume","type":"int","minimum":0,"maximum":100,"default":30}]`, status: http.StatusOK, contains: `"name":"volume"`},
+		{name: "clear the schema", method: echo.PUT, path: sony, body: `[]`, status: http.StatusOK, contains: "[]"},
+		{name: "name an attribute twice", method: echo.PUT, path: sony, body: `[{"name":"inputDelay","type":"int"},{"name":"inputDelay","type":"float"}]`, status: http.StatusUnprocessableEntity, contains: "more than once"},
+		{name: "a default outside the range", method: echo.PUT, path: sony, body: `[{"name":"inputDelay","type":"int"},{"name":"volume","type":"int","maximum":100,"default":101}]`, status: http.StatusUnprocessableEntity, contains: "maximum"},
+		{name: "leave out an attribute D1 has", method: echo.PUT, path: sony, body: `[{"name":"volume","type":"int"}]`, status: http.StatusConflict, contains: "inputDelay"},
+		{name: "a type that doesn't exist", method: echo.PUT, path: "/devices/types/SonyBravia/attributes", body: `[]`, status: http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := newTestRouter(t, func(router *echo.Echo, handlerGroup *HandlerGroup) {
+				router.PUT("/devices/types/:devicetype/attributes", handlerGroup.SetDeviceTypeAttributes)
+			})
+
+			test.run(t, router)
+		})
+	}
+}
@@ -8,9 +8,9 @@ import (
 )
 
 func (handlerGroup *HandlerGroup) GetEndpoints(context echo.Context) error {
-	response, err := handlerGroup.Accessors.GetAllEndpoints()
+	response, err := handlerGroup.Accessors.GetAllEndpoints(context.Request().Context())
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -22,15 +22,15 @@ func (handlerGroup *HandlerGroup) AddEndpoint(context echo.Context) error {
 
 	err := context.Bind(&endpoint)
 	if endpointName != endpointName {
-		return context.JSON(http.StatusBadRequest, "Endpoint parameter and json name must match!")
+		return echo.NewHTTPError(http.StatusBadRequest, "Endpoint parameter and json name must match!")
 	}
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
-	response, err := handlerGroup.Accessors.AddEndpoint(endpoint)
+	response, err := handlerGroup.Accessors.AddEndpoint(context.Request().Context(), endpoint)
 	if err != nil {
-		return context.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
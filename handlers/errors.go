@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+	"github.com/labstack/echo"
+)
+
+var statuses = map[accessors.Kind]int{
+	accessors.Internal:    http.StatusInternalServerError,
+	accessors.NotFound:    http.StatusNotFound,
+	accessors.Conflict:    http.StatusConflict,
+	accessors.Validation:  http.StatusUnprocessableEntity,
+	accessors.Unavailable: http.StatusServiceUnavailable,
+}
+
+// internalMessage is what a client is told about a 500; what went wrong is only logged, since
+// it's usually the database's own error text
+const internalMessage = "Something went wrong on our end; the service's log has the details."
+
+// ErrorHandler is the router's HTTPErrorHandler. Handlers return accessor errors as they are and
+// it picks the status from their kind; an *echo.HTTPError keeps its own status. Either way the
+// body is a structs.Error.
+func ErrorHandler(err error, context echo.Context) {
+	response := structs.Error{Message: err.Error()}
+
+	if httpError, ok := err.(*echo.HTTPError); ok {
+		response.Status = httpError.Code
+		response.Kind = strings.ToLower(strings.Replace(http.StatusText(httpError.Code), " ", "_", -1))
+	} else {
+		kind := accessors.KindOf(err)
+		response.Status = statuses[kind]
+		response.Kind = kind.String()
+	}
+
+	if accessorError, ok := err.(*accessors.Error); ok {
+		response.Details = accessorError.Details
+	}
+
+	if response.Status >= http.StatusInternalServerError {
+		log.Printf("[error] %s %s: %s", context.Request().Method, context.Request().URL.Path, err)
+	}
+	if response.Status == http.StatusInternalServerError {
+		response.Message = internalMessage
+		response.Details = nil
+	}
+
+	if context.Response().Committed {
+		return
+	}
+
+	if context.Request().Method == echo.HEAD {
+		context.NoContent(response.Status)
+		return
+	}
+
+	context.JSON(response.Status, response)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+	"github.com/labstack/echo"
+)
+
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want structs.Error
+	}{
+		{
+			name: "internal",
+			err:  accessors.Errorf(accessors.Internal, "broken"),
+			want: structs.Error{Status: http.StatusInternalServerError, Kind: "internal", Message: internalMessage},
+		},
+		{
+			name: "not found",
+			err:  accessors.Errorf(accessors.NotFound, "No rooms found with that name."),
+			want: structs.Error{Status: http.StatusNotFound, Kind: "not_found", Message: "No rooms found with that name."},
+		},
+		{
+			name: "conflict with details",
+			err:  &accessors.Error{Kind: accessors.Conflict, Err: errors.New("building still has rooms"), Details: []string{"room 1101"}},
+			want: structs.Error{Status: http.StatusConflict, Kind: "conflict", Message: "building still has rooms", Details: []string{"room 1101"}},
+		},
+		{
+			name: "validation",
+			err:  accessors.Errorf(accessors.Validation, "device type: nope does not exist"),
+			want: structs.Error{Status: http.StatusUnprocessableEntity, Kind: "validation", Message: "device type: nope does not exist"},
+		},
+		{
+			name: "unavailable",
+			err:  accessors.Errorf(accessors.Unavailable, "no database"),
+			want: structs.Error{Status: http.StatusServiceUnavailable, Kind: "unavailable", Message: "no database"},
+		},
+		{
+			name: "untyped driver error",
+			err:  sql.ErrNoRows,
+			want: structs.Error{Status: http.StatusNotFound, Kind: "not_found", Message: sql.ErrNoRows.Error()},
+		},
+		{
+			name: "plain error",
+			err:  errors.New("something else"),
+			want: structs.Error{Status: http.StatusInternalServerError, Kind: "internal", Message: internalMessage},
+		},
+		{
+			name: "internal with details",
+			err:  &accessors.Error{Kind: accessors.Internal, Err: errors.New("Error 1054: Unknown column 'x' in 'field list'"), Details: []string{"SELECT x FROM Rooms"}},
+			want: structs.Error{Status: http.StatusInternalServerError, Kind: "internal", Message: internalMessage},
+		},
+		{
+			name: "echo error keeps its status",
+			err:  echo.NewHTTPError(http.StatusBadRequest, "to is required"),
+			want: structs.Error{Status: http.StatusBadRequest, Kind: "bad_request", Message: "to is required"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			context := echo.New().NewContext(httptest.NewRequest(echo.GET, "/", nil), recorder)
+
+			ErrorHandler(test.err, context)
+
+			if recorder.Code != test.want.Status {
+				t.Errorf("status = %d, want %d", recorder.Code, test.want.Status)
+			}
+
+			var got structs.Error
+			err := json.Unmarshal(recorder.Body.Bytes(), &got)
+			if err != nil {
+				t.Fatalf("body %q isn't an error: %s", recorder.Body.String(), err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("body = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestErrorHandlerKinds makes sure every Kind has a status of its own, so adding one without
+// mapping it fails here rather than turning into a 200
+func TestErrorHandlerKinds(t *testing.T) {
+	for kind := accessors.Internal; kind <= accessors.Unavailable; kind++ {
+		status, ok := statuses[kind]
+		if !ok || status < http.StatusBadRequest {
+			t.Errorf("kind %s has no error status", kind)
+		}
+		if len(kind.String()) == 0 {
+			t.Errorf("kind %d has no name", kind)
+		}
+	}
+}
@@ -1,8 +1,52 @@
 package handlers
 
-import "github.com/byuoitav/configuration-database-microservice/accessors"
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/labstack/echo"
+)
 
 // HandlerGroup holds all config information for the handlers
 type HandlerGroup struct {
-	Accessors *accessors.AccessorGroup
+	Accessors accessors.Store
+
+	// RequireApprover makes promoting a room to production take a second person's approval
+	RequireApprover bool
+}
+
+// expanded returns the accessors narrowed to the device relations the request names in
+// ?expand= (or ?fields=), e.g. ?expand=commands,ports. Without either every relation is loaded.
+func (handlerGroup *HandlerGroup) expanded(context echo.Context) (accessors.Store, error) {
+	for _, param := range []string{"expand", "fields"} {
+		values, ok := context.QueryParams()[param]
+		if !ok {
+			continue
+		}
+
+		expand, err := accessors.ParseExpand(values[0])
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		return handlerGroup.Accessors.WithExpansion(expand), nil
+	}
+
+	return handlerGroup.Accessors, nil
+}
+
+// cascade reads ?cascade=, which asks a delete to take everything that depends on what it's deleting with it
+func cascade(context echo.Context) (bool, error) {
+	value := context.QueryParam("cascade")
+	if len(value) == 0 {
+		return false, nil
+	}
+
+	cascade, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, echo.NewHTTPError(http.StatusBadRequest, "cascade must be true or false")
+	}
+
+	return cascade, nil
 }
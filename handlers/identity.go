@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo"
+)
+
+// userKey is where Identify keeps the caller's name in the request's echo.Context
+const userKey = "user"
+
+// wso2User is the claim in the WSO2 JWT assertion that names the person behind a request
+const wso2User = "http://wso2.org/claims/enduser"
+
+func init() {
+	// WSO2 names RS256 by its Java name in the assertion's header
+	jwt.RegisterSigningMethod("SHA256withRSA", func() jwt.SigningMethod {
+		return jwt.SigningMethodRS256
+	})
+}
+
+// Identify reads who is making a request from the WSO2 JWT assertion and keeps it in the
+// context for the handlers that record people, such as promotions. authmiddleware.Authenticate
+// lets requests through on a bearer token, a machine token or LOCAL_ENVIRONMENT as well as
+// through WSO2, and none of those stop a caller from sending an assertion of their own, so it's
+// only trusted if it's signed with WSO2's key. Requests with no assertion, or one that isn't
+// signed with key (or any, if key is nil), have no user.
+func Identify(key *rsa.PublicKey) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(context echo.Context) error {
+			assertion := context.Request().Header.Get("X-jwt-assertion")
+			if len(assertion) == 0 || key == nil {
+				return next(context)
+			}
+
+			token, err := jwt.Parse(assertion, func(token *jwt.Token) (interface{}, error) {
+				if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+					return nil, fmt.Errorf("the assertion is signed with %v rather than RSA", token.Header["alg"])
+				}
+
+				return key, nil
+			})
+			if err != nil {
+				log.Printf("Not trusting the JWT assertion on %s %s: %s", context.Request().Method, context.Request().URL.Path, err)
+				return next(context)
+			}
+
+			claims, _ := token.Claims.(jwt.MapClaims)
+
+			// WSO2 tacks the tenant onto the user name, e.g. jdoe@carbon.super
+			name, _ := claims[wso2User].(string)
+			name = strings.TrimSuffix(name, "@carbon.super")
+			if len(name) > 0 {
+				context.Set(userKey, name)
+			}
+
+			return next(context)
+		}
+	}
+}
+
+// user returns who is making a request, as Identify found it, or a 401 if it couldn't tell
+func user(context echo.Context) (string, error) {
+	name, _ := context.Get(userKey).(string)
+	if len(name) == 0 {
+		return "", echo.NewHTTPError(http.StatusUnauthorized, "this needs to know who you are; sign in through WSO2 rather than with a service token")
+	}
+
+	return name, nil
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo"
+)
+
+func TestIdentify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := jwt.MapClaims{wso2User: "jdoe@carbon.super"}
+	expired := jwt.MapClaims{wso2User: "jdoe@carbon.super", "exp": time.Now().Add(-time.Hour).Unix()}
+
+	sign := func(method jwt.SigningMethod, alg string, claims jwt.MapClaims, key interface{}) string {
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["alg"] = alg
+
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return signed
+	}
+
+	tests := []struct {
+		name      string
+		key       *rsa.PublicKey
+		assertion string
+		status    int
+		user      string
+	}{
+		{name: "signed by WSO2", key: &key.PublicKey, assertion: sign(jwt.SigningMethodRS256, "RS256", claims, key), status: http.StatusOK, user: "jdoe"},
+		{name: "signed by WSO2 with its own name for RS256", key: &key.PublicKey, assertion: sign(jwt.SigningMethodRS256, "SHA256withRSA", claims, key), status: http.StatusOK, user: "jdoe"},
+		{name: "no assertion", key: &key.PublicKey, status: http.StatusUnauthorized},
+		{name: "signed by someone else", key: &key.PublicKey, assertion: sign(jwt.SigningMethodRS256, "RS256", claims, other), status: http.StatusUnauthorized},
+		{name: "signed with WSO2's public key as an HMAC secret", key: &key.PublicKey, assertion: sign(jwt.SigningMethodHS256, "HS256", claims, public), status: http.StatusUnauthorized},
+		{name: "not signed", key: &key.PublicKey, assertion: "e30." + base64.RawURLEncoding.EncodeToString([]byte(`{"`+wso2User+`":"jdoe"}`)) + ".sig", status: http.StatusUnauthorized},
+		{name: "expired", key: &key.PublicKey, assertion: sign(jwt.SigningMethodRS256, "RS256", expired, key), status: http.StatusUnauthorized},
+		{name: "no key to check it with", assertion: sign(jwt.SigningMethodRS256, "RS256", claims, key), status: http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := echo.New()
+			router.HTTPErrorHandler = ErrorHandler
+			router.GET("/whoami", func(context echo.Context) error {
+				name, err := user(context)
+				if err != nil {
+					return err
+				}
+
+				return context.String(http.StatusOK, name)
+			}, Identify(test.key))
+
+			request := httptest.NewRequest(echo.GET, "/whoami", nil)
+			if len(test.assertion) > 0 {
+				request.Header.Set("X-jwt-assertion", test.assertion)
+			}
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.status {
+				t.Errorf("status = %d, want %d (%s)", recorder.Code, test.status, recorder.Body.String())
+			}
+			if test.status == http.StatusOK && recorder.Body.String() != test.user {
+				t.Errorf("user = %q, want %q", recorder.Body.String(), test.user)
+			}
+		})
+	}
+}
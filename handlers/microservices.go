@@ -8,9 +8,9 @@ import (
 )
 
 func (handlerGroup *HandlerGroup) GetMicroservices(context echo.Context) error {
-	response, err := handlerGroup.Accessors.GetMicroservices()
+	response, err := handlerGroup.Accessors.GetMicroservices(context.Request().Context())
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -22,15 +22,15 @@ func (handlerGroup *HandlerGroup) AddMicroservice(context echo.Context) error {
 
 	err := context.Bind(&ms)
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 	if msName != ms.Name {
-		return context.JSON(http.StatusBadRequest, "Endpoint parameter and json name must match!")
+		return echo.NewHTTPError(http.StatusBadRequest, "Endpoint parameter and json name must match!")
 	}
 
-	response, err := handlerGroup.Accessors.AddMicroservice(ms)
+	response, err := handlerGroup.Accessors.AddMicroservice(context.Request().Context(), ms)
 	if err != nil {
-		return context.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -14,17 +14,79 @@ func (handlerGroup *HandlerGroup) AddPortConfiguration(context echo.Context) err
 
 	err := context.Bind(&pc)
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 	// have to convert pc.ID to a string to compare it to a string (pcID)
 	if pcID != strconv.Itoa(pc.ID) {
-		return context.JSON(http.StatusBadRequest, "Endpoint parameter and json id must match!")
+		return echo.NewHTTPError(http.StatusBadRequest, "Endpoint parameter and json id must match!")
 	}
 
-	response, err := handlerGroup.Accessors.AddPortConfiguration(pc)
+	response, err := handlerGroup.Accessors.AddPortConfiguration(context.Request().Context(), pc)
 	if err != nil {
-		return context.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
 }
+
+// GetDevicePorts lists the configured ports a device hosts
+func (handlerGroup *HandlerGroup) GetDevicePorts(context echo.Context) error {
+	response, err := handlerGroup.Accessors.GetDevicePortsByBuildingAndRoomAndName(context.Request().Context(), context.Param("building"), context.Param("room"), context.Param("device"))
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+// AddDevicePort configures one of a device's ports. The body names the source and destination
+// devices, which have to be in the same room.
+func (handlerGroup *HandlerGroup) AddDevicePort(context echo.Context) error {
+	var port structs.Port
+	err := context.Bind(&port)
+	if err != nil {
+		return err
+	}
+
+	if len(port.Name) > 0 && port.Name != context.Param("port") {
+		return echo.NewHTTPError(http.StatusBadRequest, "Parameter and port name must match!")
+	}
+	port.Name = context.Param("port")
+
+	response, err := handlerGroup.Accessors.AddDevicePort(context.Request().Context(), context.Param("building"), context.Param("room"), context.Param("device"), port)
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+// UpdateDevicePort changes the source and destination of a configured port
+func (handlerGroup *HandlerGroup) UpdateDevicePort(context echo.Context) error {
+	var port structs.Port
+	err := context.Bind(&port)
+	if err != nil {
+		return err
+	}
+
+	if len(port.Name) > 0 && port.Name != context.Param("port") {
+		return echo.NewHTTPError(http.StatusBadRequest, "Parameter and port name must match!")
+	}
+
+	response, err := handlerGroup.Accessors.UpdateDevicePort(context.Request().Context(), context.Param("building"), context.Param("room"), context.Param("device"), context.Param("port"), port)
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+// DeleteDevicePort removes a port's configuration
+func (handlerGroup *HandlerGroup) DeleteDevicePort(context echo.Context) error {
+	err := handlerGroup.Accessors.DeleteDevicePort(context.Request().Context(), context.Param("building"), context.Param("room"), context.Param("device"), context.Param("port"))
+	if err != nil {
+		return err
+	}
+
+	return context.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors/memory"
+	"github.com/labstack/echo"
+)
+
+// handlerTest is a request to make against a fresh copy of docs/fixture.json, and what should
+// come back
+type handlerTest struct {
+	name   string
+	method string
+	path   string
+	body   string
+
+	status int
+	// contains is something the response body has to have in it, if it's set
+	contains string
+}
+
+// newTestRouter seeds an in-memory store from docs/fixture.json and routes to the handlers
+// using it. In the fixture ITB-1101 has D1 (a SonyXBR, whose type has ports hdmi1 and hdmi2),
+// PC1 and CP1, and D1's hdmi1 is configured from PC1.
+func newTestRouter(t *testing.T, routes func(router *echo.Echo, handlerGroup *HandlerGroup)) *echo.Echo {
+	store := memory.New()
+
+	err := store.LoadFile("../docs/fixture.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := echo.New()
+	router.HTTPErrorHandler = ErrorHandler
+	routes(router, &HandlerGroup{Accessors: store})
+
+	return router
+}
+
+func (test handlerTest) run(t *testing.T, router *echo.Echo) {
+	request := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+	if len(test.body) > 0 {
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != test.status {
+		t.Errorf("%s %s: status = %d, want %d (%s)", test.method, test.path, recorder.Code, test.status, recorder.Body.String())
+	}
+	if !strings.Contains(recorder.Body.String(), test.contains) {
+		t.Errorf("%s %s: body %s doesn't have %q in it", test.method, test.path, recorder.Body.String(), test.contains)
+	}
+}
+
+func TestDevicePorts(t *testing.T) {
+	const d1 = "/buildings/ITB/rooms/1101/devices/D1/ports"
+
+	tests := []handlerTest{
+		{name: "list", method: echo.GET, path: d1, status: http.StatusOK, contains: `"name":"hdmi1"`},
+		{name: "list with none configured", method: echo.GET, path: "/buildings/ITB/rooms/1101/devices/PC1/ports", status: http.StatusOK, contains: "[]"},
+		{name: "list for a device that doesn't exist", method: echo.GET, path: "/buildings/ITB/rooms/1101/devices/D9/ports", status: http.StatusNotFound},
+		{name: "add", method: echo.POST, path: d1 + "/hdmi2", body: `{"source":"CP1","destination":"D1"}`, status: http.StatusOK, contains: `"host":"D1"`},
+		{name: "add one that's configured", method: echo.POST, path: d1 + "/hdmi1", body: `{"source":"CP1","destination":"D1"}`, status: http.StatusConflict},
+		{name: "add one the type doesn't have", method: echo.POST, path: d1 + "/hdmi3", body: `{"source":"CP1","destination":"D1"}`, status: http.StatusUnprocessableEntity},
+		{name: "add from a device not in the room", method: echo.POST, path: d1 + "/hdmi2", body: `{"source":"PC9","destination":"D1"}`, status: http.StatusUnprocessableEntity, contains: "PC9"},
+		{name: "add to a device that doesn't exist", method: echo.POST, path: "/buildings/ITB/rooms/1101/devices/D9/ports/hdmi2", body: `{"source":"CP1","destination":"D1"}`, status: http.StatusNotFound},
+		{name: "add with another name in the body", method: echo.POST, path: d1 + "/hdmi2", body: `{"name":"hdmi1","source":"CP1","destination":"D1"}`, status: http.StatusBadRequest},
+		{name: "update", method: echo.PUT, path: d1 + "/hdmi1", body: `{"source":"CP1","destination":"D1"}`, status: http.StatusOK, contains: `"source":"CP1"`},
+		{name: "update one that isn't configured", method: echo.PUT, path: d1 + "/hdmi2", body: `{"source":"CP1","destination":"D1"}`, status: http.StatusNotFound},
+		{name: "delete", method: echo.DELETE, path: d1 + "/hdmi1", status: http.StatusNoContent},
+		{name: "delete one that isn't configured", method: echo.DELETE, path: d1 + "/hdmi2", status: http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := newTestRouter(t, func(router *echo.Echo, handlerGroup *HandlerGroup) {
+				router.GET("/buildings/:building/rooms/:room/devices/:device/ports", handlerGroup.GetDevicePorts)
+				router.POST("/buildings/:building/rooms/:room/devices/:device/ports/:port", handlerGroup.AddDevicePort)
+				router.PUT("/buildings/:building/rooms/:room/devices/:device/ports/:port", handlerGroup.UpdateDevicePort)
+				router.DELETE("/buildings/:building/rooms/:room/devices/:device/ports/:port", handlerGroup.DeleteDevicePort)
+			})
+
+			test.run(t, router)
+		})
+	}
+}
@@ -13,33 +13,93 @@ func (handlerGroup *HandlerGroup) AddPort(context echo.Context) error {
 
 	err := context.Bind(&portToAdd)
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 	if portName != portToAdd.Name {
-		return context.JSON(http.StatusBadRequest, "Endpoint parameter and json name must match!")
+		return echo.NewHTTPError(http.StatusBadRequest, "Endpoint parameter and json name must match!")
 	}
 
-	response, err := handlerGroup.Accessors.AddPort(portToAdd)
+	response, err := handlerGroup.Accessors.AddPort(context.Request().Context(), portToAdd)
 	if err != nil {
-		return context.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
 }
 
 func (handlerGroup *HandlerGroup) GetPorts(context echo.Context) error {
-	response, err := handlerGroup.Accessors.GetAllPorts()
+	response, err := handlerGroup.Accessors.GetAllPorts(context.Request().Context())
 	if err != nil {
-		return context.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 	return context.JSON(http.StatusOK, response)
 }
 
 func (handlerGroup *HandlerGroup) GetPortsByDeviceType(context echo.Context) error {
 
-	response, err := handlerGroup.Accessors.GetPortsByDeviceTypeName(context.Param("class"))
+	response, err := handlerGroup.Accessors.GetPortsByDeviceTypeName(context.Request().Context(), context.Param("class"))
 	if err != nil {
-		return context.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 	return context.JSON(http.StatusOK, response)
 }
+
+// GetDeviceTypePorts lists the ports defined on a device type
+func (handlerGroup *HandlerGroup) GetDeviceTypePorts(context echo.Context) error {
+	response, err := handlerGroup.Accessors.GetPortsByDeviceTypeName(context.Request().Context(), context.Param("devicetype"))
+	if err != nil {
+		return err
+	}
+	return context.JSON(http.StatusOK, response)
+}
+
+// AddDeviceTypePort defines a port on a device type. The port has to be in the Ports table.
+func (handlerGroup *HandlerGroup) AddDeviceTypePort(context echo.Context) error {
+	var dtp structs.DeviceTypePort
+
+	err := context.Bind(&dtp)
+	if err != nil {
+		return err
+	}
+	if len(dtp.Port.Name) > 0 && dtp.Port.Name != context.Param("port") {
+		return echo.NewHTTPError(http.StatusBadRequest, "Endpoint parameter and json port name must match!")
+	}
+	dtp.Port.Name = context.Param("port")
+
+	response, err := handlerGroup.Accessors.AddDeviceTypePort(context.Request().Context(), context.Param("devicetype"), dtp)
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+// UpdateDeviceTypePort replaces a device type port's description, friendly name and mirroring
+func (handlerGroup *HandlerGroup) UpdateDeviceTypePort(context echo.Context) error {
+	var dtp structs.DeviceTypePort
+
+	err := context.Bind(&dtp)
+	if err != nil {
+		return err
+	}
+	if len(dtp.Port.Name) > 0 && dtp.Port.Name != context.Param("port") {
+		return echo.NewHTTPError(http.StatusBadRequest, "Endpoint parameter and json port name must match!")
+	}
+
+	response, err := handlerGroup.Accessors.UpdateDeviceTypePort(context.Request().Context(), context.Param("devicetype"), context.Param("port"), dtp)
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+// DeleteDeviceTypePort removes a port from a device type
+func (handlerGroup *HandlerGroup) DeleteDeviceTypePort(context echo.Context) error {
+	err := handlerGroup.Accessors.DeleteDeviceTypePort(context.Request().Context(), context.Param("devicetype"), context.Param("port"))
+	if err != nil {
+		return err
+	}
+
+	return context.NoContent(http.StatusNoContent)
+}
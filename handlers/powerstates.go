@@ -8,9 +8,9 @@ import (
 )
 
 func (handlerGroup *HandlerGroup) GetPowerStates(context echo.Context) error {
-	response, err := handlerGroup.Accessors.GetPowerStates()
+	response, err := handlerGroup.Accessors.GetPowerStates(context.Request().Context())
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -22,15 +22,15 @@ func (handlerGroup *HandlerGroup) AddPowerState(context echo.Context) error {
 
 	err := context.Bind(&ps)
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 	if psName != ps.Name {
-		return context.JSON(http.StatusBadRequest, "Endpoint parameter and json name must match!")
+		return echo.NewHTTPError(http.StatusBadRequest, "Endpoint parameter and json name must match!")
 	}
 
-	response, err := handlerGroup.Accessors.AddPowerState(ps)
+	response, err := handlerGroup.Accessors.AddPowerState(context.Request().Context(), ps)
 	if err != nil {
-		return context.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+	"github.com/byuoitav/configuration-database-microservice/validation"
+	"github.com/labstack/echo"
+)
+
+// GetRoomPromotions lists the times a room has been moved between designations, or asked to be,
+// oldest first
+func (handlerGroup *HandlerGroup) GetRoomPromotions(context echo.Context) error {
+	promotions, err := handlerGroup.Accessors.GetRoomPromotions(context.Request().Context(), context.Param("building"), context.Param("room"))
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, promotions)
+}
+
+// PromoteRoom moves a room to another designation (?to=production) on behalf of whoever is
+// signed in. A room only goes to production if validation finds no errors in it, checked in the
+// same transaction that moves it. When RequireApprover is set, a promotion to production only
+// asks: it's left pending, and the room stays put, until someone else approves it.
+func (handlerGroup *HandlerGroup) PromoteRoom(context echo.Context) error {
+	ctx := context.Request().Context()
+	building, roomName := context.Param("building"), context.Param("room")
+
+	promotedBy, err := user(context)
+	if err != nil {
+		return err
+	}
+
+	to := strings.TrimSpace(context.QueryParam("to"))
+	if len(to) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "to is required, e.g. ?to=production")
+	}
+	if accessors.IsProduction(to) {
+		to = accessors.Production
+	}
+
+	promotion := structs.Promotion{To: to, PromotedBy: promotedBy}
+
+	if to == accessors.Production && handlerGroup.RequireApprover {
+		var requested structs.Promotion
+		err = handlerGroup.Accessors.Transaction(ctx, func(tx accessors.Store) error {
+			requested, err = tx.RequestPromotion(ctx, building, roomName, promotion)
+			if err != nil {
+				return err
+			}
+
+			// the room isn't moved until it's approved, when it's checked again, but there's
+			// no sense asking for approval of one that isn't ready
+			return checkReady(ctx, tx, building, roomName)
+		})
+		if err != nil {
+			return err
+		}
+
+		return context.JSON(http.StatusAccepted, requested)
+	}
+
+	var promoted structs.Promotion
+	err = handlerGroup.Accessors.Transaction(ctx, func(tx accessors.Store) error {
+		promoted, err = tx.PromoteRoom(ctx, building, roomName, promotion)
+		if err != nil || to != accessors.Production {
+			return err
+		}
+
+		return checkReady(ctx, tx, building, roomName)
+	})
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, promoted)
+}
+
+// ApprovePromotion approves a pending promotion on behalf of whoever is signed in, who can't be
+// the person who asked for it, and moves the room. The room is checked again in the same
+// transaction, so anything that broke it since it was asked for stops it.
+func (handlerGroup *HandlerGroup) ApprovePromotion(context echo.Context) error {
+	ctx := context.Request().Context()
+	building, roomName := context.Param("building"), context.Param("room")
+
+	approvedBy, err := user(context)
+	if err != nil {
+		return err
+	}
+
+	id, err := promotionID(context)
+	if err != nil {
+		return err
+	}
+
+	var approved structs.Promotion
+	err = handlerGroup.Accessors.Transaction(ctx, func(tx accessors.Store) error {
+		approved, err = tx.ApprovePromotion(ctx, building, roomName, id, approvedBy)
+		if err != nil || !accessors.IsProduction(approved.To) {
+			return err
+		}
+
+		return checkReady(ctx, tx, building, roomName)
+	})
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, approved)
+}
+
+// WithdrawPromotion drops a pending promotion, leaving the room where it is
+func (handlerGroup *HandlerGroup) WithdrawPromotion(context echo.Context) error {
+	_, err := user(context)
+	if err != nil {
+		return err
+	}
+
+	id, err := promotionID(context)
+	if err != nil {
+		return err
+	}
+
+	withdrawn, err := handlerGroup.Accessors.WithdrawPromotion(context.Request().Context(), context.Param("building"), context.Param("room"), id)
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, withdrawn)
+}
+
+// checkReady runs validation on a room that's going to production, returning a Conflict that
+// lists the errors it finds
+func checkReady(ctx context.Context, store accessors.Store, building string, roomName string) error {
+	findings, err := validation.Room(ctx, store, building, roomName)
+	if err != nil {
+		return err
+	}
+
+	problems := []string{}
+	for _, finding := range findings {
+		if finding.Severity == structs.SeverityError {
+			problems = append(problems, fmt.Sprintf("%s: %s", finding.Check, finding.Message))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &accessors.Error{
+			Kind:    accessors.Conflict,
+			Err:     fmt.Errorf("%s-%s isn't ready for production; fix these first (GET /buildings/%s/rooms/%s/validate lists them too)", building, roomName, building, roomName),
+			Details: problems,
+		}
+	}
+
+	return nil
+}
+
+func promotionID(context echo.Context) (int, error) {
+	id, err := strconv.Atoi(context.Param("promotion"))
+	if err != nil {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "the promotion has to be a number")
+	}
+
+	return id, nil
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo"
+)
+
+// signedInAs stands in for Identify, as if name's WSO2 assertion had checked out
+func signedInAs(name string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(context echo.Context) error {
+			context.Set(userKey, name)
+			return next(context)
+		}
+	}
+}
+
+func TestPromotions(t *testing.T) {
+	tests := []handlerTest{
+		{name: "add straight to production", method: echo.POST, path: "/buildings/ITB/rooms/1102", body: `{"name":"1102","configurationID":1,"roomDesignation":" Production"}`, status: http.StatusConflict, contains: "promote"},
+		{name: "update to production", method: echo.PUT, path: "/buildings/ITB/rooms/1001D", body: `{"roomDesignation":"PRODUCTION"}`, status: http.StatusConflict, contains: "promote"},
+		{name: "promote", method: echo.POST, path: "/buildings/ITB/rooms/1101/promote?to=stage", status: http.StatusOK, contains: `"promotedBy":"alice"`},
+		{name: "promote to where it is", method: echo.POST, path: "/buildings/ITB/rooms/1101/promote?to=Production", status: http.StatusConflict},
+		{name: "promote without saying where", method: echo.POST, path: "/buildings/ITB/rooms/1001D/promote", status: http.StatusBadRequest},
+		{name: "promote one that isn't ready", method: echo.POST, path: "/buildings/ITB/rooms/1001D/promote?to=production", status: http.StatusConflict, contains: "room-role"},
+		{name: "list", method: echo.GET, path: "/buildings/ITB/rooms/1101/promotions", status: http.StatusOK, contains: "[]"},
+		{name: "approve one that doesn't exist", method: echo.POST, path: "/buildings/ITB/rooms/1101/promotions/9/approve", status: http.StatusNotFound},
+		{name: "approve with a bad ID", method: echo.POST, path: "/buildings/ITB/rooms/1101/promotions/nine/approve", status: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := newTestRouter(t, func(router *echo.Echo, handlerGroup *HandlerGroup) {
+				signedIn := router.Group("", signedInAs("alice"))
+				signedIn.POST("/buildings/:building/rooms/:room", handlerGroup.AddRoom)
+				signedIn.PUT("/buildings/:building/rooms/:room", handlerGroup.UpdateRoom)
+				signedIn.GET("/buildings/:building/rooms/:room/promotions", handlerGroup.GetRoomPromotions)
+				signedIn.POST("/buildings/:building/rooms/:room/promote", handlerGroup.PromoteRoom)
+				signedIn.POST("/buildings/:building/rooms/:room/promotions/:promotion/approve", handlerGroup.ApprovePromotion)
+			})
+
+			test.run(t, router)
+		})
+	}
+}
+
+// TestPromotionsNeedSomeone makes sure nothing about a promotion changes without a verified
+// person to put it down to
+func TestPromotionsNeedSomeone(t *testing.T) {
+	tests := []handlerTest{
+		{name: "promote", method: echo.POST, path: "/buildings/ITB/rooms/1101/promote?to=stage", status: http.StatusUnauthorized},
+		{name: "approve", method: echo.POST, path: "/buildings/ITB/rooms/1101/promotions/1/approve", status: http.StatusUnauthorized},
+		{name: "withdraw", method: echo.DELETE, path: "/buildings/ITB/rooms/1101/promotions/1", status: http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := newTestRouter(t, func(router *echo.Echo, handlerGroup *HandlerGroup) {
+				handlerGroup.RequireApprover = true
+				router.POST("/buildings/:building/rooms/:room/promote", handlerGroup.PromoteRoom)
+				router.POST("/buildings/:building/rooms/:room/promotions/:promotion/approve", handlerGroup.ApprovePromotion)
+				router.DELETE("/buildings/:building/rooms/:room/promotions/:promotion", handlerGroup.WithdrawPromotion)
+			})
+
+			test.run(t, router)
+		})
+	}
+}
@@ -1,28 +1,35 @@
 package handlers
 
 import (
+	"bytes"
 	"log"
 	"net/http"
 	"strconv"
 
 	"github.com/byuoitav/configuration-database-microservice/structs"
+	"github.com/byuoitav/configuration-database-microservice/validation"
 	"github.com/labstack/echo"
 )
 
 //GetAllRooms gets all rooms
 func (handlerGroup *HandlerGroup) GetAllRooms(context echo.Context) error {
-	response, err := handlerGroup.Accessors.GetAllRooms()
+	store, err := handlerGroup.expanded(context)
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	response, err := store.GetAllRooms(context.Request().Context())
+	if err != nil {
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
 }
 
 func (handlerGroup *HandlerGroup) GetAllRoomDesignations(context echo.Context) error {
-	response, err := handlerGroup.Accessors.GetAllRoomDesignations()
+	response, err := handlerGroup.Accessors.GetAllRoomDesignations(context.Request().Context())
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -32,14 +39,19 @@ func (handlerGroup *HandlerGroup) GetAllRoomDesignations(context echo.Context) e
 func (handlerGroup *HandlerGroup) GetRoomByID(context echo.Context) error {
 	id, err := strconv.Atoi(context.Param("id"))
 	if err != nil {
-		return err
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	log.Printf("[handlers] searching for room with ID: %s", id)
+	log.Printf("[handlers] searching for room with ID: %d", id)
+
+	store, err := handlerGroup.expanded(context)
+	if err != nil {
+		return err
+	}
 
-	response, err := handlerGroup.Accessors.GetRoomByID(id)
+	response, err := store.GetRoomByID(context.Request().Context(), id)
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -50,9 +62,14 @@ func (handlerGroup *HandlerGroup) GetRoomsByBuilding(context echo.Context) error
 	building := context.Param("building")
 
 	log.Printf("calling Accessors.GetRoomsByBuilding")
-	response, err := handlerGroup.Accessors.GetRoomsByBuilding(building)
+	store, err := handlerGroup.expanded(context)
+	if err != nil {
+		return err
+	}
+
+	response, err := store.GetRoomsByBuilding(context.Request().Context(), building)
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -62,17 +79,22 @@ func (handlerGroup *HandlerGroup) GetDevicesByRoomIdAndRoleId(context echo.Conte
 
 	roomId, err := strconv.Atoi(context.Param("roomId"))
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, "invalid room id")
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid room id")
 	}
 
 	roleId, err := strconv.Atoi(context.Param("roleId"))
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, "invalid role id")
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid role id")
 	}
 
-	devices, err := handlerGroup.Accessors.GetDevicesByRoomIdAndRoleId(roomId, roleId)
+	store, err := handlerGroup.expanded(context)
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	devices, err := store.GetDevicesByRoomIdAndRoleId(context.Request().Context(), roomId, roleId)
+	if err != nil {
+		return err
 	}
 
 	return context.JSON(http.StatusOK, devices)
@@ -82,12 +104,17 @@ func (handlerGroup *HandlerGroup) GetDevicesByRoomId(context echo.Context) error
 
 	roomId, err := strconv.Atoi(context.Param("roomId"))
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	devices, err := handlerGroup.Accessors.GetDevicesByRoomId(roomId)
+	store, err := handlerGroup.expanded(context)
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	devices, err := store.GetDevicesByRoomId(context.Request().Context(), roomId)
+	if err != nil {
+		return err
 	}
 
 	return context.JSON(http.StatusOK, devices)
@@ -95,9 +122,14 @@ func (handlerGroup *HandlerGroup) GetDevicesByRoomId(context echo.Context) error
 
 //GetRoomByBuildingAndName returns the room by building and name
 func (handlerGroup *HandlerGroup) GetRoomByBuildingAndName(context echo.Context) error {
-	response, err := handlerGroup.Accessors.GetRoomByBuildingAndName(context.Param("building"), context.Param("room"))
+	store, err := handlerGroup.expanded(context)
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	response, err := store.GetRoomByBuildingAndName(context.Request().Context(), context.Param("building"), context.Param("room"))
+	if err != nil {
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -107,10 +139,10 @@ func (handlerGroup *HandlerGroup) GetRoomByBuildingAndName(context echo.Context)
 func (handlerGroup *HandlerGroup) GetConfigurationByName(context echo.Context) error {
 	name := context.Param("configuration")
 
-	response, err := handlerGroup.Accessors.GetConfigurationByConfigurationName(name)
+	response, err := handlerGroup.Accessors.GetConfigurationByConfigurationName(context.Request().Context(), name)
 
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -118,9 +150,9 @@ func (handlerGroup *HandlerGroup) GetConfigurationByName(context echo.Context) e
 
 //
 func (handlerGroup *HandlerGroup) GetConfigurations(context echo.Context) error {
-	response, err := handlerGroup.Accessors.GetConfigurations()
+	response, err := handlerGroup.Accessors.GetConfigurations(context.Request().Context())
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -131,10 +163,10 @@ func (handlerGroup *HandlerGroup) GetConfigurationByRoomAndBuilding(context echo
 	building := context.Param("building")
 	room := context.Param("room")
 
-	response, err := handlerGroup.Accessors.GetConfigurationByRoomAndBuilding(building, room)
+	response, err := handlerGroup.Accessors.GetConfigurationByRoomAndBuilding(context.Request().Context(), building, room)
 
 	if err != nil {
-		return context.String(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
@@ -147,15 +179,97 @@ func (handlerGroup *HandlerGroup) AddRoom(context echo.Context) error {
 	err := context.Bind(&roomToAdd)
 
 	if roomN != roomToAdd.Name {
-		return context.JSON(http.StatusBadRequest, "Parameter and room name must match!")
+		return echo.NewHTTPError(http.StatusBadRequest, "Parameter and room name must match!")
 	}
 
 	roomToAdd.Name = roomN
 
-	response, err := handlerGroup.Accessors.AddRoom(buildingSN, roomToAdd)
+	store, err := handlerGroup.expanded(context)
+	if err != nil {
+		return err
+	}
+
+	response, err := store.AddRoom(context.Request().Context(), buildingSN, roomToAdd)
 	if err != nil {
-		return context.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return context.JSON(http.StatusOK, response)
 }
+
+//UpdateRoom changes a room's description, configuration and designation, or renames it. Fields
+//left empty in the body keep their current values, except the description.
+func (handlerGroup *HandlerGroup) UpdateRoom(context echo.Context) error {
+	var room structs.Room
+	err := context.Bind(&room)
+	if err != nil {
+		return err
+	}
+
+	store, err := handlerGroup.expanded(context)
+	if err != nil {
+		return err
+	}
+
+	response, err := store.UpdateRoom(context.Request().Context(), context.Param("building"), context.Param("room"), room)
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, response)
+}
+
+//DeleteRoom deletes a room. It refuses while devices are in the room, unless ?cascade=true,
+//which deletes them too, along with their roles, power states and port configurations.
+func (handlerGroup *HandlerGroup) DeleteRoom(context echo.Context) error {
+	cascade, err := cascade(context)
+	if err != nil {
+		return err
+	}
+
+	err = handlerGroup.Accessors.DeleteRoom(context.Request().Context(), context.Param("building"), context.Param("room"), cascade)
+	if err != nil {
+		return err
+	}
+
+	return context.NoContent(http.StatusNoContent)
+}
+
+// ValidateRoom runs every check on a room and lists what they found: devices the AV API can't
+// drive, missing roles, a missing or ambiguous configuration, and the wiring problems
+// ValidateRoomWiring reports. An empty list means the room is ready for production.
+func (handlerGroup *HandlerGroup) ValidateRoom(context echo.Context) error {
+	findings, err := validation.Room(context.Request().Context(), handlerGroup.Accessors, context.Param("building"), context.Param("room"))
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, findings)
+}
+
+// ValidateAllRooms runs every check on every room, or on the rooms with the given
+// ?roomDesignation=, and reports what they found grouped by building and severity. ?format=csv
+// returns one finding per line instead.
+func (handlerGroup *HandlerGroup) ValidateAllRooms(context echo.Context) error {
+	format := context.QueryParam("format")
+	if len(format) > 0 && format != "json" && format != "csv" {
+		return echo.NewHTTPError(http.StatusBadRequest, "format must be json or csv")
+	}
+
+	report, err := validation.Lint(context.Request().Context(), handlerGroup.Accessors, context.QueryParam("roomDesignation"))
+	if err != nil {
+		return err
+	}
+
+	if format != "csv" {
+		return context.JSON(http.StatusOK, report)
+	}
+
+	var body bytes.Buffer
+	err = validation.WriteLintCSV(&body, report)
+	if err != nil {
+		return err
+	}
+
+	return context.Blob(http.StatusOK, "text/csv; charset=utf-8", body.Bytes())
+}
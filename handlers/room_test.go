@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo"
+)
+
+func TestRooms(t *testing.T) {
+	tests := []handlerTest{
+		{name: "get with its devices", method: echo.GET, path: "/buildings/ITB/rooms/1101", status: http.StatusOK, contains: `"name":"PC1"`},
+		{name: "get one that doesn't exist", method: echo.GET, path: "/buildings/ITB/rooms/9999", status: http.StatusNotFound},
+		{name: "list a building's", method: echo.GET, path: "/buildings/ITB/rooms", status: http.StatusOK, contains: `"name":"1001D"`},
+		{name: "add", method: echo.POST, path: "/buildings/ITB/rooms/1102", body: `{"name":"1102","configurationID":1,"roomDesignation":"stage"}`, status: http.StatusOK, contains: `"name":"1102"`},
+		{name: "add one that exists", method: echo.POST, path: "/buildings/ITB/rooms/1101", body: `{"name":"1101","configurationID":1,"roomDesignation":"stage"}`, status: http.StatusConflict},
+		{name: "add straight to production", method: echo.POST, path: "/buildings/ITB/rooms/1102", body: `{"name":"1102","configurationID":1,"roomDesignation":" Production"}`, status: http.StatusConflict, contains: "promote"},
+		{name: "add with another name in the body", method: echo.POST, path: "/buildings/ITB/rooms/1102", body: `{"name":"1103"}`, status: http.StatusBadRequest},
+		{name: "rename", method: echo.PUT, path: "/buildings/ITB/rooms/1001D", body: `{"name":"1001E"}`, status: http.StatusOK, contains: `"name":"1001E"`},
+		{name: "rename onto a room that exists", method: echo.PUT, path: "/buildings/ITB/rooms/1001D", body: `{"name":"1101"}`, status: http.StatusConflict},
+		{name: "update one that doesn't exist", method: echo.PUT, path: "/buildings/ITB/rooms/9999", body: `{"name":"9998"}`, status: http.StatusNotFound},
+		{name: "delete with devices in it", method: echo.DELETE, path: "/buildings/ITB/rooms/1101", status: http.StatusConflict, contains: "ITB-1101-PC1"},
+		{name: "delete with devices in it, cascading", method: echo.DELETE, path: "/buildings/ITB/rooms/1101?cascade=true", status: http.StatusNoContent},
+		{name: "delete one that doesn't exist", method: echo.DELETE, path: "/buildings/ITB/rooms/9999", status: http.StatusNotFound},
+		{name: "validate", method: echo.GET, path: "/buildings/ITB/rooms/1001D/validate", status: http.StatusOK, contains: `"check":"room-role"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := newTestRouter(t, func(router *echo.Echo, handlerGroup *HandlerGroup) {
+				router.GET("/buildings/:building/rooms", handlerGroup.GetRoomsByBuilding)
+				router.GET("/buildings/:building/rooms/:room", handlerGroup.GetRoomByBuildingAndName)
+				router.POST("/buildings/:building/rooms/:room", handlerGroup.AddRoom)
+				router.PUT("/buildings/:building/rooms/:room", handlerGroup.UpdateRoom)
+				router.DELETE("/buildings/:building/rooms/:room", handlerGroup.DeleteRoom)
+				router.GET("/buildings/:building/rooms/:room/validate", handlerGroup.ValidateRoom)
+			})
+
+			test.run(t, router)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+// Timeout puts a deadline on each request's context, so the queries it runs are cancelled
+// once it passes. routes overrides fallback for individual routes, keyed by the path they were
+// registered with (e.g. "/buildings/:building/rooms/:room"). A zero timeout means no deadline.
+func Timeout(fallback time.Duration, routes map[string]time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			timeout, ok := routes[c.Path()]
+			if !ok {
+				timeout = fallback
+			}
+
+			if timeout <= 0 {
+				return next(c)
+			}
+
+			ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+			defer cancel()
+
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+	"github.com/byuoitav/configuration-database-microservice/validation"
+	"github.com/byuoitav/configuration-database-microservice/wiring"
+	"github.com/labstack/echo"
+)
+
+// GetRoomPaths lists the routes a signal can take from one device in a room to another
+// (?from=PC1&to=D1), with the port each host along the way has to select. If there are more
+// than wiring.MaxPaths, it returns the shortest and sets truncated.
+func (handlerGroup *HandlerGroup) GetRoomPaths(context echo.Context) error {
+	from, to := context.QueryParam("from"), context.QueryParam("to")
+	if len(from) == 0 || len(to) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "both from and to are required")
+	}
+
+	ctx := context.Request().Context()
+	building, roomName := context.Param("building"), context.Param("room")
+	store := handlerGroup.Accessors.WithExpansion(accessors.Expand{})
+
+	room, err := store.GetRoomByBuildingAndName(ctx, building, roomName)
+	if err != nil {
+		return err
+	}
+
+	// the room's devices and its port configuration, rather than the devices' ports, so that
+	// devices without roles are still in the graph
+	devices, err := store.GetDevicesByRoomId(ctx, room.ID)
+	if err != nil {
+		return err
+	}
+
+	ports, err := store.GetRoomPortConfiguration(ctx, building, roomName)
+	if err != nil {
+		return err
+	}
+
+	graph := wiring.Room{Name: building + "-" + roomName, Devices: devices, Ports: ports}.Graph()
+	for _, device := range []string{from, to} {
+		if !graph.Has(device) {
+			return accessors.Errorf(accessors.NotFound, "there is no device called %s in %s-%s", device, building, roomName)
+		}
+	}
+
+	paths, truncated := graph.Paths(from, to)
+
+	return context.JSON(http.StatusOK, structs.SignalPaths{Paths: paths, Truncated: truncated})
+}
+
+// ValidateRoomWiring checks a room's port configuration against itself and its devices' types,
+// listing what's wrong with it: ports that point at nothing or into another room, ports a host
+// doesn't have, loops, and inputs that can't reach an output
+func (handlerGroup *HandlerGroup) ValidateRoomWiring(context echo.Context) error {
+	ctx := context.Request().Context()
+	building, roomName := context.Param("building"), context.Param("room")
+
+	room, err := handlerGroup.Accessors.WithExpansion(accessors.Expand{Roles: true}).GetRoomByBuildingAndName(ctx, building, roomName)
+	if err != nil {
+		return err
+	}
+
+	findings, err := validation.Wiring(ctx, handlerGroup.Accessors, building, roomName, room.Devices)
+	if err != nil {
+		return err
+	}
+
+	return context.JSON(http.StatusOK, findings)
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo"
+)
+
+func TestRoomPaths(t *testing.T) {
+	const paths = "/buildings/ITB/rooms/1101/paths"
+
+	tests := []handlerTest{
+		{name: "a route", method: echo.GET, path: paths + "?from=PC1&to=D1", status: http.StatusOK, contains: `"devices":["PC1","D1"]`},
+		{name: "all of them", method: echo.GET, path: paths + "?from=PC1&to=D1", status: http.StatusOK, contains: `"truncated":false`},
+		{name: "no route", method: echo.GET, path: paths + "?from=D1&to=PC1", status: http.StatusOK, contains: `{"paths":[],"truncated":false}`},
+		{name: "without a destination", method: echo.GET, path: paths + "?from=PC1", status: http.StatusBadRequest},
+		{name: "from a device that doesn't exist", method: echo.GET, path: paths + "?from=PC9&to=D1", status: http.StatusNotFound, contains: "PC9"},
+		{name: "in a room that doesn't exist", method: echo.GET, path: "/buildings/ITB/rooms/9999/paths?from=PC1&to=D1", status: http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := newTestRouter(t, func(router *echo.Echo, handlerGroup *HandlerGroup) {
+				router.GET("/buildings/:building/rooms/:room/paths", handlerGroup.GetRoomPaths)
+			})
+
+			test.run(t, router)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/byuoitav/configuration-database-microservice/migrations"
+)
+
+// migrate runs the migrate subcommand against the database the microservice would use
+func migrate(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "usage: %s migrate up|down|status\n", os.Args[0])
+		os.Exit(2)
+	}
+
+	accessorGroup := openDatabase()
+	defer accessorGroup.Database.Close()
+
+	switch args[0] {
+	case "up":
+		err := migrations.Up(accessorGroup.Database, accessorGroup.Driver)
+		if err != nil {
+			log.Fatalf("%s", err)
+		}
+
+	case "down":
+		err := migrations.Down(accessorGroup.Database, accessorGroup.Driver)
+		if err != nil {
+			log.Fatalf("%s", err)
+		}
+
+	case "status":
+		statuses, err := migrations.GetStatus(accessorGroup.Database)
+		if err != nil {
+			log.Fatalf("%s", err)
+		}
+
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt
+			}
+
+			fmt.Printf("%04d  %-40s  %s\n", status.Version, status.Description, appliedAt)
+		}
+
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate command %q; expected up, down or status\n", args[0])
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,392 @@
+package migrations
+
+// initialSchema is the schema the MySQL database had when migrations were introduced. Applying
+// it to that database leaves the tables alone and recreates the view and stored procedure as
+// they already were, so all it really does there is record the version. That's also why it
+// can't be reverted: the tables it would drop hold everything configured before it was applied.
+var initialSchema = Migration{
+	Version:     1,
+	Description: "initial schema",
+	Up: map[string][]string{
+		MySQL: {
+			`CREATE TABLE IF NOT EXISTS Buildings (
+				buildingID int(11) NOT NULL AUTO_INCREMENT,
+				name text,
+				shortName varchar(256) DEFAULT NULL,
+				description text,
+				PRIMARY KEY (buildingID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS RoomConfiguration (
+				roomConfigurationID int(11) NOT NULL AUTO_INCREMENT,
+				name varchar(256) DEFAULT NULL,
+				description text,
+				roomConfigurationKey varchar(256) DEFAULT NULL,
+				roomInitializationKey varchar(256) DEFAULT NULL,
+				PRIMARY KEY (roomConfigurationID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS RoomConfigurationMapping (
+				roomConfigurationMappingID int(11) NOT NULL AUTO_INCREMENT,
+				roomConfigurationID int(11) DEFAULT NULL,
+				evaluatorKey varchar(256) DEFAULT NULL,
+				priority int(11) DEFAULT NULL,
+				PRIMARY KEY (roomConfigurationMappingID),
+				KEY roomConfigurationID (roomConfigurationID),
+				CONSTRAINT RoomConfigurationMapping_ibfk_2 FOREIGN KEY (roomConfigurationID) REFERENCES RoomConfiguration (roomConfigurationID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE OR REPLACE VIEW vConfigurationMapping AS
+				SELECT rc.roomConfigurationID AS ConfigurationID,
+				rc.name AS ConfigurationName,
+				rcm.evaluatorKey AS EvaluatorKey,
+				rcm.priority AS Priority,
+				rc.roomConfigurationKey AS ConfigurationKey
+				FROM RoomConfiguration rc
+				JOIN RoomConfigurationMapping rcm ON rc.roomConfigurationID = rcm.roomConfigurationID`,
+			`DROP PROCEDURE IF EXISTS AddConfigurationMapping`,
+			`CREATE PROCEDURE AddConfigurationMapping(IN ConfigurationName VARCHAR(256), IN EvaluatorKey VARCHAR(256), IN Priority INT)
+			BEGIN
+				DECLARE rcid int;
+
+				SELECT roomConfigurationID INTO rcid
+				FROM RoomConfiguration WHERE RoomConfiguration.name = ConfigurationName;
+
+				INSERT INTO RoomConfigurationMapping (roomConfigurationID, evaluatorKey, priority)
+				VALUES (rcid, EvaluatorKey, Priority);
+			END`,
+			`CREATE TABLE IF NOT EXISTS Rooms (
+				roomID int(11) NOT NULL AUTO_INCREMENT,
+				name varchar(256) DEFAULT NULL,
+				buildingID int(11) DEFAULT NULL,
+				description varchar(256) DEFAULT NULL,
+				configurationID int(11) DEFAULT NULL,
+				roomDesignation varchar(256) DEFAULT NULL,
+				PRIMARY KEY (roomID),
+				KEY rmBld_ind (buildingID),
+				CONSTRAINT Rooms_ibfk_1 FOREIGN KEY (buildingID) REFERENCES Buildings (buildingID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS DeviceClasses (
+				deviceClassID int(11) NOT NULL AUTO_INCREMENT,
+				name varchar(256) DEFAULT NULL,
+				description text,
+				PRIMARY KEY (deviceClassID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS DeviceTypes (
+				deviceTypeID int(11) NOT NULL AUTO_INCREMENT,
+				typeName varchar(255) NOT NULL,
+				typeDescription varchar(1024),
+				typeDisplayName varchar(255),
+				PRIMARY KEY (deviceTypeID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS Devices (
+				deviceID int(11) NOT NULL AUTO_INCREMENT,
+				name varchar(256) DEFAULT NULL,
+				address varchar(256) DEFAULT NULL,
+				input tinyint(1) DEFAULT NULL,
+				output tinyint(1) DEFAULT NULL,
+				buildingID int(11) DEFAULT NULL,
+				roomID int(11) DEFAULT NULL,
+				classID int(11) DEFAULT NULL,
+				typeID int(11) DEFAULT NULL,
+				displayName varchar(256) DEFAULT NULL,
+				PRIMARY KEY (deviceID),
+				KEY devbld_ind (buildingID),
+				KEY devrm_ind (roomID),
+				KEY devcl_ind (classID),
+				KEY devty_ind (typeID),
+				CONSTRAINT Devices_ibfk_1 FOREIGN KEY (buildingID) REFERENCES Buildings (buildingID),
+				CONSTRAINT Devices_ibfk_4 FOREIGN KEY (roomID) REFERENCES Rooms (roomID),
+				CONSTRAINT Devices_ibfk_5 FOREIGN KEY (classID) REFERENCES DeviceClasses (deviceClassID),
+				CONSTRAINT Devices_ibfk_6 FOREIGN KEY (typeID) REFERENCES DeviceTypes (deviceTypeID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS Commands (
+				commandID int(11) NOT NULL AUTO_INCREMENT,
+				name varchar(256) DEFAULT NULL,
+				description text,
+				Priority int(11) DEFAULT NULL,
+				PRIMARY KEY (commandID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS Endpoints (
+				endpointID int(11) NOT NULL AUTO_INCREMENT,
+				name varchar(256) DEFAULT NULL,
+				path text,
+				description text,
+				PRIMARY KEY (endpointID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS Microservices (
+				microserviceID int(11) NOT NULL AUTO_INCREMENT,
+				name varchar(256) DEFAULT NULL,
+				address text,
+				description text,
+				PRIMARY KEY (microserviceID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS DeviceTypeCommandMapping (
+				deviceTypeCommandMappingID int NOT NULL AUTO_INCREMENT,
+				deviceTypeID int NOT NULL,
+				commandID int,
+				microserviceID int,
+				endpointID int,
+				PRIMARY KEY (deviceTypeCommandMappingID),
+				KEY devTypComDev_ind (deviceTypeID),
+				KEY devTypComCom_ind (commandID),
+				KEY devTypComMS_ind (microserviceID),
+				KEY devTypComEnd_ind (endpointID),
+				CONSTRAINT DeviceTypeCommands_ibfk_1 FOREIGN KEY (deviceTypeID) REFERENCES DeviceTypes (deviceTypeID),
+				CONSTRAINT DeviceTypeCommands_ibfk_2 FOREIGN KEY (commandID) REFERENCES Commands (commandID),
+				CONSTRAINT DeviceTypeCommands_ibfk_3 FOREIGN KEY (endpointID) REFERENCES Endpoints (endpointID),
+				CONSTRAINT DeviceTypeCommands_ibfk_4 FOREIGN KEY (microserviceID) REFERENCES Microservices (microserviceID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS DeviceCommands (
+				deviceCommandID int(11) NOT NULL AUTO_INCREMENT,
+				deviceID int(11) DEFAULT NULL,
+				commandID int(11) DEFAULT NULL,
+				microserviceID int(11) DEFAULT NULL,
+				endpointID int(11) DEFAULT NULL,
+				enabled tinyint(1) DEFAULT NULL,
+				PRIMARY KEY (deviceCommandID),
+				KEY devComDev_ind (deviceID),
+				KEY devComCom_ind (commandID),
+				KEY devComMS_ind (microserviceID),
+				KEY devComEnd_ind (endpointID),
+				CONSTRAINT DeviceCommands_ibfk_1 FOREIGN KEY (deviceID) REFERENCES Devices (deviceID),
+				CONSTRAINT DeviceCommands_ibfk_2 FOREIGN KEY (commandID) REFERENCES Commands (commandID),
+				CONSTRAINT DeviceCommands_ibfk_3 FOREIGN KEY (endpointID) REFERENCES Endpoints (endpointID),
+				CONSTRAINT DeviceCommands_ibfk_4 FOREIGN KEY (microserviceID) REFERENCES Microservices (microserviceID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS Ports (
+				portID int(11) NOT NULL AUTO_INCREMENT,
+				name varchar(256) DEFAULT NULL,
+				description text,
+				PRIMARY KEY (portID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS DeviceTypePorts (
+				deviceTypePortID int NOT NULL AUTO_INCREMENT,
+				deviceTypeID int NOT NULL,
+				portID int NOT NULL,
+				description varchar(1024) NOT NULL,
+				friendlyName varchar(255) NOT NULL,
+				hostDestinationMirror tinyint(1) NOT NULL,
+				PRIMARY KEY (deviceTypePortID),
+				KEY devTypPorTyp_ind (deviceTypeID),
+				KEY devTypPorPor_ind (portID),
+				CONSTRAINT deviceTypePorts_ibfk_1 FOREIGN KEY (deviceTypeID) REFERENCES DeviceTypes (deviceTypeID),
+				CONSTRAINT deviceTypePorts_ibfk_2 FOREIGN KEY (portID) REFERENCES Ports (portID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS PortConfiguration (
+				portConfigurationID int(11) NOT NULL AUTO_INCREMENT,
+				destinationDeviceID int(11) DEFAULT NULL,
+				portID int(11) DEFAULT NULL,
+				sourceDeviceID int(11) DEFAULT NULL,
+				hostDeviceID int(11) DEFAULT NULL,
+				PRIMARY KEY (portConfigurationID),
+				KEY destinationDeviceID (destinationDeviceID),
+				KEY portID (portID),
+				KEY sourceDeviceID (sourceDeviceID),
+				KEY hostDeviceID (hostDeviceID),
+				CONSTRAINT PortConfiguration_ibfk_1 FOREIGN KEY (destinationDeviceID) REFERENCES Devices (deviceID),
+				CONSTRAINT PortConfiguration_ibfk_2 FOREIGN KEY (portID) REFERENCES Ports (portID),
+				CONSTRAINT PortConfiguration_ibfk_3 FOREIGN KEY (sourceDeviceID) REFERENCES Devices (deviceID),
+				CONSTRAINT PortConfiguration_ibfk_4 FOREIGN KEY (hostDeviceID) REFERENCES Devices (deviceID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS PowerStates (
+				powerStateID int(11) NOT NULL AUTO_INCREMENT,
+				name varchar(256) DEFAULT NULL,
+				description text,
+				PRIMARY KEY (powerStateID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS DevicePowerStates (
+				devicePowerStateID int(11) NOT NULL AUTO_INCREMENT,
+				deviceID int(11) DEFAULT NULL,
+				powerStateID int(11) DEFAULT NULL,
+				PRIMARY KEY (devicePowerStateID),
+				KEY deviceID (deviceID),
+				KEY powerStateID (powerStateID),
+				CONSTRAINT DevicePowerStates_ibfk_1 FOREIGN KEY (deviceID) REFERENCES Devices (deviceID),
+				CONSTRAINT DevicePowerStates_ibfk_2 FOREIGN KEY (powerStateID) REFERENCES PowerStates (powerStateID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS DeviceRoleDefinition (
+				deviceRoleDefinitionID int(11) NOT NULL AUTO_INCREMENT,
+				name varchar(256) DEFAULT NULL,
+				description text,
+				PRIMARY KEY (deviceRoleDefinitionID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS DeviceRole (
+				deviceRoleID int(11) NOT NULL AUTO_INCREMENT,
+				deviceID int(11) DEFAULT NULL,
+				deviceRoleDefinitionID int(11) DEFAULT NULL,
+				PRIMARY KEY (deviceRoleID),
+				KEY devRolDevID_ind (deviceID),
+				KEY devRolDevRolDef_ind (deviceRoleDefinitionID),
+				CONSTRAINT DeviceRole_ibfk_1 FOREIGN KEY (deviceID) REFERENCES Devices (deviceID),
+				CONSTRAINT DeviceRole_ibfk_2 FOREIGN KEY (deviceRoleDefinitionID) REFERENCES DeviceRoleDefinition (deviceRoleDefinitionID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS AudioDevices (
+				audioDeviceID int(11) NOT NULL AUTO_INCREMENT,
+				deviceID int(11) DEFAULT NULL,
+				deviceRoleDefinitionID int(11) DEFAULT NULL,
+				muted tinyint(1) DEFAULT NULL,
+				volume int(11) DEFAULT NULL,
+				PRIMARY KEY (audioDeviceID),
+				KEY audDev_ind (deviceID),
+				KEY audDevRol_ind (deviceRoleDefinitionID),
+				CONSTRAINT AudioDevices_ibfk_1 FOREIGN KEY (deviceID) REFERENCES Devices (deviceID),
+				CONSTRAINT AudioDevices_ibfk_2 FOREIGN KEY (deviceRoleDefinitionID) REFERENCES DeviceRoleDefinition (deviceRoleDefinitionID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS Displays (
+				displayID int(11) NOT NULL AUTO_INCREMENT,
+				deviceID int(11) DEFAULT NULL,
+				deviceRoleDefinitionID int(11) DEFAULT NULL,
+				blanked tinyint(1) DEFAULT NULL,
+				PRIMARY KEY (displayID),
+				KEY dispDev_ind (deviceID),
+				KEY dispDevRol_ind (deviceRoleDefinitionID),
+				CONSTRAINT Displays_ibfk_1 FOREIGN KEY (deviceID) REFERENCES Devices (deviceID),
+				CONSTRAINT Displays_ibfk_2 FOREIGN KEY (deviceRoleDefinitionID) REFERENCES DeviceRoleDefinition (deviceRoleDefinitionID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+		},
+		SQLite: {
+			`CREATE TABLE IF NOT EXISTS Buildings (
+				buildingID INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT,
+				shortName VARCHAR(256),
+				description TEXT
+			)`,
+			`CREATE TABLE IF NOT EXISTS RoomConfiguration (
+				roomConfigurationID INTEGER PRIMARY KEY AUTOINCREMENT,
+				name VARCHAR(256),
+				description TEXT,
+				roomConfigurationKey VARCHAR(256),
+				roomInitializationKey VARCHAR(256)
+			)`,
+			`CREATE TABLE IF NOT EXISTS RoomConfigurationMapping (
+				roomConfigurationMappingID INTEGER PRIMARY KEY AUTOINCREMENT,
+				roomConfigurationID INTEGER REFERENCES RoomConfiguration (roomConfigurationID),
+				evaluatorKey VARCHAR(256),
+				priority INTEGER
+			)`,
+			`CREATE VIEW IF NOT EXISTS vConfigurationMapping AS
+				SELECT rc.roomConfigurationID AS ConfigurationID,
+				rc.name AS ConfigurationName,
+				rcm.evaluatorKey AS EvaluatorKey,
+				rcm.priority AS Priority,
+				rc.roomConfigurationKey AS ConfigurationKey
+				FROM RoomConfiguration rc
+				JOIN RoomConfigurationMapping rcm ON rc.roomConfigurationID = rcm.roomConfigurationID`,
+			`CREATE TABLE IF NOT EXISTS Rooms (
+				roomID INTEGER PRIMARY KEY AUTOINCREMENT,
+				name VARCHAR(256),
+				buildingID INTEGER REFERENCES Buildings (buildingID),
+				description VARCHAR(256),
+				configurationID INTEGER REFERENCES RoomConfiguration (roomConfigurationID),
+				roomDesignation VARCHAR(256)
+			)`,
+			`CREATE TABLE IF NOT EXISTS DeviceClasses (
+				deviceClassID INTEGER PRIMARY KEY AUTOINCREMENT,
+				name VARCHAR(256),
+				description TEXT
+			)`,
+			`CREATE TABLE IF NOT EXISTS DeviceTypes (
+				deviceTypeID INTEGER PRIMARY KEY AUTOINCREMENT,
+				typeName VARCHAR(255) NOT NULL,
+				typeDescription VARCHAR(1024),
+				typeDisplayName VARCHAR(255)
+			)`,
+			`CREATE TABLE IF NOT EXISTS Devices (
+				deviceID INTEGER PRIMARY KEY AUTOINCREMENT,
+				name VARCHAR(256),
+				address VARCHAR(256),
+				input BOOLEAN,
+				output BOOLEAN,
+				buildingID INTEGER REFERENCES Buildings (buildingID),
+				roomID INTEGER REFERENCES Rooms (roomID),
+				classID INTEGER REFERENCES DeviceClasses (deviceClassID),
+				typeID INTEGER REFERENCES DeviceTypes (deviceTypeID),
+				displayName VARCHAR(256)
+			)`,
+			`CREATE TABLE IF NOT EXISTS Commands (
+				commandID INTEGER PRIMARY KEY AUTOINCREMENT,
+				name VARCHAR(256),
+				description TEXT,
+				priority INTEGER
+			)`,
+			`CREATE TABLE IF NOT EXISTS Endpoints (
+				endpointID INTEGER PRIMARY KEY AUTOINCREMENT,
+				name VARCHAR(256),
+				path TEXT,
+				description TEXT
+			)`,
+			`CREATE TABLE IF NOT EXISTS Microservices (
+				microserviceID INTEGER PRIMARY KEY AUTOINCREMENT,
+				name VARCHAR(256),
+				address TEXT,
+				description TEXT
+			)`,
+			`CREATE TABLE IF NOT EXISTS DeviceTypeCommandMapping (
+				deviceTypeCommandMappingID INTEGER PRIMARY KEY AUTOINCREMENT,
+				deviceTypeID INTEGER NOT NULL REFERENCES DeviceTypes (deviceTypeID),
+				commandID INTEGER REFERENCES Commands (commandID),
+				microserviceID INTEGER REFERENCES Microservices (microserviceID),
+				endpointID INTEGER REFERENCES Endpoints (endpointID)
+			)`,
+			`CREATE TABLE IF NOT EXISTS DeviceCommands (
+				deviceCommandID INTEGER PRIMARY KEY AUTOINCREMENT,
+				deviceID INTEGER REFERENCES Devices (deviceID),
+				commandID INTEGER REFERENCES Commands (commandID),
+				microserviceID INTEGER REFERENCES Microservices (microserviceID),
+				endpointID INTEGER REFERENCES Endpoints (endpointID),
+				enabled BOOLEAN
+			)`,
+			`CREATE TABLE IF NOT EXISTS Ports (
+				portID INTEGER PRIMARY KEY AUTOINCREMENT,
+				name VARCHAR(256),
+				description TEXT
+			)`,
+			`CREATE TABLE IF NOT EXISTS DeviceTypePorts (
+				deviceTypePortID INTEGER PRIMARY KEY AUTOINCREMENT,
+				deviceTypeID INTEGER NOT NULL REFERENCES DeviceTypes (deviceTypeID),
+				portID INTEGER NOT NULL REFERENCES Ports (portID),
+				description VARCHAR(1024) NOT NULL,
+				friendlyName VARCHAR(255) NOT NULL,
+				hostDestinationMirror BOOLEAN NOT NULL
+			)`,
+			`CREATE TABLE IF NOT EXISTS PortConfiguration (
+				portConfigurationID INTEGER PRIMARY KEY AUTOINCREMENT,
+				destinationDeviceID INTEGER REFERENCES Devices (deviceID),
+				portID INTEGER REFERENCES Ports (portID),
+				sourceDeviceID INTEGER REFERENCES Devices (deviceID),
+				hostDeviceID INTEGER REFERENCES Devices (deviceID)
+			)`,
+			`CREATE TABLE IF NOT EXISTS PowerStates (
+				powerStateID INTEGER PRIMARY KEY AUTOINCREMENT,
+				name VARCHAR(256),
+				description TEXT
+			)`,
+			`CREATE TABLE IF NOT EXISTS DevicePowerStates (
+				devicePowerStateID INTEGER PRIMARY KEY AUTOINCREMENT,
+				deviceID INTEGER REFERENCES Devices (deviceID),
+				powerStateID INTEGER REFERENCES PowerStates (powerStateID)
+			)`,
+			`CREATE TABLE IF NOT EXISTS DeviceRoleDefinition (
+				deviceRoleDefinitionID INTEGER PRIMARY KEY AUTOINCREMENT,
+				name VARCHAR(256),
+				description TEXT
+			)`,
+			`CREATE TABLE IF NOT EXISTS DeviceRole (
+				deviceRoleID INTEGER PRIMARY KEY AUTOINCREMENT,
+				deviceID INTEGER REFERENCES Devices (deviceID),
+				deviceRoleDefinitionID INTEGER REFERENCES DeviceRoleDefinition (deviceRoleDefinitionID)
+			)`,
+			`CREATE TABLE IF NOT EXISTS AudioDevices (
+				audioDeviceID INTEGER PRIMARY KEY AUTOINCREMENT,
+				deviceID INTEGER REFERENCES Devices (deviceID),
+				deviceRoleDefinitionID INTEGER REFERENCES DeviceRoleDefinition (deviceRoleDefinitionID),
+				muted BOOLEAN,
+				volume INTEGER
+			)`,
+			`CREATE TABLE IF NOT EXISTS Displays (
+				displayID INTEGER PRIMARY KEY AUTOINCREMENT,
+				deviceID INTEGER REFERENCES Devices (deviceID),
+				deviceRoleDefinitionID INTEGER REFERENCES DeviceRoleDefinition (deviceRoleDefinitionID),
+				blanked BOOLEAN
+			)`,
+		},
+	},
+}
@@ -0,0 +1,82 @@
+package migrations
+
+import "fmt"
+
+// referenceData seeds the role definitions and power states the accessors look up by name.
+// Rows that already exist are left alone. The ones it does add are listed in SeededReferenceData,
+// so that reverting it deletes those and leaves the ones that were there before it.
+var referenceData = Migration{
+	Version:     2,
+	Description: "seed role definitions and power states",
+	Up: map[string][]string{
+		MySQL: append([]string{
+			`CREATE TABLE IF NOT EXISTS SeededReferenceData (
+				tableName varchar(64) NOT NULL,
+				name varchar(256) NOT NULL
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+		}, seed(MySQL)...),
+		SQLite: append([]string{
+			`CREATE TABLE IF NOT EXISTS SeededReferenceData (
+				tableName VARCHAR(64) NOT NULL,
+				name VARCHAR(256) NOT NULL
+			)`,
+		}, seed(SQLite)...),
+	},
+	Down: map[string][]string{
+		MySQL:  unseed(),
+		SQLite: unseed(),
+	},
+}
+
+var roleDefinitions = [][2]string{
+	{"AudioOut", "Device that outputs audio (speakers, tv, etc.)"},
+	{"VideoOut", "Device that displays video (projector, tv, etc.)"},
+	{"AudioIn", "Device that provides Audio input (computer, 3.5mm jack, hdmi, etc.)"},
+	{"VideoIn", "Device that provides Video input (computer, HDMI, VGA, etc.)"},
+	{"ControlProcessor", "A device that controls other devices in the room"},
+	{"Touchpanel", "The touch interface for controlling devices in a room"},
+}
+
+var powerStates = [][2]string{
+	{"On", "Powered on"},
+	{"Standby", "In standby"},
+	{"Off", "Powered off"},
+}
+
+func seed(dialect string) []string {
+	// MySQL won't take a WHERE without a FROM
+	from := ""
+	if dialect == MySQL {
+		from = " FROM DUAL"
+	}
+
+	statements := []string{}
+	insert := func(table string, row [2]string) {
+		missing := fmt.Sprintf("%s WHERE NOT EXISTS (SELECT 1 FROM %s WHERE name = '%s')", from, table, row[0])
+
+		// the row is noted before it's added, while it's still missing
+		statements = append(statements,
+			fmt.Sprintf("INSERT INTO SeededReferenceData (tableName, name) SELECT '%s', '%s'%s", table, row[0], missing),
+			fmt.Sprintf("INSERT INTO %s (name, description) SELECT '%s', '%s'%s", table, row[0], row[1], missing))
+	}
+
+	for _, row := range roleDefinitions {
+		insert("DeviceRoleDefinition", row)
+	}
+	for _, row := range powerStates {
+		insert("PowerStates", row)
+	}
+
+	return statements
+}
+
+// unseed fails if any device still uses one of the rows, which is what we want
+func unseed() []string {
+	statements := []string{}
+
+	for _, table := range []string{"DeviceRoleDefinition", "PowerStates"} {
+		statements = append(statements, fmt.Sprintf("DELETE FROM %s WHERE name IN (SELECT name FROM SeededReferenceData WHERE tableName = '%s')", table, table))
+	}
+
+	return append(statements, "DROP TABLE SeededReferenceData")
+}
@@ -0,0 +1,30 @@
+package migrations
+
+// dropConfigurationProcedure drops the AddConfigurationMapping stored procedure. The accessors
+// add evaluator mappings themselves now, the same way for every backend; SQLite never had it.
+var dropConfigurationProcedure = Migration{
+	Version:     3,
+	Description: "drop AddConfigurationMapping procedure",
+	Up: map[string][]string{
+		MySQL: {
+			`DROP PROCEDURE IF EXISTS AddConfigurationMapping`,
+		},
+		SQLite: {},
+	},
+	Down: map[string][]string{
+		MySQL: {
+			`DROP PROCEDURE IF EXISTS AddConfigurationMapping`,
+			`CREATE PROCEDURE AddConfigurationMapping(IN ConfigurationName VARCHAR(256), IN EvaluatorKey VARCHAR(256), IN Priority INT)
+			BEGIN
+				DECLARE rcid int;
+
+				SELECT roomConfigurationID INTO rcid
+				FROM RoomConfiguration WHERE RoomConfiguration.name = ConfigurationName;
+
+				INSERT INTO RoomConfigurationMapping (roomConfigurationID, evaluatorKey, priority)
+				VALUES (rcid, EvaluatorKey, Priority);
+			END`,
+		},
+		SQLite: {},
+	},
+}
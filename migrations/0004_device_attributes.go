@@ -0,0 +1,40 @@
+package migrations
+
+// deviceAttributes adds a table of typed name/value settings on devices, so a new per-device
+// setting doesn't need a new column. Values are stored as text next to their type.
+var deviceAttributes = Migration{
+	Version:     4,
+	Description: "add device attributes",
+	Up: map[string][]string{
+		MySQL: {
+			`CREATE TABLE IF NOT EXISTS DeviceAttributes (
+				deviceAttributeID int(11) NOT NULL AUTO_INCREMENT,
+				deviceID int(11) NOT NULL,
+				name varchar(256) NOT NULL,
+				type varchar(16) NOT NULL,
+				value text NOT NULL,
+				PRIMARY KEY (deviceAttributeID),
+				UNIQUE KEY devAttDevNam_ind (deviceID, name),
+				CONSTRAINT DeviceAttributes_ibfk_1 FOREIGN KEY (deviceID) REFERENCES Devices (deviceID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+		},
+		SQLite: {
+			`CREATE TABLE IF NOT EXISTS DeviceAttributes (
+				deviceAttributeID INTEGER PRIMARY KEY AUTOINCREMENT,
+				deviceID INTEGER NOT NULL REFERENCES Devices (deviceID),
+				name VARCHAR(256) NOT NULL,
+				type VARCHAR(16) NOT NULL,
+				value TEXT NOT NULL,
+				UNIQUE (deviceID, name)
+			)`,
+		},
+	},
+	Down: map[string][]string{
+		MySQL: {
+			`DROP TABLE IF EXISTS DeviceAttributes`,
+		},
+		SQLite: {
+			`DROP TABLE IF EXISTS DeviceAttributes`,
+		},
+	},
+}
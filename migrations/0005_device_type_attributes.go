@@ -0,0 +1,49 @@
+package migrations
+
+// deviceTypeAttributes adds the attribute schema of each device type: the attributes its
+// devices may have, with their types, ranges, enums and defaults. Enums are stored as JSON
+// arrays, defaults as text like attribute values.
+var deviceTypeAttributes = Migration{
+	Version:     5,
+	Description: "add device type attribute schemas",
+	Up: map[string][]string{
+		MySQL: {
+			`CREATE TABLE IF NOT EXISTS DeviceTypeAttributes (
+				deviceTypeAttributeID int(11) NOT NULL AUTO_INCREMENT,
+				deviceTypeID int(11) NOT NULL,
+				name varchar(256) NOT NULL,
+				type varchar(16) NOT NULL,
+				description text,
+				minimum double DEFAULT NULL,
+				maximum double DEFAULT NULL,
+				enumValues text,
+				defaultValue text,
+				PRIMARY KEY (deviceTypeAttributeID),
+				UNIQUE KEY devTypAttTypNam_ind (deviceTypeID, name),
+				CONSTRAINT DeviceTypeAttributes_ibfk_1 FOREIGN KEY (deviceTypeID) REFERENCES DeviceTypes (deviceTypeID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+		},
+		SQLite: {
+			`CREATE TABLE IF NOT EXISTS DeviceTypeAttributes (
+				deviceTypeAttributeID INTEGER PRIMARY KEY AUTOINCREMENT,
+				deviceTypeID INTEGER NOT NULL REFERENCES DeviceTypes (deviceTypeID),
+				name VARCHAR(256) NOT NULL,
+				type VARCHAR(16) NOT NULL,
+				description TEXT,
+				minimum REAL,
+				maximum REAL,
+				enumValues TEXT,
+				defaultValue TEXT,
+				UNIQUE (deviceTypeID, name)
+			)`,
+		},
+	},
+	Down: map[string][]string{
+		MySQL: {
+			`DROP TABLE IF EXISTS DeviceTypeAttributes`,
+		},
+		SQLite: {
+			`DROP TABLE IF EXISTS DeviceTypeAttributes`,
+		},
+	},
+}
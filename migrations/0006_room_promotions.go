@@ -0,0 +1,50 @@
+package migrations
+
+// roomPromotions adds the history of rooms moving between designations, e.g. from stage to
+// production: who moved each room, who approved it, and when (RFC 3339, like schema_version).
+// A promotion can wait for a second person's approval before it moves the room, so status is
+// pending, applied or withdrawn, and approvedAt is when the approval came in.
+var roomPromotions = Migration{
+	Version:     6,
+	Description: "add room promotion history",
+	Up: map[string][]string{
+		MySQL: {
+			`CREATE TABLE IF NOT EXISTS RoomPromotions (
+				roomPromotionID int(11) NOT NULL AUTO_INCREMENT,
+				roomID int(11) NOT NULL,
+				fromDesignation varchar(256) NOT NULL,
+				toDesignation varchar(256) NOT NULL,
+				promotedBy varchar(256) NOT NULL,
+				approvedBy varchar(256) DEFAULT NULL,
+				promotedAt varchar(64) NOT NULL,
+				status varchar(16) NOT NULL,
+				approvedAt varchar(64) DEFAULT NULL,
+				PRIMARY KEY (roomPromotionID),
+				KEY roomPromotionRoom_ind (roomID),
+				CONSTRAINT RoomPromotions_ibfk_1 FOREIGN KEY (roomID) REFERENCES Rooms (roomID)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+		},
+		SQLite: {
+			`CREATE TABLE IF NOT EXISTS RoomPromotions (
+				roomPromotionID INTEGER PRIMARY KEY AUTOINCREMENT,
+				roomID INTEGER NOT NULL REFERENCES Rooms (roomID),
+				fromDesignation VARCHAR(256) NOT NULL,
+				toDesignation VARCHAR(256) NOT NULL,
+				promotedBy VARCHAR(256) NOT NULL,
+				approvedBy VARCHAR(256),
+				promotedAt VARCHAR(64) NOT NULL,
+				status VARCHAR(16) NOT NULL,
+				approvedAt VARCHAR(64)
+			)`,
+			`CREATE INDEX IF NOT EXISTS roomPromotionRoom_ind ON RoomPromotions (roomID)`,
+		},
+	},
+	Down: map[string][]string{
+		MySQL: {
+			`DROP TABLE IF EXISTS RoomPromotions`,
+		},
+		SQLite: {
+			`DROP TABLE IF EXISTS RoomPromotions`,
+		},
+	},
+}
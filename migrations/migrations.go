@@ -0,0 +1,225 @@
+/*
+Package migrations keeps the configuration database schema under version control.
+
+Each Migration carries the statements that move a database up to its version and
+back down again, once per SQL dialect. The versions applied to a database are
+recorded in its schema_version table, so Up only runs what's missing.
+
+The statements are compiled into the binary; run them with
+
+	configuration-database-microservice migrate up|down|status
+*/
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Dialects, named after the database/sql driver that speaks them
+const (
+	MySQL  = "mysql"
+	SQLite = "sqlite3"
+)
+
+// Migration is one numbered step in the schema's history. Up and Down are keyed by dialect.
+// Down is nil for a migration that can't be reverted without losing data it didn't create.
+type Migration struct {
+	Version     int
+	Description string
+	Up          map[string][]string
+	Down        map[string][]string
+}
+
+// Status is whether a migration has been applied to a database, and when
+type Status struct {
+	Version     int    `json:"version"`
+	Description string `json:"description"`
+	Applied     bool   `json:"applied"`
+	AppliedAt   string `json:"appliedAt,omitempty"`
+}
+
+// all is every migration, oldest first. Append new ones; never renumber or edit one that has shipped.
+var all = []Migration{
+	initialSchema,
+	referenceData,
+	dropConfigurationProcedure,
+	deviceAttributes,
+	deviceTypeAttributes,
+	roomPromotions,
+}
+
+// Latest returns the version the newest migration brings a database to
+func Latest() int {
+	return all[len(all)-1].Version
+}
+
+// Version returns the newest migration applied to the database, or 0 if there are none
+func Version(db *sql.DB) (int, error) {
+	err := createVersionTable(db)
+	if err != nil {
+		return 0, err
+	}
+
+	var version int
+	err = db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// Up applies every migration newer than the database's current version
+func Up(db *sql.DB, dialect string) error {
+	return upTo(db, dialect, Latest())
+}
+
+// upTo applies the migrations newer than the database's current version, up to and including version
+func upTo(db *sql.DB, dialect string, version int) error {
+	current, err := Version(db)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range all {
+		if migration.Version <= current || migration.Version > version {
+			continue
+		}
+
+		log.Printf("Applying migration %d: %s", migration.Version, migration.Description)
+
+		statements, ok := migration.Up[dialect]
+		if !ok {
+			return fmt.Errorf("migration %d has no %s statements", migration.Version, dialect)
+		}
+
+		err = run(db, statements, "INSERT INTO schema_version (version, description, appliedAt) VALUES (?,?,?)", migration.Version, migration.Description, time.Now().UTC().Format(time.RFC3339))
+		if err != nil {
+			return fmt.Errorf("migration %d failed: %s", migration.Version, err)
+		}
+	}
+
+	return nil
+}
+
+// Down reverts the newest migration applied to the database
+func Down(db *sql.DB, dialect string) error {
+	current, err := Version(db)
+	if err != nil {
+		return err
+	}
+
+	if current == 0 {
+		return fmt.Errorf("no migrations have been applied")
+	}
+
+	for i := len(all) - 1; i >= 0; i-- {
+		migration := all[i]
+		if migration.Version != current {
+			continue
+		}
+
+		if migration.Down == nil {
+			return fmt.Errorf("migration %d (%s) can't be reverted", migration.Version, migration.Description)
+		}
+
+		log.Printf("Reverting migration %d: %s", migration.Version, migration.Description)
+
+		statements, ok := migration.Down[dialect]
+		if !ok {
+			return fmt.Errorf("migration %d has no %s statements", migration.Version, dialect)
+		}
+
+		err = run(db, statements, "DELETE FROM schema_version WHERE version = ?", migration.Version)
+		if err != nil {
+			return fmt.Errorf("reverting migration %d failed: %s", migration.Version, err)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("the database is at version %d, which this build doesn't know about", current)
+}
+
+// GetStatus lists every migration and whether it has been applied to the database
+func GetStatus(db *sql.DB) ([]Status, error) {
+	err := createVersionTable(db)
+	if err != nil {
+		return []Status{}, err
+	}
+
+	rows, err := db.Query("SELECT version, appliedAt FROM schema_version")
+	if err != nil {
+		return []Status{}, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]string)
+	for rows.Next() {
+		var version int
+		var appliedAt sql.NullString
+
+		err = rows.Scan(&version, &appliedAt)
+		if err != nil {
+			return []Status{}, err
+		}
+
+		applied[version] = appliedAt.String
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return []Status{}, err
+	}
+
+	toReturn := []Status{}
+	for _, migration := range all {
+		appliedAt, ok := applied[migration.Version]
+		toReturn = append(toReturn, Status{
+			Version:     migration.Version,
+			Description: migration.Description,
+			Applied:     ok,
+			AppliedAt:   appliedAt,
+		})
+	}
+
+	return toReturn, nil
+}
+
+func createVersionTable(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER NOT NULL PRIMARY KEY,
+		description VARCHAR(256),
+		appliedAt VARCHAR(64)
+	)`)
+
+	return err
+}
+
+// run executes statements, then the bookkeeping statement, in one transaction. MySQL commits
+// DDL implicitly, so there a failure part way through can leave earlier statements applied.
+func run(db *sql.DB, statements []string, bookkeeping string, args ...interface{}) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range statements {
+		_, err = tx.Exec(statement)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	_, err = tx.Exec(bookkeeping, args...)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
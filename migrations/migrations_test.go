@@ -0,0 +1,197 @@
+//go:build cgo
+// +build cgo
+
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// newDatabase opens a new, empty SQLite database and returns it with a func that deletes it
+func newDatabase(t *testing.T) (*sql.DB, func()) {
+	dir, err := ioutil.TempDir("", "migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_foreign_keys=1", filepath.Join(dir, "migrations.db")))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// schema lists the tables, views and indexes in a database, along with how they're defined
+func schema(t *testing.T, db *sql.DB) map[string]string {
+	rows, err := db.Query("SELECT name, COALESCE(sql, '') FROM sqlite_master WHERE name NOT LIKE 'sqlite_%'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	toReturn := make(map[string]string)
+	for rows.Next() {
+		var name, definition string
+
+		err = rows.Scan(&name, &definition)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		toReturn[name] = definition
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return toReturn
+}
+
+func checkVersion(t *testing.T, db *sql.DB, want int) {
+	version, err := Version(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != want {
+		t.Fatalf("the database is at version %d, want %d", version, want)
+	}
+
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM schema_version").Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != want {
+		t.Fatalf("schema_version has %d rows at version %d", count, want)
+	}
+}
+
+// TestUpAndDown applies every migration one at a time, then reverts them one at a time, and
+// checks each one's Down leaves the schema as it was before its Up
+func TestUpAndDown(t *testing.T) {
+	db, done := newDatabase(t)
+	defer done()
+
+	before := make(map[int]map[string]string)
+	for _, migration := range all {
+		before[migration.Version] = schema(t, db)
+
+		err := upTo(db, SQLite, migration.Version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkVersion(t, db, migration.Version)
+	}
+
+	for i := len(all) - 1; i >= 1; i-- {
+		migration := all[i]
+
+		err := Down(db, SQLite)
+		if err != nil {
+			t.Fatalf("reverting migration %d: %s", migration.Version, err)
+		}
+		checkVersion(t, db, migration.Version-1)
+
+		after := schema(t, db)
+		if !reflect.DeepEqual(after, before[migration.Version]) {
+			t.Errorf("reverting migration %d left\n%v\nwant\n%v", migration.Version, after, before[migration.Version])
+		}
+	}
+
+	// the initial schema can't be reverted, as it may have been applied over an existing database
+	err := Down(db, SQLite)
+	if err == nil {
+		t.Errorf("reverting the initial schema worked, want an error")
+	}
+	checkVersion(t, db, 1)
+
+	err = Up(db, SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkVersion(t, db, Latest())
+}
+
+// TestReferenceDataDownKeepsExistingRows makes sure reverting the reference data only deletes
+// the rows it added
+func TestReferenceDataDownKeepsExistingRows(t *testing.T) {
+	db, done := newDatabase(t)
+	defer done()
+
+	err := upTo(db, SQLite, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statements := []string{
+		"INSERT INTO DeviceRoleDefinition (name, description) VALUES ('AudioOut', 'Device that outputs audio (speakers, tv, etc.)'), ('Microphone', 'A microphone')",
+		"INSERT INTO PowerStates (name, description) VALUES ('On', 'Powered on')",
+	}
+	for _, statement := range statements {
+		_, err = db.Exec(statement)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := func(table string) []string {
+		rows, err := db.Query("SELECT name FROM " + table + " ORDER BY name")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		toReturn := []string{}
+		for rows.Next() {
+			var name string
+			if err = rows.Scan(&name); err != nil {
+				t.Fatal(err)
+			}
+			toReturn = append(toReturn, name)
+		}
+
+		return toReturn
+	}
+
+	err = upTo(db, SQLite, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roles := []string{"AudioIn", "AudioOut", "ControlProcessor", "Microphone", "Touchpanel", "VideoIn", "VideoOut"}
+	if got := names("DeviceRoleDefinition"); !reflect.DeepEqual(got, roles) {
+		t.Errorf("seeded roles = %v, want %v", got, roles)
+	}
+	states := []string{"Off", "On", "Standby"}
+	if got := names("PowerStates"); !reflect.DeepEqual(got, states) {
+		t.Errorf("seeded power states = %v, want %v", got, states)
+	}
+
+	err = Down(db, SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roles = []string{"AudioOut", "Microphone"}
+	if got := names("DeviceRoleDefinition"); !reflect.DeepEqual(got, roles) {
+		t.Errorf("roles after reverting = %v, want %v", got, roles)
+	}
+	states = []string{"On"}
+	if got := names("PowerStates"); !reflect.DeepEqual(got, states) {
+		t.Errorf("power states after reverting = %v, want %v", got, states)
+	}
+}
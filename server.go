@@ -1,54 +1,77 @@
 package main
 
 import (
+	"context"
+	"crypto/rsa"
 	"fmt"
+	"io/ioutil"
+	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/byuoitav/authmiddleware"
 	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/accessors/memory"
 	"github.com/byuoitav/configuration-database-microservice/handlers"
+	"github.com/byuoitav/configuration-database-microservice/migrations"
 	"github.com/byuoitav/device-monitoring-microservice/statusinfrastructure"
+	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/jessemillar/health"
 	"github.com/labstack/echo"
 	"github.com/labstack/echo/middleware"
 )
 
 func main() {
-	database := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", os.Getenv("CONFIGURATION_DATABASE_USERNAME"), os.Getenv("CONFIGURATION_DATABASE_PASSWORD"), os.Getenv("CONFIGURATION_DATABASE_HOST"), os.Getenv("CONFIGURATION_DATABASE_PORT"), os.Getenv("CONFIGURATION_DATABASE_NAME"))
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		migrate(os.Args[2:])
+		return
+	}
 
-	// Constructs a new accessor group and connects it to the database
-	accessorGroup := new(accessors.AccessorGroup)
-	accessorGroup.Open(database)
+	store, schemaProblems := openStore()
 
-	// Constructs a new controller group and gives it the accessor group
+	// Constructs a new controller group and gives it the store
 	handlerGroup := new(handlers.HandlerGroup)
-	handlerGroup.Accessors = accessorGroup
+	handlerGroup.Accessors = store
+	handlerGroup.RequireApprover = requireApprover()
 
 	port := ":8006"
 	router := echo.New()
+	router.HTTPErrorHandler = handlers.ErrorHandler
 	router.Pre(middleware.RemoveTrailingSlash())
 	router.Use(middleware.CORS())
+	router.Use(handlers.Timeout(timeouts()))
 
 	// Use the `secure` routing group to require authentication
-	secure := router.Group("", echo.WrapMiddleware(authmiddleware.Authenticate))
+	secure := router.Group("", echo.WrapMiddleware(authmiddleware.Authenticate), handlers.Identify(wso2Key()))
 
 	router.GET("/health", echo.WrapHandler(http.HandlerFunc(health.Check)))
-	router.GET("/mstatus", GetStatus)
+	router.GET("/mstatus", GetStatus(store, schemaProblems))
 
 	secure.GET("/buildings", handlerGroup.GetAllBuildings)
-	secure.GET("/buildings/:id", handlerGroup.GetBuildingByID)
-	secure.GET("/buildings/:shortname", handlerGroup.GetBuildingByShortname)
+	secure.GET("/buildings/id/:id", handlerGroup.GetBuildingByID)
+	secure.GET("/buildings/:building", handlerGroup.GetBuildingByShortname)
 	secure.GET("/buildings/:building/rooms/:room", handlerGroup.GetRoomByBuildingAndName)
 	secure.GET("/buildings/:building/rooms", handlerGroup.GetRoomsByBuilding)
 	secure.GET("/buildings/:building/rooms/:room/devices", handlerGroup.GetDevicesByBuildingAndRoom)
 	secure.GET("/buildings/:building/rooms/:room/devices/roles/:role", handlerGroup.GetDevicesByBuildingAndRoomAndRole)
 	secure.GET("/buildings/:building/rooms/:room/devices/:device", handlerGroup.GetDeviceByBuildingAndRoomAndName)
+	secure.GET("/buildings/:building/rooms/:room/devices/:device/ports", handlerGroup.GetDevicePorts)
+	secure.GET("/buildings/:building/rooms/:room/devices/:device/attributes", handlerGroup.GetDeviceAttributes)
+	secure.GET("/buildings/:building/rooms/:room/paths", handlerGroup.GetRoomPaths)
+	secure.GET("/buildings/:building/rooms/:room/wiring/validate", handlerGroup.ValidateRoomWiring)
+	secure.GET("/buildings/:building/rooms/:room/validate", handlerGroup.ValidateRoom)
+	secure.GET("/buildings/:building/rooms/:room/promotions", handlerGroup.GetRoomPromotions)
 
 	secure.PUT("/buildings/:building/rooms/:room/devices/:device/attributes/:attribute/:value", handlerGroup.PutDeviceAttributeByDeviceAndRoomAndBuilding)
+	secure.PUT("/buildings/:building/rooms/:room/devices/:device/attributes/:attribute", handlerGroup.PutDeviceAttribute)
+	secure.DELETE("/buildings/:building/rooms/:room/devices/:device/attributes/:attribute", handlerGroup.DeleteDeviceAttribute)
 
 	secure.GET("/rooms", handlerGroup.GetAllRooms)
 	secure.GET("/rooms/designations", handlerGroup.GetAllRoomDesignations)
+	secure.GET("/rooms/validate", handlerGroup.ValidateAllRooms)
 	secure.GET("/rooms/id/:id", handlerGroup.GetRoomByID)
 	secure.GET("/rooms/buildings/:building", handlerGroup.GetRoomsByBuilding)
 
@@ -61,10 +84,18 @@ func main() {
 
 	secure.GET("/buildings/:building/rooms/:room/configuration", handlerGroup.GetConfigurationByRoomAndBuilding)
 	secure.GET("/configurations/:configuration", handlerGroup.GetConfigurationByName)
+	secure.POST("/configurations/:configuration", handlerGroup.AddConfiguration)
+	secure.PUT("/configurations/:configuration", handlerGroup.UpdateConfiguration)
+	secure.POST("/configurations/:configuration/evaluators/:evaluator", handlerGroup.AddConfigurationEvaluator)
+	secure.PUT("/configurations/:configuration/evaluators/:evaluator", handlerGroup.UpdateConfigurationEvaluator)
+	secure.DELETE("/configurations/:configuration/evaluators/:evaluator", handlerGroup.DeleteConfigurationEvaluator)
 	secure.GET("/configurations", handlerGroup.GetConfigurations)
 
 	secure.GET("/devices/ports", handlerGroup.GetPorts)
 	secure.GET("/devices/types", handlerGroup.GetDeviceTypes)
+	secure.GET("/devices/types/:devicetype/commands", handlerGroup.GetDeviceTypeCommands)
+	secure.GET("/devices/types/:devicetype/ports", handlerGroup.GetDeviceTypePorts)
+	secure.GET("/devices/types/:devicetype/attributes", handlerGroup.GetDeviceTypeAttributes)
 	secure.GET("/devices/classes", handlerGroup.GetDeviceClasses)
 	secure.GET("/devices/endpoints", handlerGroup.GetEndpoints)
 	secure.GET("/devices/commands", handlerGroup.GetAllCommands)
@@ -72,6 +103,7 @@ func main() {
 	secure.GET("/devices/microservices", handlerGroup.GetMicroservices)
 	secure.GET("/devices/roledefinitions", handlerGroup.GetDeviceRoleDefs)
 	secure.GET("/devices/roledefinitions/:id", handlerGroup.GetDeviceRoleDefsById)
+	secure.GET("/devices/attributes/:attribute", handlerGroup.GetDevicesByAttribute)
 	secure.GET("/devices/:id", handlerGroup.GetDeviceById)
 
 	secure.GET("/classes/:class/ports", handlerGroup.GetPortsByDeviceType)
@@ -80,11 +112,32 @@ func main() {
 	secure.PUT("/devices/attribute", handlerGroup.SetDeviceAttribute)
 
 	secure.POST("/buildings/:building", handlerGroup.AddBuilding)
+	secure.PUT("/buildings/:building", handlerGroup.UpdateBuilding)
+	secure.DELETE("/buildings/:building", handlerGroup.DeleteBuilding)
 	secure.POST("/buildings/:building/rooms/:room", handlerGroup.AddRoom)
+	secure.PUT("/buildings/:building/rooms/:room", handlerGroup.UpdateRoom)
+	secure.DELETE("/buildings/:building/rooms/:room", handlerGroup.DeleteRoom)
+	secure.DELETE("/buildings/:building/rooms/:room/promotions/:promotion", handlerGroup.WithdrawPromotion)
+	secure.POST("/buildings/:building/rooms/:room/promote", handlerGroup.PromoteRoom)
+	secure.POST("/buildings/:building/rooms/:room/promotions/:promotion/approve", handlerGroup.ApprovePromotion)
 	secure.POST("/buildings/:building/rooms/:room/devices/:device", handlerGroup.AddDevice)
+	secure.PUT("/buildings/:building/rooms/:room/devices/:device", handlerGroup.UpdateDevice)
+	secure.DELETE("/buildings/:building/rooms/:room/devices/:device", handlerGroup.DeleteDevice)
+	secure.POST("/buildings/:building/rooms/:room/devices/:device/ports/:port", handlerGroup.AddDevicePort)
+	secure.PUT("/buildings/:building/rooms/:room/devices/:device/ports/:port", handlerGroup.UpdateDevicePort)
+	secure.DELETE("/buildings/:building/rooms/:room/devices/:device/ports/:port", handlerGroup.DeleteDevicePort)
 
 	secure.POST("/devices/ports/:port", handlerGroup.AddPort)
 	secure.POST("/devices/types/:devicetype", handlerGroup.AddDeviceType)
+	secure.POST("/devices/types/:devicetype/commands/:command", handlerGroup.AddDeviceTypeCommand)
+	secure.DELETE("/devices/types/:devicetype/commands/:command", handlerGroup.DeleteDeviceTypeCommand)
+	secure.POST("/devices/types/:devicetype/ports/:port", handlerGroup.AddDeviceTypePort)
+	secure.PUT("/devices/types/:devicetype/ports/:port", handlerGroup.UpdateDeviceTypePort)
+	secure.DELETE("/devices/types/:devicetype/ports/:port", handlerGroup.DeleteDeviceTypePort)
+	secure.PUT("/devices/types/:devicetype/attributes", handlerGroup.SetDeviceTypeAttributes)
+	secure.POST("/devices/types/:devicetype/attributes/:attribute", handlerGroup.AddDeviceTypeAttribute)
+	secure.PUT("/devices/types/:devicetype/attributes/:attribute", handlerGroup.UpdateDeviceTypeAttribute)
+	secure.DELETE("/devices/types/:devicetype/attributes/:attribute", handlerGroup.DeleteDeviceTypeAttribute)
 	secure.POST("/devices/endpoints/:endpoint", handlerGroup.AddEndpoint)
 	secure.POST("/devices/commands/:command", handlerGroup.AddCommand)
 	secure.POST("/devices/powerstates/:powerstate", handlerGroup.AddPowerState)
@@ -93,7 +146,6 @@ func main() {
 
 	//	secure.POST("/buildings/:building/rooms/:room/devices/:device/commands/:id", handlerGroup.AddDeviceCommand)
 	//	secure.POST("/buildings/:building/rooms/:room/devices/:device/powerstates/:id", handlerGroup.AddDevicePowerState)
-	//	secure.POST("/buildings/:building/rooms/:room/devices/:device/portconfiguration/:id", handlerGroup.AddPortConfiguration)
 	//	secure.POST("/buildings/:building/rooms/:room/devices/:device/roles/:id", handlerGroup.AddDeviceRole)
 
 	server := http.Server{
@@ -104,30 +156,205 @@ func main() {
 	router.StartServer(&server)
 }
 
-func GetStatus(context echo.Context) error {
-	var s statusinfrastructure.Status
-	var err error
+// openStore builds the backend picked by CONFIGURATION_DATABASE_BACKEND. MySQL is the default;
+// "sqlite" uses the file at CONFIGURATION_DATABASE_PATH, and "memory" keeps everything in memory,
+// seeded from CONFIGURATION_DATABASE_FIXTURE if it's set. SQL databases have their schema checked;
+// anything wrong with it is returned alongside the store.
+func openStore() (accessors.Store, []string) {
+	if os.Getenv("CONFIGURATION_DATABASE_BACKEND") == "memory" {
+		fixture := os.Getenv("CONFIGURATION_DATABASE_FIXTURE")
+		if len(fixture) == 0 {
+			log.Printf("Using an empty in-memory store")
+			return memory.New(), nil
+		}
+
+		log.Printf("Using an in-memory store seeded from %s", fixture)
+		store, err := memory.NewFromFixture(fixture)
+		if err != nil {
+			log.Fatalf("Could not load fixture %s: %s", fixture, err)
+		}
+
+		return store, nil
+	}
+
+	accessorGroup := openDatabase()
+
+	// A SQLite file is ours alone, so bring it up to date; MySQL is migrated by hand
+	if accessorGroup.Driver == migrations.SQLite {
+		err := migrations.Up(accessorGroup.Database, accessorGroup.Driver)
+		if err != nil {
+			log.Fatalf("Could not migrate the database: %s", err)
+		}
+	}
+
+	return accessorGroup, checkSchema(accessorGroup)
+}
+
+// checkSchema compares the database with the columns the accessors scan.
+// CONFIGURATION_DATABASE_SCHEMA_CHECK picks what happens if they don't match: "strict" (the default)
+// refuses to start, "degraded" starts anyway and reports the problems on /mstatus, and "off" skips the check.
+// Tables that are only missing because their migrations haven't been applied are warned about either way.
+func checkSchema(accessorGroup *accessors.AccessorGroup) []string {
+	mode := os.Getenv("CONFIGURATION_DATABASE_SCHEMA_CHECK")
+	if mode == "off" {
+		return nil
+	}
+
+	problems, pending, err := accessorGroup.VerifySchema(context.Background())
+	if err != nil {
+		// An unreachable database is already reported by /mstatus
+		log.Printf("Could not check the database schema: %s", err)
+		return nil
+	}
+
+	// The routes that use these fail until they're migrated, but the rest work without them
+	if len(pending) > 0 {
+		log.Printf("WARNING: the database is missing tables from migrations that haven't been applied; run `migrate up`:")
+		for _, table := range pending {
+			log.Printf("\t%s", table)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	log.Printf("The database schema doesn't match what the accessors expect:")
+	for _, problem := range problems {
+		log.Printf("\t%s", problem)
+	}
+
+	if mode != "degraded" {
+		log.Fatalf("Refusing to start. Fix the schema (see `migrate status`) or set CONFIGURATION_DATABASE_SCHEMA_CHECK=degraded to start anyway.")
+	}
+
+	log.Printf("Starting in degraded mode")
+	return problems
+}
+
+// timeouts reads how long a request may take before its queries are cancelled.
+// CONFIGURATION_DATABASE_TIMEOUT applies to every route (30s if it isn't set; 0 turns it off), and
+// CONFIGURATION_DATABASE_ROUTE_TIMEOUTS overrides it for individual routes, as a comma separated
+// list of route=duration pairs, e.g. "/deployment/devices/roles/:role/types/:type/:branch=2m".
+func timeouts() (time.Duration, map[string]time.Duration) {
+	fallback := 30 * time.Second
+	if value := os.Getenv("CONFIGURATION_DATABASE_TIMEOUT"); len(value) > 0 {
+		var err error
+		fallback, err = time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("Invalid CONFIGURATION_DATABASE_TIMEOUT: %s", err)
+		}
+	}
+
+	routes := make(map[string]time.Duration)
+	for _, pair := range strings.Split(os.Getenv("CONFIGURATION_DATABASE_ROUTE_TIMEOUTS"), ",") {
+		if len(strings.TrimSpace(pair)) == 0 {
+			continue
+		}
+
+		separator := strings.LastIndex(pair, "=")
+		if separator < 0 {
+			log.Fatalf("Invalid CONFIGURATION_DATABASE_ROUTE_TIMEOUTS entry %q: expected route=duration", pair)
+		}
+
+		timeout, err := time.ParseDuration(strings.TrimSpace(pair[separator+1:]))
+		if err != nil {
+			log.Fatalf("Invalid CONFIGURATION_DATABASE_ROUTE_TIMEOUTS entry %q: %s", pair, err)
+		}
+
+		routes[strings.TrimSpace(pair[:separator])] = timeout
+	}
+
+	return fallback, routes
+}
+
+// wso2Key reads the key WSO2 signs its JWT assertions with from the PEM file (its certificate
+// or public key) at CONFIGURATION_DATABASE_WSO2_KEY. Without it no assertion is trusted, so
+// nobody can be identified to make or approve a promotion.
+func wso2Key() *rsa.PublicKey {
+	path := os.Getenv("CONFIGURATION_DATABASE_WSO2_KEY")
+	if len(path) == 0 {
+		log.Printf("CONFIGURATION_DATABASE_WSO2_KEY isn't set, so nobody can be identified and promotions will be refused")
+		return nil
+	}
 
-	s.Version, err = statusinfrastructure.GetVersion("version.txt")
+	contents, err := ioutil.ReadFile(path)
 	if err != nil {
-		return context.JSON(http.StatusOK, "Failed to open version.txt")
+		log.Fatalf("Couldn't read CONFIGURATION_DATABASE_WSO2_KEY: %s", err)
+	}
+
+	key, err := jwt.ParseRSAPublicKeyFromPEM(contents)
+	if err != nil {
+		log.Fatalf("Invalid CONFIGURATION_DATABASE_WSO2_KEY: %s", err)
+	}
+
+	return key
+}
+
+// requireApprover reads CONFIGURATION_DATABASE_REQUIRE_APPROVER, which makes promoting a room to
+// production take a second person's approval. It's off if it isn't set.
+func requireApprover() bool {
+	value := os.Getenv("CONFIGURATION_DATABASE_REQUIRE_APPROVER")
+	if len(value) == 0 {
+		return false
 	}
 
-	// open new? database connection
-	database := os.Getenv("CONFIGURATION_DATABASE_USERNAME") + ":" + os.Getenv("CONFIGURATION_DATABASE_PASSWORD") + "@tcp(" + os.Getenv("CONFIGURATION_DATABASE_HOST") + ":" + os.Getenv("CONFIGURATION_DATABASE_PORT") + ")/" + os.Getenv("CONFIGURATION_DATABASE_NAME")
+	require, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatalf("Invalid CONFIGURATION_DATABASE_REQUIRE_APPROVER: %s", err)
+	}
+
+	return require
+}
+
+// openDatabase connects to the SQL database picked by CONFIGURATION_DATABASE_BACKEND
+func openDatabase() *accessors.AccessorGroup {
+	// Constructs a new accessor group and connects it to the database
 	accessorGroup := new(accessors.AccessorGroup)
-	accessorGroup.Open(database)
 
-	vals, err := accessorGroup.GetAllBuildings()
-	if len(vals) < 1 || err != nil {
-		s.Status = statusinfrastructure.StatusDead
-		s.StatusInfo = fmt.Sprintf("Unable to access database. Error: %s", err)
-	} else {
-		s.Status = statusinfrastructure.StatusOK
-		s.StatusInfo = ""
+	switch os.Getenv("CONFIGURATION_DATABASE_BACKEND") {
+	case "memory":
+		log.Fatalf("The memory backend doesn't have a database")
+
+	case "sqlite":
+		path := os.Getenv("CONFIGURATION_DATABASE_PATH")
+		if len(path) == 0 {
+			log.Fatalf("CONFIGURATION_DATABASE_PATH must be set to use the sqlite backend")
+		}
+
+		log.Printf("Using the SQLite database at %s", path)
+		accessorGroup.OpenSQLite(path)
+
+	default:
+		database := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", os.Getenv("CONFIGURATION_DATABASE_USERNAME"), os.Getenv("CONFIGURATION_DATABASE_PASSWORD"), os.Getenv("CONFIGURATION_DATABASE_HOST"), os.Getenv("CONFIGURATION_DATABASE_PORT"), os.Getenv("CONFIGURATION_DATABASE_NAME"))
+		accessorGroup.Open(database)
 	}
 
-	accessorGroup.Database.Close()
+	return accessorGroup
+}
+
+func GetStatus(store accessors.Store, schemaProblems []string) echo.HandlerFunc {
+	return func(context echo.Context) error {
+		var s statusinfrastructure.Status
+		var err error
 
-	return context.JSON(http.StatusOK, s)
+		s.Version, err = statusinfrastructure.GetVersion("version.txt")
+		if err != nil {
+			return context.JSON(http.StatusOK, "Failed to open version.txt")
+		}
+
+		vals, err := store.GetAllBuildings(context.Request().Context())
+		if len(vals) < 1 || err != nil {
+			s.Status = statusinfrastructure.StatusDead
+			s.StatusInfo = fmt.Sprintf("Unable to access database. Error: %s", err)
+		} else if len(schemaProblems) > 0 {
+			s.Status = statusinfrastructure.StatusSick
+			s.StatusInfo = fmt.Sprintf("Database schema doesn't match: %s", strings.Join(schemaProblems, "; "))
+		} else {
+			s.Status = statusinfrastructure.StatusOK
+			s.StatusInfo = ""
+		}
+
+		return context.JSON(http.StatusOK, s)
+	}
 }
@@ -3,6 +3,8 @@ Defines structs that get passed over the entire project
 **/
 package structs
 
+import "fmt"
+
 type Building struct {
 	ID          int    `json:"id,omitempty"`
 	Name        string `json:"name,omitempty"`
@@ -96,6 +98,8 @@ type Device struct {
 	Responding  bool      `json:"responding"`
 	Ports       []Port    `json:"ports,omitempty"`
 	Commands    []Command `json:"commands,omitempty"`
+
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
 }
 
 //GetFullName reutrns the string of building + room + name
@@ -175,6 +179,29 @@ type DeviceAttributeInfo struct {
 	AttributeType  string `json:"attributeType"`
 }
 
+//DeviceAttribute is a named setting on one device, e.g. an input delay or a serial baud rate.
+//Type is one of string, int, float or bool; Value holds a value of that type. IsDefault marks
+//an attribute the device only has because its type gives it a default.
+type DeviceAttribute struct {
+	Name      string      `json:"name"`
+	Type      string      `json:"type,omitempty"`
+	Value     interface{} `json:"value"`
+	IsDefault bool        `json:"default,omitempty"`
+}
+
+//DeviceTypeAttribute is one attribute a device type allows its devices to have, and the rules
+//its values follow. Minimum and Maximum only apply to ints and floats. A device of the type
+//that hasn't set the attribute reads as having Default, if there is one.
+type DeviceTypeAttribute struct {
+	Name        string        `json:"name"`
+	Type        string        `json:"type"`
+	Description string        `json:"description,omitempty"`
+	Minimum     *float64      `json:"minimum,omitempty"`
+	Maximum     *float64      `json:"maximum,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+	Default     interface{}   `json:"default,omitempty"`
+}
+
 type DeviceRole struct {
 	ID                     int `json:"id,omitempty"`
 	DeviceID               int `json:"device"`
@@ -204,6 +231,39 @@ type Port struct {
 	Host        string `json:"host"`
 }
 
+//SignalPath is one route a signal can take from one device in a room to another. Each hop is a
+//configured port the host has to select to pass the signal from its source to its destination.
+//Devices lists every device the signal goes through in order, including switchers that host a
+//hop without being its source or destination.
+type SignalPath struct {
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Devices []string `json:"devices"`
+	Hops    []Port   `json:"hops"`
+}
+
+//SignalPaths are the routes GetRoomPaths found, shortest first. Truncated says there were more
+//than it returns.
+type SignalPaths struct {
+	Paths     []SignalPath `json:"paths"`
+	Truncated bool         `json:"truncated"`
+}
+
+//RoomPort is a row of PortConfiguration that touches a room: its host, source or destination is
+//in it. Each device is given by name and the room (building-room) it's in; the names of devices
+//the row points at that don't exist are empty.
+type RoomPort struct {
+	ID              int    `json:"id"`
+	Port            string `json:"port"`
+	Source          string `json:"source"`
+	SourceRoom      string `json:"sourceRoom"`
+	Destination     string `json:"destination"`
+	DestinationRoom string `json:"destinationRoom"`
+	Host            string `json:"host"`
+	HostRoom        string `json:"hostRoom"`
+	HostType        string `json:"hostType"`
+}
+
 type PortConfiguration struct {
 	ID                  int `json:"id,omitempty"`
 	DestinationDeviceID int `json:"destination-device"`
@@ -245,6 +305,22 @@ type DeviceTypePort struct {
 	HostDestintionMirror bool     `json:"mirror-host-dest"`
 }
 
+//DeviceTypeCommand maps a command to the microservice endpoint that carries it out, for every
+//device of a type. Everything is referred to by name.
+type DeviceTypeCommand struct {
+	Command      string `json:"command"`
+	Endpoint     string `json:"endpoint"`
+	Microservice string `json:"microservice"`
+}
+
+//DeviceTypeCommands lists the commands a device type supports and the devices (building-room-name)
+//that get them
+type DeviceTypeCommands struct {
+	Type     string              `json:"type"`
+	Commands []DeviceTypeCommand `json:"commands"`
+	Devices  []string            `json:"devices"`
+}
+
 type PowerState struct {
 	ID          int    `json:"id,omitempty"`
 	Name        string `json:"name"`
@@ -262,3 +338,81 @@ type Room struct {
 	Configuration   RoomConfiguration `json:"configuration"`
 	RoomDesignation string            `json:"roomDesignation"`
 }
+
+//Promotion records a room being moved from one designation to another, e.g. from stage to
+//production: who moved it, who approved it if anyone had to, and when (RFC 3339, in UTC). One
+//that needs approval stays pending, and leaves the room alone, until someone else approves it.
+type Promotion struct {
+	ID         int    `json:"id"`
+	Building   string `json:"building"`
+	Room       string `json:"room"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Status     string `json:"status"`
+	PromotedBy string `json:"promotedBy"`
+	PromotedAt string `json:"promotedAt"`
+	ApprovedBy string `json:"approvedBy,omitempty"`
+	ApprovedAt string `json:"approvedAt,omitempty"`
+}
+
+//What has become of a Promotion
+const (
+	PromotionPending   = "pending"
+	PromotionApplied   = "applied"
+	PromotionWithdrawn = "withdrawn"
+)
+
+//How serious a Finding is
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+//Finding is one problem a check found in a room's configuration. An error is something that
+//breaks the room; a warning is worth a look. Check names the check that found it, and Device the
+//device it's about, if it's about one.
+type Finding struct {
+	Severity string `json:"severity"`
+	Check    string `json:"check"`
+	Device   string `json:"device,omitempty"`
+	Message  string `json:"message"`
+}
+
+//NewFinding builds a Finding, formatting its message from format and args
+func NewFinding(severity string, check string, device string, format string, args ...interface{}) Finding {
+	return Finding{Severity: severity, Check: check, Device: device, Message: fmt.Sprintf(format, args...)}
+}
+
+//LintReport is what validating many rooms at once found, grouped by building. Rooms counts the
+//rooms checked, and BrokenRooms the ones with at least one error.
+type LintReport struct {
+	Designation string         `json:"roomDesignation,omitempty"`
+	Rooms       int            `json:"rooms"`
+	BrokenRooms int            `json:"brokenRooms"`
+	Buildings   []BuildingLint `json:"buildings"`
+}
+
+//BuildingLint is one building's part of a LintReport. BrokenRooms names the rooms with errors,
+//and Findings groups what was found by severity.
+type BuildingLint struct {
+	Building    string                   `json:"building"`
+	Rooms       int                      `json:"rooms"`
+	BrokenRooms []string                 `json:"brokenRooms"`
+	Findings    map[string][]RoomFinding `json:"findings"`
+}
+
+//RoomFinding is a Finding and the room it was found in
+type RoomFinding struct {
+	Room            string `json:"room"`
+	RoomDesignation string `json:"roomDesignation"`
+	Finding
+}
+
+//Error is the body of every error response. Kind says what went wrong (not_found, conflict,
+//validation, unavailable, ...) so clients can branch on it instead of the message.
+type Error struct {
+	Status  int      `json:"status"`
+	Kind    string   `json:"kind"`
+	Message string   `json:"message"`
+	Details []string `json:"details,omitempty"`
+}
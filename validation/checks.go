@@ -0,0 +1,155 @@
+package validation
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// The checks Check runs, as they're named in its findings
+const (
+	CheckPowerStates   = "device-power-states"
+	CheckChangeInput   = "device-change-input"
+	CheckAddress       = "device-address"
+	CheckRole          = "room-role"
+	CheckConfiguration = "room-configuration"
+	CheckPriority      = "configuration-priority"
+)
+
+// requiredRoles are the roles some device in every room has to have
+var requiredRoles = []string{"ControlProcessor", "Touchpanel"}
+
+// displayClasses are the device classes that are displays, whatever their roles say
+var displayClasses = []string{"display", "tv", "projector", "monitor", "videowall"}
+
+// Check looks for mistakes in a room's devices and configuration. The room's devices need their
+// commands, power states and roles; configured says whether the room's configurationID points at
+// a RoomConfiguration, which room.Configuration is.
+func Check(room structs.Room, configured bool) []structs.Finding {
+	findings := []structs.Finding{}
+
+	for _, device := range room.Devices {
+		findings = append(findings, deviceProblems(device)...)
+	}
+
+	findings = append(findings, missingRoles(room.Devices)...)
+
+	if !configured {
+		findings = append(findings, structs.NewFinding(structs.SeverityError, CheckConfiguration, "", "configuration %d doesn't exist", room.ConfigurationID))
+	} else {
+		findings = append(findings, duplicatePriorities(room.Configuration)...)
+	}
+
+	return findings
+}
+
+// deviceProblems checks one device on its own
+func deviceProblems(device structs.Device) []structs.Finding {
+	findings := []structs.Finding{}
+
+	if isDisplay(device) && len(device.PowerStates) == 0 {
+		findings = append(findings, structs.NewFinding(structs.SeverityError, CheckPowerStates, device.Name, "%s is a display but has no power states", device.Name))
+	}
+
+	if hasRole(device, "VideoOut") && !hasCommand(device, "ChangeInput") {
+		if len(device.Class) == 0 {
+			findings = append(findings, structs.NewFinding(structs.SeverityError, CheckChangeInput, device.Name, "%s is a VideoOut device but has no type, so no ChangeInput command", device.Name))
+		} else {
+			findings = append(findings, structs.NewFinding(structs.SeverityError, CheckChangeInput, device.Name, "%s is a VideoOut device but its type, %s, has no ChangeInput command", device.Name, device.Class))
+		}
+	}
+
+	// a device the API sends commands to has to be reachable; one it doesn't may be fine without
+	if len(strings.TrimSpace(device.Address)) == 0 {
+		if len(device.Commands) > 0 {
+			findings = append(findings, structs.NewFinding(structs.SeverityError, CheckAddress, device.Name, "%s has commands but no address", device.Name))
+		} else {
+			findings = append(findings, structs.NewFinding(structs.SeverityWarning, CheckAddress, device.Name, "%s has no address", device.Name))
+		}
+	}
+
+	return findings
+}
+
+// missingRoles reports each required role no device in the room has
+func missingRoles(devices []structs.Device) []structs.Finding {
+	findings := []structs.Finding{}
+
+	for _, role := range requiredRoles {
+		found := false
+		for _, device := range devices {
+			if hasRole(device, role) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			findings = append(findings, structs.NewFinding(structs.SeverityError, CheckRole, "", "no device in the room has the %s role", role))
+		}
+	}
+
+	return findings
+}
+
+// duplicatePriorities reports each priority more than one of a configuration's evaluators has,
+// since the order they run in is then up to the database
+func duplicatePriorities(config structs.RoomConfiguration) []structs.Finding {
+	keys := make(map[int][]string)
+	for _, evaluator := range config.Evaluators {
+		keys[evaluator.Priority] = append(keys[evaluator.Priority], evaluator.EvaluatorKey)
+	}
+
+	priorities := []int{}
+	for priority, shared := range keys {
+		if len(shared) > 1 {
+			priorities = append(priorities, priority)
+		}
+	}
+	sort.Ints(priorities)
+
+	findings := []structs.Finding{}
+	for _, priority := range priorities {
+		sort.Strings(keys[priority])
+		findings = append(findings, structs.NewFinding(structs.SeverityError, CheckPriority, "", "%s evaluators %s all have priority %d", config.Name, strings.Join(keys[priority], ", "), priority))
+	}
+
+	return findings
+}
+
+// isDisplay says whether a device shows video: it has the VideoOut role, or its class is one of
+// displayClasses
+func isDisplay(device structs.Device) bool {
+	if hasRole(device, "VideoOut") {
+		return true
+	}
+
+	for _, class := range displayClasses {
+		if strings.EqualFold(strings.TrimSpace(device.Type), class) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasRole(device structs.Device, role string) bool {
+	for _, r := range device.Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasCommand(device structs.Device, command string) bool {
+	for _, c := range device.Commands {
+		if c.Name == command {
+			return true
+		}
+	}
+
+	return false
+}
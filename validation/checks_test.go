@@ -0,0 +1,154 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// ready is a device that passes every device check
+func ready(name string, class string, roles ...string) structs.Device {
+	return structs.Device{
+		Name:        name,
+		Address:     name + ".byu.edu",
+		Type:        class,
+		Class:       "SonyXBR",
+		Roles:       roles,
+		PowerStates: []string{"On", "Standby"},
+		Commands:    []structs.Command{{Name: "ChangeInput"}},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	config := structs.RoomConfiguration{
+		Name: "Default",
+		Evaluators: []structs.ConfigurationEvaluator{
+			{EvaluatorKey: "PowerOnDefault", Priority: 1},
+			{EvaluatorKey: "StandbyDefault", Priority: 9999},
+		},
+	}
+
+	tests := []struct {
+		name string
+		// change is made to a room that passes every check: D1, a display, and CP1, which
+		// controls it
+		change     func(room *structs.Room)
+		configured bool
+		// want lists the findings' severities, checks and devices
+		want [][3]string
+	}{
+		{
+			name:       "ready",
+			change:     func(room *structs.Room) {},
+			configured: true,
+			want:       [][3]string{},
+		},
+		{
+			name: "a tv without power states",
+			change: func(room *structs.Room) {
+				room.Devices[0].Roles = []string{"AudioOut"}
+				room.Devices[0].PowerStates = nil
+			},
+			configured: true,
+			want:       [][3]string{{structs.SeverityError, CheckPowerStates, "D1"}},
+		},
+		{
+			name: "a projector without power states",
+			change: func(room *structs.Room) {
+				room.Devices = append(room.Devices, ready("D2", "Projector"))
+				room.Devices[2].PowerStates = nil
+			},
+			configured: true,
+			want:       [][3]string{{structs.SeverityError, CheckPowerStates, "D2"}},
+		},
+		{
+			name: "a VideoOut device of another class without power states",
+			change: func(room *structs.Room) {
+				room.Devices = append(room.Devices, ready("LED1", "led-wall", "VideoOut"))
+				room.Devices[2].PowerStates = nil
+			},
+			configured: true,
+			want:       [][3]string{{structs.SeverityError, CheckPowerStates, "LED1"}},
+		},
+		{
+			name: "something else without power states",
+			change: func(room *structs.Room) {
+				room.Devices = append(room.Devices, ready("PC1", "computer", "VideoIn"))
+				room.Devices[2].PowerStates = nil
+			},
+			configured: true,
+			want:       [][3]string{},
+		},
+		{
+			name:       "a VideoOut device that can't change input",
+			change:     func(room *structs.Room) { room.Devices[0].Commands = []structs.Command{{Name: "PowerOn"}} },
+			configured: true,
+			want:       [][3]string{{structs.SeverityError, CheckChangeInput, "D1"}},
+		},
+		{
+			name:       "a device with commands but no address",
+			change:     func(room *structs.Room) { room.Devices[0].Address = " " },
+			configured: true,
+			want:       [][3]string{{structs.SeverityError, CheckAddress, "D1"}},
+		},
+		{
+			name: "a device with no commands or address",
+			change: func(room *structs.Room) {
+				room.Devices[1].Address = ""
+				room.Devices[1].Commands = nil
+			},
+			configured: true,
+			want:       [][3]string{{structs.SeverityWarning, CheckAddress, "CP1"}},
+		},
+		{
+			name:       "no touchpanel",
+			change:     func(room *structs.Room) { room.Devices[1].Roles = []string{"ControlProcessor"} },
+			configured: true,
+			want:       [][3]string{{structs.SeverityError, CheckRole, ""}},
+		},
+		{
+			name:       "no devices",
+			change:     func(room *structs.Room) { room.Devices = []structs.Device{} },
+			configured: true,
+			want:       [][3]string{{structs.SeverityError, CheckRole, ""}, {structs.SeverityError, CheckRole, ""}},
+		},
+		{
+			name:       "a configuration that doesn't exist",
+			change:     func(room *structs.Room) {},
+			configured: false,
+			want:       [][3]string{{structs.SeverityError, CheckConfiguration, ""}},
+		},
+		{
+			name: "evaluators that share a priority",
+			change: func(room *structs.Room) {
+				room.Configuration.Evaluators = append(room.Configuration.Evaluators, structs.ConfigurationEvaluator{EvaluatorKey: "ChangeVideoInputDefault", Priority: 1})
+			},
+			configured: true,
+			want:       [][3]string{{structs.SeverityError, CheckPriority, ""}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			room := structs.Room{
+				Devices: []structs.Device{
+					ready("D1", "tv", "VideoOut", "AudioOut"),
+					ready("CP1", "pi", "ControlProcessor", "Touchpanel"),
+				},
+				Configuration: config,
+			}
+			room.Configuration.Evaluators = append([]structs.ConfigurationEvaluator(nil), config.Evaluators...)
+			test.change(&room)
+
+			got := [][3]string{}
+			for _, finding := range Check(room, test.configured) {
+				got = append(got, [3]string{finding.Severity, finding.Check, finding.Device})
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package validation
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// severities are the severities a finding can have, most serious first
+var severities = []string{structs.SeverityError, structs.SeverityWarning}
+
+// Lint runs every check on every room with the given designation, however it's capitalized
+// (every room at all if it's empty), grouping what they found by building and severity. It loads every room's devices and
+// port configuration together, and each configuration and device type's ports once, rather than
+// querying room by room.
+func Lint(ctx context.Context, store accessors.Store, designation string) (structs.LintReport, error) {
+	report := structs.LintReport{Designation: designation, Buildings: []structs.BuildingLint{}}
+
+	buildings, err := store.GetAllBuildings(ctx)
+	if err != nil {
+		return structs.LintReport{}, err
+	}
+
+	shortnames := make(map[int]string)
+	for _, building := range buildings {
+		shortnames[building.ID] = building.Shortname
+	}
+
+	rooms, err := store.GetAllRooms(ctx)
+	if err != nil {
+		return structs.LintReport{}, err
+	}
+
+	sort.Slice(rooms, func(i, j int) bool {
+		a, b := shortnames[rooms[i].Building.ID], shortnames[rooms[j].Building.ID]
+		if a != b {
+			return a < b
+		}
+		return rooms[i].Name < rooms[j].Name
+	})
+
+	// every room's devices and ports are loaded at once, rather than room by room
+	devices, err := store.WithExpansion(expansion).GetAllDevices(ctx)
+	if err != nil {
+		return structs.LintReport{}, err
+	}
+
+	roomDevices := make(map[int][]structs.Device)
+	for _, device := range devices {
+		roomDevices[device.Room.ID] = append(roomDevices[device.Room.ID], device)
+	}
+
+	ports, err := store.GetAllPortConfiguration(ctx)
+	if err != nil {
+		return structs.LintReport{}, err
+	}
+
+	roomPorts := make(map[string][]structs.RoomPort)
+	for _, port := range ports {
+		for _, room := range portRooms(port) {
+			roomPorts[room] = append(roomPorts[room], port)
+		}
+	}
+
+	load := newLoader(store)
+
+	indexes := make(map[string]int)
+	for _, room := range rooms {
+		if len(designation) > 0 && !strings.EqualFold(strings.TrimSpace(room.RoomDesignation), strings.TrimSpace(designation)) {
+			continue
+		}
+
+		shortname := shortnames[room.Building.ID]
+
+		room.Devices = roomDevices[room.ID]
+		if room.Devices == nil {
+			room.Devices = []structs.Device{}
+		}
+
+		findings, err := load.checkRoom(ctx, shortname, room, roomPorts[shortname+"-"+room.Name])
+		if err != nil {
+			return structs.LintReport{}, err
+		}
+
+		index, ok := indexes[shortname]
+		if !ok {
+			index = len(report.Buildings)
+			indexes[shortname] = index
+			report.Buildings = append(report.Buildings, structs.BuildingLint{
+				Building:    shortname,
+				BrokenRooms: []string{},
+				Findings:    make(map[string][]structs.RoomFinding),
+			})
+		}
+		building := &report.Buildings[index]
+
+		broken := false
+		for _, finding := range findings {
+			building.Findings[finding.Severity] = append(building.Findings[finding.Severity], structs.RoomFinding{
+				Room:            room.Name,
+				RoomDesignation: room.RoomDesignation,
+				Finding:         finding,
+			})
+
+			if finding.Severity == structs.SeverityError {
+				broken = true
+			}
+		}
+
+		building.Rooms++
+		report.Rooms++
+		if broken {
+			building.BrokenRooms = append(building.BrokenRooms, room.Name)
+			report.BrokenRooms++
+		}
+	}
+
+	return report, nil
+}
+
+// portRooms returns the rooms, by building-room, that a port configuration touches
+func portRooms(port structs.RoomPort) []string {
+	rooms := []string{}
+	for _, room := range []string{port.HostRoom, port.SourceRoom, port.DestinationRoom} {
+		if len(room) > 0 && !contains(rooms, room) {
+			rooms = append(rooms, room)
+		}
+	}
+
+	return rooms
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WriteLintCSV writes a report as CSV, one finding per line, errors before warnings in each
+// building
+func WriteLintCSV(w io.Writer, report structs.LintReport) error {
+	out := csv.NewWriter(w)
+
+	err := out.Write([]string{"building", "room", "roomDesignation", "severity", "check", "device", "message"})
+	if err != nil {
+		return err
+	}
+
+	for _, building := range report.Buildings {
+		for _, severity := range severities {
+			for _, finding := range building.Findings[severity] {
+				err = out.Write([]string{building.Building, finding.Room, finding.RoomDesignation, finding.Severity, finding.Check, finding.Device, finding.Message})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
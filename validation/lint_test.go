@@ -0,0 +1,106 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"reflect"
+	"testing"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name        string
+		designation string
+		// rooms and broken are the rooms checked and the ones with errors
+		rooms  []string
+		broken []string
+	}{
+		{name: "every room", designation: "", rooms: []string{"1001D", "1101", "1102"}, broken: []string{"1001D", "1102"}},
+		{name: "production", designation: "production", rooms: []string{"1101"}, broken: []string{}},
+		{name: "capitalized", designation: "Production", rooms: []string{"1101"}, broken: []string{}},
+		{name: "with spaces", designation: " production ", rooms: []string{"1101"}, broken: []string{}},
+		{name: "stored with spaces and capitals", designation: "stage", rooms: []string{"1001D", "1102"}, broken: []string{"1001D", "1102"}},
+		{name: "no rooms", designation: "development", rooms: []string{}, broken: []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newFixtureStore(t)
+
+			_, err := store.AddRoom(ctx, "ITB", structs.Room{Name: "1102", ConfigurationID: 1, RoomDesignation: " Stage"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			report, err := Lint(ctx, store, test.designation)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rooms := []string{}
+			broken := []string{}
+			for _, building := range report.Buildings {
+				for _, severity := range severities {
+					for _, finding := range building.Findings[severity] {
+						if !contains(rooms, finding.Room) {
+							rooms = append(rooms, finding.Room)
+						}
+					}
+				}
+				broken = append(broken, building.BrokenRooms...)
+			}
+
+			if report.Rooms != len(test.rooms) || report.BrokenRooms != len(test.broken) {
+				t.Errorf("checked %d rooms with %d broken, want %d with %d broken", report.Rooms, report.BrokenRooms, len(test.rooms), len(test.broken))
+			}
+			if !reflect.DeepEqual(broken, test.broken) {
+				t.Errorf("broken rooms = %v, want %v", broken, test.broken)
+			}
+			for _, room := range rooms {
+				if !contains(test.rooms, room) {
+					t.Errorf("%s was checked, but isn't %q", room, test.designation)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteLintCSV(t *testing.T) {
+	report := structs.LintReport{
+		Buildings: []structs.BuildingLint{{
+			Building: "ITB",
+			Findings: map[string][]structs.RoomFinding{
+				structs.SeverityWarning: {
+					{Room: "1101", RoomDesignation: "production", Finding: structs.NewFinding(structs.SeverityWarning, CheckAddress, "PC1", "PC1 has no address")},
+				},
+				structs.SeverityError: {
+					{Room: "1101", RoomDesignation: "production", Finding: structs.NewFinding(structs.SeverityError, CheckPowerStates, "D1, the TV", "D1 is a \"display\" but has no power states,\nnone at all")},
+				},
+			},
+		}},
+	}
+
+	var body bytes.Buffer
+	err := WriteLintCSV(&body, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&body).ReadAll()
+	if err != nil {
+		t.Fatalf("reading it back: %s", err)
+	}
+
+	want := [][]string{
+		{"building", "room", "roomDesignation", "severity", "check", "device", "message"},
+		{"ITB", "1101", "production", structs.SeverityError, CheckPowerStates, "D1, the TV", "D1 is a \"display\" but has no power states,\nnone at all"},
+		{"ITB", "1101", "production", structs.SeverityWarning, CheckAddress, "PC1", "PC1 has no address"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("got %q, want %q", records, want)
+	}
+}
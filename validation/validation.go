@@ -0,0 +1,168 @@
+/*
+Package validation checks that a room is configured well enough for the AV API to run it: its
+displays can be turned on and switched, the API can reach its devices, it has a touchpanel and
+something to control it, its configuration exists and orders its evaluators, and its wiring
+(checked by package wiring) makes sense.
+*/
+package validation
+
+import (
+	"context"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+	"github.com/byuoitav/configuration-database-microservice/wiring"
+)
+
+// expansion is the device relations the checks look at
+var expansion = accessors.Expand{Commands: true, PowerStates: true, Roles: true}
+
+// Room loads a room from store and runs every check on it, returning what they found: first the
+// room's devices and configuration, then its wiring
+func Room(ctx context.Context, store accessors.Store, buildingShortname string, roomName string) ([]structs.Finding, error) {
+	room, err := roomRow(ctx, store, buildingShortname, roomName)
+	if err != nil {
+		return []structs.Finding{}, err
+	}
+
+	room.Devices, err = store.WithExpansion(expansion).GetDevicesByBuildingAndRoom(ctx, buildingShortname, roomName)
+	if err != nil {
+		return []structs.Finding{}, err
+	}
+
+	ports, err := store.GetRoomPortConfiguration(ctx, buildingShortname, roomName)
+	if err != nil {
+		return []structs.Finding{}, err
+	}
+
+	return newLoader(store).checkRoom(ctx, buildingShortname, room, ports)
+}
+
+// Wiring loads a room's port configuration, and the ports its hosts' types have, from store and
+// checks them. devices are the room's devices, with their roles.
+func Wiring(ctx context.Context, store accessors.Store, buildingShortname string, roomName string, devices []structs.Device) ([]structs.Finding, error) {
+	ports, err := store.GetRoomPortConfiguration(ctx, buildingShortname, roomName)
+	if err != nil {
+		return []structs.Finding{}, err
+	}
+
+	typePorts, err := newLoader(store).typePorts(ctx, ports)
+	if err != nil {
+		return []structs.Finding{}, err
+	}
+
+	return wiring.Check(wiring.Room{
+		Name:      buildingShortname + "-" + roomName,
+		Devices:   devices,
+		Ports:     ports,
+		TypePorts: typePorts,
+	}), nil
+}
+
+// loader loads the rest of what the checks need from a store, keeping the configurations and the
+// device types' ports it has loaded, which many rooms share
+type loader struct {
+	store          accessors.Store
+	configurations map[int]configuration
+	ports          map[string][]string
+}
+
+// configuration is a RoomConfiguration as loader found it, or didn't
+type configuration struct {
+	structs.RoomConfiguration
+	exists bool
+}
+
+func newLoader(store accessors.Store) *loader {
+	return &loader{
+		store:          store,
+		configurations: make(map[int]configuration),
+		ports:          make(map[string][]string),
+	}
+}
+
+// checkRoom runs every check on a room, as it comes from the Rooms table with its devices (and
+// their commands, power states and roles) filled in. ports are the port configurations that touch
+// it.
+func (l *loader) checkRoom(ctx context.Context, buildingShortname string, room structs.Room, ports []structs.RoomPort) ([]structs.Finding, error) {
+	config, err := l.configuration(ctx, room.ConfigurationID)
+	if err != nil {
+		return []structs.Finding{}, err
+	}
+	room.Configuration = config.RoomConfiguration
+
+	typePorts, err := l.typePorts(ctx, ports)
+	if err != nil {
+		return []structs.Finding{}, err
+	}
+
+	findings := Check(room, config.exists)
+
+	return append(findings, wiring.Check(wiring.Room{
+		Name:      buildingShortname + "-" + room.Name,
+		Devices:   room.Devices,
+		Ports:     ports,
+		TypePorts: typePorts,
+	})...), nil
+}
+
+func (l *loader) configuration(ctx context.Context, id int) (configuration, error) {
+	if config, ok := l.configurations[id]; ok {
+		return config, nil
+	}
+
+	config, err := l.store.GetConfigurationByConfigurationID(ctx, id)
+	if accessors.KindOf(err) == accessors.NotFound {
+		l.configurations[id] = configuration{}
+		return configuration{}, nil
+	} else if err != nil {
+		return configuration{}, err
+	}
+
+	l.configurations[id] = configuration{RoomConfiguration: config, exists: true}
+	return l.configurations[id], nil
+}
+
+// typePorts lists the names of the ports each host's type has in DeviceTypePorts, by type
+func (l *loader) typePorts(ctx context.Context, ports []structs.RoomPort) (map[string][]string, error) {
+	typePorts := make(map[string][]string)
+	for _, port := range ports {
+		if _, ok := typePorts[port.HostType]; ok || len(port.HostType) == 0 {
+			continue
+		}
+
+		names, ok := l.ports[port.HostType]
+		if !ok {
+			declared, err := l.store.GetPortsByDeviceTypeName(ctx, port.HostType)
+			if err != nil {
+				return nil, err
+			}
+
+			names = []string{}
+			for _, typePort := range declared {
+				names = append(names, typePort.Port.Name)
+			}
+			l.ports[port.HostType] = names
+		}
+
+		typePorts[port.HostType] = names
+	}
+
+	return typePorts, nil
+}
+
+// roomRow finds a room without its configuration, which GetRoomByBuildingAndName fails without
+func roomRow(ctx context.Context, store accessors.Store, buildingShortname string, roomName string) (structs.Room, error) {
+	rooms, err := store.GetRoomsByBuilding(ctx, buildingShortname)
+	if err != nil {
+		return structs.Room{}, err
+	}
+
+	for _, room := range rooms {
+		if room.Name == roomName {
+			return room, nil
+		}
+	}
+
+	return structs.Room{}, accessors.Errorf(accessors.NotFound, "No rooms found with that name.")
+}
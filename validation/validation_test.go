@@ -0,0 +1,138 @@
+package validation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/byuoitav/configuration-database-microservice/accessors"
+	"github.com/byuoitav/configuration-database-microservice/accessors/memory"
+	"github.com/byuoitav/configuration-database-microservice/structs"
+	"github.com/byuoitav/configuration-database-microservice/wiring"
+)
+
+// newFixtureStore loads docs/fixture.json, where ITB-1101 passes every check and ITB-1001D has
+// a display and nothing to control it
+func newFixtureStore(t *testing.T) *memory.Store {
+	store, err := memory.NewFromFixture("../docs/fixture.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store
+}
+
+// updateDevice makes change to a device in ITB-1101 and saves it
+func updateDevice(name string, change func(d *structs.Device)) func(ctx context.Context, store accessors.Store) error {
+	return func(ctx context.Context, store accessors.Store) error {
+		d, err := store.GetDeviceByBuildingAndRoomAndName(ctx, "ITB", "1101", name)
+		if err != nil {
+			return err
+		}
+
+		change(&d)
+
+		_, err = store.UpdateDevice(ctx, "ITB", "1101", name, d)
+		return err
+	}
+}
+
+func TestRoom(t *testing.T) {
+	tests := []struct {
+		name   string
+		room   string
+		change func(ctx context.Context, store accessors.Store) error
+		// want lists the findings' checks and devices
+		want [][2]string
+	}{
+		{
+			name: "ready",
+			room: "1101",
+			want: [][2]string{},
+		},
+		{
+			name: "nothing to control it",
+			room: "1001D",
+			want: [][2]string{{CheckRole, ""}, {CheckRole, ""}},
+		},
+		{
+			name:   "a display without power states",
+			room:   "1101",
+			change: updateDevice("D1", func(d *structs.Device) { d.PowerStates = []string{} }),
+			want:   [][2]string{{CheckPowerStates, "D1"}},
+		},
+		{
+			name:   "a device with commands but no address",
+			room:   "1101",
+			change: updateDevice("D1", func(d *structs.Device) { d.Address = "" }),
+			want:   [][2]string{{CheckAddress, "D1"}},
+		},
+		{
+			name:   "a device with no commands or address",
+			room:   "1101",
+			change: updateDevice("PC1", func(d *structs.Device) { d.Address = "" }),
+			want:   [][2]string{{CheckAddress, "PC1"}},
+		},
+		{
+			name:   "no touchpanel",
+			room:   "1101",
+			change: updateDevice("CP1", func(d *structs.Device) { d.Roles = []string{"ControlProcessor"} }),
+			want:   [][2]string{{CheckRole, ""}},
+		},
+		{
+			name: "a configuration that doesn't exist",
+			room: "1102",
+			change: func(ctx context.Context, store accessors.Store) error {
+				_, err := store.AddRoom(ctx, "ITB", structs.Room{Name: "1102", ConfigurationID: 9, RoomDesignation: "stage"})
+				return err
+			},
+			want: [][2]string{{CheckRole, ""}, {CheckRole, ""}, {CheckConfiguration, ""}},
+		},
+		{
+			name: "a wiring problem",
+			room: "1101",
+			change: func(ctx context.Context, store accessors.Store) error {
+				return store.DeleteDevicePort(ctx, "ITB", "1101", "D1", "hdmi1")
+			},
+			want: [][2]string{{wiring.CheckUnreachableInput, "PC1"}, {wiring.CheckUnreachableInput, "PC1"}},
+		},
+		{
+			name: "a room that doesn't exist",
+			room: "9999",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newFixtureStore(t)
+
+			if test.change != nil {
+				err := test.change(ctx, store)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			findings, err := Room(ctx, store, "ITB", test.room)
+			if test.want == nil {
+				if accessors.KindOf(err) != accessors.NotFound {
+					t.Errorf("got %v, %v, want a NotFound error", findings, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := [][2]string{}
+			for _, finding := range findings {
+				got = append(got, [2]string{finding.Check, finding.Device})
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package wiring
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// The checks Check runs, as they're named in its findings
+const (
+	CheckDanglingPort     = "wiring-dangling-port"
+	CheckOtherRoom        = "wiring-other-room"
+	CheckUnknownPort      = "wiring-unknown-port"
+	CheckDuplicatePort    = "wiring-duplicate-port"
+	CheckCycle            = "wiring-cycle"
+	CheckUnreachableInput = "wiring-unreachable-input"
+)
+
+// signalRoles pairs each role that brings a signal into a room with the role that should end up
+// showing or playing it
+var signalRoles = [][2]string{
+	{"VideoIn", "VideoOut"},
+	{"AudioIn", "AudioOut"},
+}
+
+// Room is what Check needs to know about a room
+type Room struct {
+	// Name is the room's building-room
+	Name string
+
+	// Devices are the devices in the room, with their roles
+	Devices []structs.Device
+
+	// Ports are the port configurations that touch the room
+	Ports []structs.RoomPort
+
+	// TypePorts lists the names of the ports each host's type has in DeviceTypePorts, by type
+	TypePorts map[string][]string
+}
+
+// Check looks for mistakes in a room's wiring: ports that point at nothing or into another
+// room, ports a host's type doesn't have, physical ports configured more than once, devices
+// wired in a loop, and inputs that can't reach an output. Everything but the last is an error.
+func Check(room Room) []structs.Finding {
+	findings := []structs.Finding{}
+
+	uses := make(map[structs.Port]int)
+	for _, port := range room.Ports {
+		findings = append(findings, room.portProblems(port)...)
+
+		if room.inRoom(port.Host, port.HostRoom) && len(port.Port) > 0 {
+			physical := structs.Port{Host: port.Host, Name: port.Port}
+			uses[physical]++
+
+			// a port the host doesn't have is reported once, however many times it's configured
+			if uses[physical] == 1 && len(port.HostType) > 0 && !contains(room.TypePorts[port.HostType], port.Port) {
+				findings = append(findings, structs.NewFinding(structs.SeverityError, CheckUnknownPort, port.Host, "%s is a %s, which has no port called %s", port.Host, port.HostType, port.Port))
+			}
+		}
+	}
+
+	findings = append(findings, duplicatePorts(uses)...)
+
+	// the rest of the checks follow signals through the room
+	graph := room.Graph()
+
+	findings = append(findings, graph.loopFindings()...)
+	findings = append(findings, graph.unreachableInputs(room.Devices)...)
+
+	return findings
+}
+
+// portProblems checks that a port configuration names a port and devices in the room
+func (room Room) portProblems(port structs.RoomPort) []structs.Finding {
+	findings := []structs.Finding{}
+
+	host := port.Host
+	if len(host) == 0 {
+		host = fmt.Sprintf("(port configuration %d)", port.ID)
+	}
+
+	if len(port.Port) == 0 {
+		findings = append(findings, structs.NewFinding(structs.SeverityError, CheckDanglingPort, port.Host, "port configuration %d on %s has no port", port.ID, host))
+	}
+
+	ends := []struct{ role, name, room string }{
+		{"host", port.Host, port.HostRoom},
+		{"source", port.Source, port.SourceRoom},
+		{"destination", port.Destination, port.DestinationRoom},
+	}
+	for _, end := range ends {
+		switch {
+		case len(end.name) == 0:
+			findings = append(findings, structs.NewFinding(structs.SeverityError, CheckDanglingPort, port.Host, "port %s on %s has no %s device", port.Port, host, end.role))
+		case end.room != room.Name:
+			findings = append(findings, structs.NewFinding(structs.SeverityError, CheckOtherRoom, port.Host, "port %s on %s: its %s %s is in %s, not %s", port.Port, host, end.role, end.name, end.room, room.Name))
+		}
+	}
+
+	return findings
+}
+
+func (room Room) inRoom(device string, deviceRoom string) bool {
+	return len(device) > 0 && deviceRoom == room.Name
+}
+
+// duplicatePorts reports the physical ports, by host and name, that are configured more than once
+func duplicatePorts(uses map[structs.Port]int) []structs.Finding {
+	duplicated := []structs.Port{}
+	for port, count := range uses {
+		if count > 1 {
+			duplicated = append(duplicated, port)
+		}
+	}
+	sortPorts(duplicated)
+
+	findings := []structs.Finding{}
+	for _, port := range duplicated {
+		findings = append(findings, structs.NewFinding(structs.SeverityError, CheckDuplicatePort, port.Host, "port %s on %s is configured %d times", port.Name, port.Host, uses[port]))
+	}
+
+	return findings
+}
+
+// loopFindings reports each set of devices a signal can go around in a loop, including a device
+// wired to itself
+func (g *Graph) loopFindings() []structs.Finding {
+	findings := []structs.Finding{}
+
+	for _, port := range g.allPorts() {
+		if port.Source == port.Destination {
+			findings = append(findings, structs.NewFinding(structs.SeverityError, CheckCycle, port.Source, "%s is wired to itself through port %s on %s", port.Source, port.Name, port.Host))
+		}
+	}
+
+	for _, loop := range g.loops() {
+		findings = append(findings, structs.NewFinding(structs.SeverityError, CheckCycle, loop[0], "%s are wired in a loop", strings.Join(loop, ", ")))
+	}
+
+	return findings
+}
+
+// unreachableInputs reports the input devices whose signal doesn't reach any device that
+// would show or play it
+func (g *Graph) unreachableInputs(devices []structs.Device) []structs.Finding {
+	roles := make(map[string]map[string]bool)
+	for _, device := range devices {
+		roles[device.Name] = make(map[string]bool)
+		for _, role := range device.Roles {
+			roles[device.Name][role] = true
+		}
+	}
+
+	findings := []structs.Finding{}
+	for _, device := range devices {
+		for _, pair := range signalRoles {
+			input, output := pair[0], pair[1]
+			if !roles[device.Name][input] {
+				continue
+			}
+
+			reached := false
+			for name := range g.reachable(device.Name) {
+				if roles[name][output] {
+					reached = true
+					break
+				}
+			}
+
+			if !reached {
+				findings = append(findings, structs.NewFinding(structs.SeverityWarning, CheckUnreachableInput, device.Name, "%s is a %s device but isn't wired to any %s device", device.Name, input, output))
+			}
+		}
+	}
+
+	return findings
+}
+
+// reachable returns the devices a signal from device can get to
+func (g *Graph) reachable(device string) map[string]bool {
+	reached := make(map[string]bool)
+
+	queue := []string{device}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, port := range g.bySource[current] {
+			if !reached[port.Destination] {
+				reached[port.Destination] = true
+				queue = append(queue, port.Destination)
+			}
+		}
+	}
+
+	return reached
+}
+
+// loops returns the strongly connected sets of more than one device, each sorted by name: the
+// devices a signal can go around in a loop. It's Tarjan's algorithm.
+func (g *Graph) loops() [][]string {
+	index := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	stack := []string{}
+	loops := [][]string{}
+
+	var connect func(device string)
+	connect = func(device string) {
+		index[device] = len(index)
+		lowlink[device] = index[device]
+		stack = append(stack, device)
+		onStack[device] = true
+
+		for _, port := range g.bySource[device] {
+			next := port.Destination
+			if _, visited := index[next]; !visited {
+				connect(next)
+				if lowlink[next] < lowlink[device] {
+					lowlink[device] = lowlink[next]
+				}
+			} else if onStack[next] && index[next] < lowlink[device] {
+				lowlink[device] = index[next]
+			}
+		}
+
+		if lowlink[device] != index[device] {
+			return
+		}
+
+		set := []string{}
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			set = append(set, top)
+
+			if top == device {
+				break
+			}
+		}
+
+		if len(set) > 1 {
+			sort.Strings(set)
+			loops = append(loops, set)
+		}
+	}
+
+	devices := []string{}
+	for device := range g.devices {
+		devices = append(devices, device)
+	}
+	sort.Strings(devices)
+
+	for _, device := range devices {
+		if _, visited := index[device]; !visited {
+			connect(device)
+		}
+	}
+
+	sort.Slice(loops, func(i, j int) bool {
+		return loops[i][0] < loops[j][0]
+	})
+
+	return loops
+}
+
+// allPorts returns every port in the graph, sorted
+func (g *Graph) allPorts() []structs.Port {
+	ports := []structs.Port{}
+	for _, fromSource := range g.bySource {
+		ports = append(ports, fromSource...)
+	}
+	sortPorts(ports)
+
+	return ports
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,132 @@
+package wiring
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+const room = "ITB-1101"
+
+// roomPort is a port configuration wholly inside room, hosted by a SonyXBR
+func roomPort(id int, port string, source string, destination string, host string) structs.RoomPort {
+	return structs.RoomPort{
+		ID:              id,
+		Port:            port,
+		Source:          source,
+		SourceRoom:      room,
+		Destination:     destination,
+		DestinationRoom: room,
+		Host:            host,
+		HostRoom:        room,
+		HostType:        "SonyXBR",
+	}
+}
+
+func TestCheck(t *testing.T) {
+	devices := []structs.Device{
+		{Name: "PC1", Roles: []string{"VideoIn", "AudioIn"}},
+		{Name: "D1", Roles: []string{"VideoOut", "AudioOut"}},
+		{Name: "SW1"},
+		{Name: "SW2"},
+	}
+
+	tests := []struct {
+		name  string
+		ports []structs.RoomPort
+		// want lists the findings' checks and devices
+		want [][2]string
+	}{
+		{
+			name:  "a chain",
+			ports: []structs.RoomPort{roomPort(1, "hdmi1", "PC1", "SW1", "SW1"), roomPort(2, "hdmi1", "SW1", "D1", "D1")},
+			want:  [][2]string{},
+		},
+		{
+			name:  "two routes",
+			ports: []structs.RoomPort{roomPort(1, "hdmi1", "PC1", "D1", "D1"), roomPort(2, "hdmi1", "PC1", "D1", "SW1")},
+			want:  [][2]string{},
+		},
+		{
+			name: "a loop",
+			ports: []structs.RoomPort{
+				roomPort(1, "hdmi1", "PC1", "SW1", "SW1"),
+				roomPort(2, "hdmi1", "SW1", "SW2", "SW2"),
+				roomPort(3, "hdmi2", "SW2", "SW1", "SW1"),
+				roomPort(4, "hdmi1", "SW2", "D1", "D1"),
+			},
+			want: [][2]string{{CheckCycle, "SW1"}},
+		},
+		{
+			name:  "wired to itself",
+			ports: []structs.RoomPort{roomPort(1, "hdmi1", "PC1", "D1", "D1"), roomPort(2, "hdmi2", "D1", "D1", "D1")},
+			want:  [][2]string{{CheckCycle, "D1"}},
+		},
+		{
+			name:  "no port",
+			ports: []structs.RoomPort{roomPort(1, "", "PC1", "D1", "D1")},
+			want:  [][2]string{{CheckDanglingPort, "D1"}, {CheckUnreachableInput, "PC1"}, {CheckUnreachableInput, "PC1"}},
+		},
+		{
+			name:  "a source that doesn't exist",
+			ports: []structs.RoomPort{roomPort(1, "hdmi1", "PC1", "D1", "D1"), roomPort(2, "hdmi2", "", "D1", "D1")},
+			want:  [][2]string{{CheckDanglingPort, "D1"}},
+		},
+		{
+			name: "a source in another room",
+			ports: []structs.RoomPort{
+				roomPort(1, "hdmi1", "PC1", "D1", "D1"),
+				func() structs.RoomPort {
+					port := roomPort(2, "hdmi2", "PC1", "D1", "D1")
+					port.SourceRoom = "ITB-1001D"
+					return port
+				}(),
+			},
+			want: [][2]string{{CheckOtherRoom, "D1"}},
+		},
+		{
+			name:  "a port the host's type doesn't have",
+			ports: []structs.RoomPort{roomPort(1, "hdmi3", "PC1", "D1", "D1")},
+			want:  [][2]string{{CheckUnknownPort, "D1"}},
+		},
+		{
+			name:  "a port configured twice",
+			ports: []structs.RoomPort{roomPort(1, "hdmi1", "PC1", "D1", "D1"), roomPort(2, "hdmi1", "SW1", "D1", "D1")},
+			want:  [][2]string{{CheckDuplicatePort, "D1"}},
+		},
+		{
+			name:  "an input wired to nothing that shows it",
+			ports: []structs.RoomPort{roomPort(1, "hdmi1", "PC1", "SW1", "SW1")},
+			want:  [][2]string{{CheckUnreachableInput, "PC1"}, {CheckUnreachableInput, "PC1"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			findings := Check(Room{
+				Name:      room,
+				Devices:   devices,
+				Ports:     test.ports,
+				TypePorts: map[string][]string{"SonyXBR": {"hdmi1", "hdmi2"}},
+			})
+
+			got := [][2]string{}
+			for _, finding := range findings {
+				got = append(got, [2]string{finding.Check, finding.Device})
+
+				want := structs.SeverityError
+				if finding.Check == CheckUnreachableInput {
+					want = structs.SeverityWarning
+				}
+				if finding.Severity != want {
+					t.Errorf("%s is a %s, want a %s", finding.Check, finding.Severity, want)
+				}
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package wiring
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// MaxPaths caps how many routes Paths returns, so a densely wired room can't make a request
+// enumerate an exponential number of them
+const MaxPaths = 1000
+
+// Paths returns the routes from one device to another that don't go through any device twice,
+// fewest hops first. It looks for them a hop count at a time, so if there are more than
+// MaxPaths it keeps the shortest ones and says it left the rest out. It returns an empty list if
+// to can't be reached from from.
+func (g *Graph) Paths(from string, to string) (paths []structs.SignalPath, truncated bool) {
+	paths = []structs.SignalPath{}
+	if from == to {
+		return paths, false
+	}
+
+	// a walk gives up on a device that's too many hops from to for the hops it has left
+	distance := g.distancesTo(to)
+	if _, ok := distance[from]; !ok {
+		return paths, false
+	}
+
+	visited := map[string]bool{from: true}
+	hops := []structs.Port{}
+
+	// walk adds the routes on from hops that get from device to to in exactly left more
+	var walk func(device string, left int)
+	walk = func(device string, left int) {
+		for _, port := range g.bySource[device] {
+			if truncated {
+				return
+			}
+
+			// a switcher routing between two other devices is on the path too
+			switcher := port.Host != port.Source && port.Host != port.Destination
+			if visited[port.Destination] || (switcher && (visited[port.Host] || port.Host == to)) {
+				continue
+			}
+			if d, ok := distance[port.Destination]; !ok || d > left-1 {
+				continue
+			}
+
+			hops = append(hops, port)
+			if switcher {
+				visited[port.Host] = true
+			}
+
+			switch {
+			case port.Destination == to && left == 1:
+				if len(paths) == MaxPaths {
+					truncated = true
+				} else {
+					paths = append(paths, newPath(from, to, hops))
+				}
+			case port.Destination != to:
+				visited[port.Destination] = true
+				walk(port.Destination, left-1)
+				visited[port.Destination] = false
+			}
+
+			if switcher {
+				visited[port.Host] = false
+			}
+			hops = hops[:len(hops)-1]
+		}
+	}
+
+	// every hop goes to a device the route hasn't been through, so none has more hops than the
+	// room has other devices
+	for length := distance[from]; length < len(g.devices) && !truncated; length++ {
+		found := len(paths)
+		walk(from, length)
+
+		sort.SliceStable(paths[found:], func(i, j int) bool {
+			return strings.Join(paths[found+i].Devices, ",") < strings.Join(paths[found+j].Devices, ",")
+		})
+	}
+
+	return paths, truncated
+}
+
+// distancesTo returns how few hops each device that can reach to needs to get there
+func (g *Graph) distancesTo(to string) map[string]int {
+	// sources lists the devices wired straight to each device
+	sources := make(map[string][]string)
+	for source, ports := range g.bySource {
+		for _, port := range ports {
+			sources[port.Destination] = append(sources[port.Destination], source)
+		}
+	}
+
+	distance := map[string]int{to: 0}
+	queue := []string{to}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, source := range sources[current] {
+			if _, ok := distance[source]; !ok {
+				distance[source] = distance[current] + 1
+				queue = append(queue, source)
+			}
+		}
+	}
+
+	return distance
+}
+
+// newPath copies hops into a SignalPath, listing the devices the signal passes through
+func newPath(from string, to string, hops []structs.Port) structs.SignalPath {
+	path := structs.SignalPath{
+		From:    from,
+		To:      to,
+		Devices: []string{from},
+		Hops:    append([]structs.Port(nil), hops...),
+	}
+
+	for _, hop := range hops {
+		if hop.Host != hop.Source && hop.Host != hop.Destination {
+			path.Devices = append(path.Devices, hop.Host)
+		}
+		path.Devices = append(path.Devices, hop.Destination)
+	}
+
+	return path
+}
@@ -0,0 +1,154 @@
+package wiring
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// wire is a port on destination that selects source
+func wire(source string, destination string) structs.Port {
+	return structs.Port{Source: source, Name: "in", Destination: destination, Host: destination}
+}
+
+// routes lists each path's devices, joined with arrows
+func routes(paths []structs.SignalPath) []string {
+	toReturn := []string{}
+	for _, path := range paths {
+		toReturn = append(toReturn, strings.Join(path.Devices, "->"))
+	}
+
+	return toReturn
+}
+
+func TestPaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		ports []structs.Port
+		from  string
+		to    string
+		want  []string
+	}{
+		{
+			name:  "a chain",
+			ports: []structs.Port{wire("PC1", "SW1"), wire("SW1", "D1")},
+			from:  "PC1",
+			to:    "D1",
+			want:  []string{"PC1->SW1->D1"},
+		},
+		{
+			name:  "several routes, fewest hops first",
+			ports: []structs.Port{wire("PC1", "SW2"), wire("SW2", "SW1"), wire("SW1", "D1"), wire("PC1", "SW1"), wire("PC1", "D1")},
+			from:  "PC1",
+			to:    "D1",
+			want:  []string{"PC1->D1", "PC1->SW1->D1", "PC1->SW2->SW1->D1"},
+		},
+		{
+			name: "through a switcher",
+			ports: []structs.Port{
+				{Source: "PC1", Name: "hdmi1", Destination: "D1", Host: "SW1"},
+				{Source: "PC1", Name: "hdmi2", Destination: "D1", Host: "SW2"},
+			},
+			from: "PC1",
+			to:   "D1",
+			want: []string{"PC1->SW1->D1", "PC1->SW2->D1"},
+		},
+		{
+			name:  "around a loop",
+			ports: []structs.Port{wire("PC1", "SW1"), wire("SW1", "SW2"), wire("SW2", "SW1"), wire("SW2", "D1")},
+			from:  "PC1",
+			to:    "D1",
+			want:  []string{"PC1->SW1->SW2->D1"},
+		},
+		{
+			name:  "past a device wired to itself",
+			ports: []structs.Port{wire("PC1", "SW1"), wire("SW1", "SW1"), wire("SW1", "D1")},
+			from:  "PC1",
+			to:    "D1",
+			want:  []string{"PC1->SW1->D1"},
+		},
+		{
+			name:  "against the signal",
+			ports: []structs.Port{wire("PC1", "D1")},
+			from:  "D1",
+			to:    "PC1",
+			want:  []string{},
+		},
+		{
+			name:  "unwired",
+			ports: []structs.Port{wire("PC1", "D1")},
+			from:  "PC2",
+			to:    "D1",
+			want:  []string{},
+		},
+		{
+			name:  "to itself",
+			ports: []structs.Port{wire("PC1", "PC1")},
+			from:  "PC1",
+			to:    "PC1",
+			want:  []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			graph := newGraph([]string{"PC1", "PC2", "D1"}, test.ports)
+
+			paths, truncated := graph.Paths(test.from, test.to)
+			if truncated {
+				t.Errorf("truncated %d paths", len(paths))
+			}
+			if got := routes(paths); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+// TestPathsTruncated wires A to Z directly, through Y, and through four fully connected layers
+// of six devices, which is 6^4 more routes than MaxPaths allows. The layers' ports sort before
+// the others, so a search that went deep first would fill up on them.
+func TestPathsTruncated(t *testing.T) {
+	const width = 6
+	layers := []string{"B", "C", "D", "E"}
+
+	ports := []structs.Port{wire("A", "Z"), wire("A", "Y"), wire("Y", "Z")}
+	previous := []string{"A"}
+	for _, layer := range layers {
+		current := []string{}
+		for i := 1; i <= width; i++ {
+			current = append(current, fmt.Sprintf("%s%d", layer, i))
+		}
+
+		for _, source := range previous {
+			for _, destination := range current {
+				ports = append(ports, wire(source, destination))
+			}
+		}
+		previous = current
+	}
+	for _, source := range previous {
+		ports = append(ports, wire(source, "Z"))
+	}
+
+	paths, truncated := newGraph([]string{}, ports).Paths("A", "Z")
+	if !truncated {
+		t.Errorf("not truncated")
+	}
+	if len(paths) != MaxPaths {
+		t.Fatalf("got %d paths, want %d", len(paths), MaxPaths)
+	}
+
+	got := routes(paths[:2])
+	if want := []string{"A->Z", "A->Y->Z"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("the shortest routes are %q, want %q", got, want)
+	}
+	for _, path := range paths[2:] {
+		if len(path.Hops) != len(layers)+1 {
+			t.Fatalf("%s has %d hops, want %d", strings.Join(path.Devices, "->"), len(path.Hops), len(layers)+1)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Package wiring works out how signals move through a room from its PortConfiguration.
+
+Every configured port is an edge in a directed graph: the signal goes from the port's
+source device to its destination when the port's host selects it. The host is usually
+the destination (a display picking an input) but a switcher hosts the ports it routes
+between other devices, so a route from a computer to a display can cross several hosts,
+each of which has to be told which port to use.
+*/
+package wiring
+
+import (
+	"sort"
+
+	"github.com/byuoitav/configuration-database-microservice/structs"
+)
+
+// Graph is the wiring of one room, built from the ports configured on its devices
+type Graph struct {
+	bySource map[string][]structs.Port
+	devices  map[string]bool
+}
+
+// Graph builds a room's graph from its devices and the ports wholly inside it, which are the
+// only ones that carry a signal through it. Devices are told apart by name, which is unique
+// within a room.
+func (room Room) Graph() *Graph {
+	names := []string{}
+	for _, device := range room.Devices {
+		names = append(names, device.Name)
+	}
+
+	ports := []structs.Port{}
+	for _, port := range room.Ports {
+		if len(port.Port) > 0 && room.inRoom(port.Host, port.HostRoom) && room.inRoom(port.Source, port.SourceRoom) && room.inRoom(port.Destination, port.DestinationRoom) {
+			ports = append(ports, structs.Port{Source: port.Source, Name: port.Port, Destination: port.Destination, Host: port.Host})
+		}
+	}
+
+	return newGraph(names, ports)
+}
+
+func newGraph(devices []string, ports []structs.Port) *Graph {
+	g := &Graph{bySource: make(map[string][]structs.Port), devices: make(map[string]bool)}
+
+	for _, name := range devices {
+		g.devices[name] = true
+	}
+
+	seen := make(map[structs.Port]bool)
+	for _, port := range ports {
+		if seen[port] {
+			continue
+		}
+		seen[port] = true
+
+		g.bySource[port.Source] = append(g.bySource[port.Source], port)
+		g.devices[port.Source] = true
+		g.devices[port.Destination] = true
+		g.devices[port.Host] = true
+	}
+
+	for _, ports := range g.bySource {
+		sortPorts(ports)
+	}
+
+	return g
+}
+
+// Has says whether the room has a device called name, or one of its ports refers to one
+func (g *Graph) Has(name string) bool {
+	return g.devices[name]
+}
+
+func sortPorts(ports []structs.Port) {
+	sort.Slice(ports, func(i, j int) bool {
+		a, b := ports[i], ports[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Destination != b.Destination {
+			return a.Destination < b.Destination
+		}
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		return a.Name < b.Name
+	})
+}